@@ -0,0 +1,73 @@
+package todo
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// Actions recorded in the activity log.
+const (
+	ActionCreate   = "create"
+	ActionUpdate   = "update"
+	ActionComplete = "complete"
+	ActionMove     = "move"
+	ActionDelete   = "delete"
+	ActionRestore  = "restore"
+)
+
+const DefaultActivityLimit = 50
+
+// Activity is one change of a list or of one of its items. ItemId is empty
+// for changes of the list itself and for items purged from the trash.
+type Activity struct {
+	Id        int       `json:"id" db:"id"`
+	ActorId   *int      `json:"actor_id" db:"actor_id"`
+	ListId    int       `json:"list_id" db:"list_id"`
+	ItemId    *int      `json:"item_id,omitempty" db:"item_id"`
+	Action    string    `json:"action" db:"action"`
+	Changes   Changes   `json:"changes,omitempty" db:"changes"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// Change is the value of a field before and after a change.
+type Change struct {
+	Old interface{} `json:"old"`
+	New interface{} `json:"new"`
+}
+
+// Changes maps field names to their changes, it is stored as jsonb.
+type Changes map[string]Change
+
+func (c Changes) Value() (driver.Value, error) {
+	if c == nil {
+		return "{}", nil
+	}
+
+	data, err := json.Marshal(c)
+	return string(data), err
+}
+
+func (c *Changes) Scan(src interface{}) error {
+	data, ok := src.([]byte)
+	if !ok {
+		return errors.New("changes must be jsonb")
+	}
+
+	return json.Unmarshal(data, c)
+}
+
+// Page addresses one page of a log, Cursor is taken from the previous page.
+type Page struct {
+	Cursor string
+	Limit  int
+}
+
+func (p Page) Validate() error {
+	if p.Limit < 1 || p.Limit > MaxItemsLimit {
+		return NewError(ErrInvalidInput, "invalid_filter", "limit is out of range")
+	}
+
+	return nil
+}
@@ -0,0 +1,11 @@
+package todo
+
+// Agenda is what is on the user's plate across all of their lists. Only open
+// items are included and every item is in one group at most.
+type Agenda struct {
+	Overdue  []TodoItem `json:"overdue"`
+	Today    []TodoItem `json:"today"`
+	ThisWeek []TodoItem `json:"this_week"`
+	// Undated are high and urgent priority items without a deadline
+	Undated []TodoItem `json:"undated"`
+}
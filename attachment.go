@@ -0,0 +1,16 @@
+package todo
+
+import "time"
+
+// Attachment is the metadata of a file attached to an item, the contents
+// are kept in the file storage under StorageKey.
+type Attachment struct {
+	Id          int       `json:"id" db:"id"`
+	ItemId      int       `json:"item_id" db:"item_id"`
+	UserId      int       `json:"user_id" db:"user_id"`
+	FileName    string    `json:"file_name" db:"file_name"`
+	ContentType string    `json:"content_type" db:"content_type"`
+	Size        int64     `json:"size" db:"size"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+	StorageKey  string    `json:"-" db:"storage_key"`
+}
@@ -0,0 +1,71 @@
+package todo
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Calendar is a list as a CalDAV calendar collection.
+type Calendar struct {
+	TodoList
+	// CTag changes whenever an object of the calendar is added, changed or removed
+	CTag string `db:"ctag"`
+}
+
+// CalendarObject is an item as a VTODO resource of the CalDAV calendar of
+// its list.
+type CalendarObject struct {
+	TodoItem
+	// Name is the last segment of the resource URL
+	Name string `db:"name"`
+	// UID is the UID of the VTODO, empty for items that were not created over CalDAV
+	UID string `db:"uid"`
+	// Data is the object as an iCalendar file
+	Data []byte `db:"-"`
+}
+
+// ETag changes with every change of the item. The id keeps the tags of
+// different items apart when a name is reused after a delete.
+func (o CalendarObject) ETag() string {
+	return fmt.Sprintf(`"%d-%d"`, o.Id, o.Version)
+}
+
+// InvalidCalendarObject is the error of a resource a client cannot store in
+// a calendar.
+func InvalidCalendarObject(message string) *Error {
+	return NewError(ErrInvalidInput, "invalid_calendar_object", message)
+}
+
+// Precondition holds the If-Match and If-None-Match headers of a request
+// that changes a resource.
+type Precondition struct {
+	IfMatch     string
+	IfNoneMatch string
+}
+
+// Check tests the precondition against the current entity tag of the
+// resource, an empty one for a resource that does not exist.
+func (p Precondition) Check(etag string) error {
+	if p.IfMatch != "" && (etag == "" || !matchETag(p.IfMatch, etag)) {
+		return ErrPreconditionFailed
+	}
+
+	if p.IfNoneMatch != "" && etag != "" && matchETag(p.IfNoneMatch, etag) {
+		return ErrPreconditionFailed
+	}
+
+	return nil
+}
+
+// matchETag tells whether a list of entity tags from a header contains etag,
+// "*" matches any. Weak tags never match, changes need the strong comparison.
+func matchETag(header, etag string) bool {
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || tag == etag {
+			return true
+		}
+	}
+
+	return false
+}
@@ -2,6 +2,9 @@ package main
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
@@ -12,6 +15,7 @@ import (
 	"github.com/lypolix/todo-app/pkg/handler"
 	"github.com/lypolix/todo-app/pkg/repository"
 	"github.com/lypolix/todo-app/pkg/service"
+	"github.com/lypolix/todo-app/pkg/storage"
 	"github.com/lypolix/todo-app/pkg/wsserver"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
@@ -30,14 +34,6 @@ import (
 
 
 func main(){
-
-	server := wsserver.NewWsServer(":8000")
-	logrus.Info("Started ws server")
-	if err := server.Start(); err != nil {
-		logrus.Errorf("Error with ws server: %v", err)
-	}
-  
-
 	logrus.SetFormatter(new(logrus.JSONFormatter))
 	if err := initConfig(); err != nil {
 		logrus.Fatalf ("error initializing configs: %s", err.Error())
@@ -60,29 +56,87 @@ func main(){
 		logrus.Fatalf("failed to ititializedb: %s", err.Error())
 	}
 
+	keys, err := loadSigningKeys()
+	if err != nil {
+		logrus.Fatalf("failed to load jwt keys: %s", err.Error())
+	}
+
+	files, err := storage.NewLocalStorage(viper.GetString("attachments.dir"))
+	if err != nil {
+		logrus.Fatalf("failed to initialize file storage: %s", err.Error())
+	}
+
 	repos := repository.NewRepository(db)
-	services := service.NewService(repos)
-	handlers := handler.NewHandler(services)
+	services := service.NewService(repos, files, service.Config{
+		Auth: service.AuthConfig{
+			TokenTTL: viper.GetDuration("auth.token_ttl"),
+			RefreshTokenTTL: viper.GetDuration("auth.refresh_token_ttl"),
+			Keys: keys,
+		},
+		Trash: service.TrashConfig{
+			Retention: viper.GetDuration("trash.retention"),
+		},
+		Attachments: service.AttachmentConfig{
+			MaxSize: int64(viper.GetSizeInBytes("attachments.max_size")),
+			Quota: int64(viper.GetSizeInBytes("attachments.quota")),
+		},
+	})
+	handlers := handler.NewHandler(services, handler.Config{
+		QueryTimeout: viper.GetDuration("db.query_timeout"),
+		MaxUploadSize: int64(viper.GetSizeInBytes("attachments.max_size")),
+		UploadTimeout: viper.GetDuration("attachments.upload_timeout"),
+	})
 
 	srv := new(todo.Server)
+	wsSrv := wsserver.NewWsServer(":"+viper.GetString("ws.port"), services, viper.GetDuration("db.query_timeout"))
+
+	serverErrors := make(chan error, 2)
+	go func () {
+		if err := srv.Run(viper.GetString("port"), handlers.InitRoutes()); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			serverErrors <- fmt.Errorf("http server: %w", err)
+		}
+	}()
+
 	go func () {
-		if err := srv.Run(viper.GetString("port"), handlers.InitRoutes()); err != nil {
-			logrus.Fatalf("error occured while running http server: %s", err.Error())
+		if err := wsSrv.Start(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			serverErrors <- fmt.Errorf("ws server: %w", err)
 		}
 	}()
 
+	purgerCtx, stopPurger := context.WithCancel(context.Background())
+	purgerDone := make(chan struct{})
+	go func () {
+		service.RunPurger(purgerCtx, viper.GetDuration("trash.purge_interval"), services.Trash.Purge, services.Attachment.PurgeDetached)
+		close(purgerDone)
+	}()
+
 	logrus.Print("TodoApp Started")
 
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGTERM, syscall.SIGINT)
-	<- quit
+	select {
+	case <- quit:
+	case err := <- serverErrors:
+		logrus.Errorf("error occured while running server: %s", err.Error())
+	}
 
 	logrus.Print("TodoApp Shutting Down")
 
-	if err := srv.Shutdown(context.Background()); err != nil {
+	ctx, cancel := context.WithTimeout(context.Background(), viper.GetDuration("shutdown_timeout"))
+	defer cancel()
+
+	errs := todo.ShutdownAll(ctx, srv, wsSrv)
+	if err := errs[0]; err != nil {
 		logrus.Errorf("error occured on server shutting down: %s", err.Error())
 	}
 
+	if err := errs[1]; err != nil {
+		logrus.Errorf("error occured on ws server shutting down: %s", err.Error())
+	}
+
+	stopPurger()
+	<- purgerDone
+
 	if err := db.Close(); err != nil {
 		logrus.Errorf("error occured on db connection close: %s", err.Error())
 	}
@@ -95,3 +149,54 @@ func initConfig() error{
 	viper.SetConfigName("config")
 	return viper.ReadInConfig()
 }
+
+type jwtKeyConfig struct {
+	Id string `mapstructure:"id"`
+	Algorithm string `mapstructure:"algorithm"`
+	SecretEnv string `mapstructure:"secret_env"`
+	PrivateKeyEnv string `mapstructure:"private_key_env"`
+	PrivateKeyFile string `mapstructure:"private_key_file"`
+	PublicKeyFile string `mapstructure:"public_key_file"`
+}
+
+// loadSigningKeys reads auth.keys: secrets and PEM keys come either from
+// environment variables or from files, never from the config itself.
+func loadSigningKeys() (*service.KeySet, error) {
+	var configs []jwtKeyConfig
+	if err := viper.UnmarshalKey("auth.keys", &configs); err != nil {
+		return nil, err
+	}
+
+	keys := make([]service.KeyConfig, 0, len(configs))
+	for _, cfg := range configs {
+		key := service.KeyConfig{Id: cfg.Id, Algorithm: cfg.Algorithm}
+
+		if cfg.SecretEnv != "" {
+			key.Secret = []byte(os.Getenv(cfg.SecretEnv))
+		}
+
+		if cfg.PrivateKeyEnv != "" {
+			key.PrivateKey = []byte(os.Getenv(cfg.PrivateKeyEnv))
+		}
+
+		if cfg.PrivateKeyFile != "" {
+			data, err := os.ReadFile(cfg.PrivateKeyFile)
+			if err != nil {
+				return nil, err
+			}
+			key.PrivateKey = data
+		}
+
+		if cfg.PublicKeyFile != "" {
+			data, err := os.ReadFile(cfg.PublicKeyFile)
+			if err != nil {
+				return nil, err
+			}
+			key.PublicKey = data
+		}
+
+		keys = append(keys, key)
+	}
+
+	return service.NewKeySet(viper.GetString("auth.signing_key"), keys)
+}
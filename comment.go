@@ -0,0 +1,43 @@
+package todo
+
+import (
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const MaxCommentLength = 10000
+
+// Comment is a message in the discussion of an item. Replies point to the
+// comment they answer with ParentId, which is cleared when that comment is
+// deleted.
+type Comment struct {
+	Id        int        `json:"id" db:"id"`
+	ItemId    int        `json:"item_id" db:"item_id"`
+	ParentId  *int       `json:"parent_id,omitempty" db:"parent_id"`
+	AuthorId  int        `json:"author_id" db:"author_id"`
+	Author    string     `json:"author" db:"author"`
+	Body      string     `json:"body" db:"body" binding:"required"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+	EditedAt  *time.Time `json:"edited_at,omitempty" db:"edited_at"`
+}
+
+func (c Comment) Validate() error {
+	return validateCommentBody(c.Body)
+}
+
+type UpdateCommentInput struct {
+	Body string `json:"body" binding:"required"`
+}
+
+func (i UpdateCommentInput) Validate() error {
+	return validateCommentBody(i.Body)
+}
+
+func validateCommentBody(body string) error {
+	if strings.TrimSpace(body) == "" || utf8.RuneCountInString(body) > MaxCommentLength {
+		return ErrInvalidComment
+	}
+
+	return nil
+}
@@ -0,0 +1,96 @@
+package todo
+
+import "errors"
+
+// Kinds of domain errors. Every *Error wraps one of them, which is what the
+// transport layer uses to pick a status code.
+var (
+	ErrNotFound     = errors.New("not found")
+	ErrConflict     = errors.New("conflict")
+	ErrInvalidInput = errors.New("invalid input")
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrForbidden    = errors.New("forbidden")
+	ErrTooLarge     = errors.New("too large")
+	ErrPrecondition = errors.New("precondition failed")
+)
+
+// Error is a domain error with a stable code clients can rely on.
+type Error struct {
+	Kind    error
+	Code    string
+	Message string
+}
+
+func NewError(kind error, code, message string) *Error {
+	return &Error{Kind: kind, Code: code, Message: message}
+}
+
+// InvalidInput wraps a validation failure of a request. Domain errors, e.g.
+// returned while decoding the request, are kept as they are.
+func InvalidInput(err error) *Error {
+	var e *Error
+	if errors.As(err, &e) {
+		return e
+	}
+	return NewError(ErrInvalidInput, "invalid_input", err.Error())
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Kind
+}
+
+var (
+	ErrListNotFound       = NewError(ErrNotFound, "list_not_found", "list not found")
+	ErrItemNotFound       = NewError(ErrNotFound, "item_not_found", "item not found")
+	ErrUserNotFound       = NewError(ErrNotFound, "user_not_found", "user not found")
+	ErrMemberNotFound     = NewError(ErrNotFound, "member_not_found", "user is not a member of the list")
+	ErrSubtaskNotFound    = NewError(ErrNotFound, "subtask_not_found", "subtask not found")
+	ErrLabelNotFound      = NewError(ErrNotFound, "label_not_found", "label not found")
+	ErrCommentNotFound    = NewError(ErrNotFound, "comment_not_found", "comment not found")
+	ErrAttachmentNotFound = NewError(ErrNotFound, "attachment_not_found", "attachment not found")
+	ErrCalendarNotFound   = NewError(ErrNotFound, "calendar_not_found", "calendar not found")
+	ErrObjectNotFound     = NewError(ErrNotFound, "object_not_found", "calendar object not found")
+
+	ErrUsernameTaken = NewError(ErrConflict, "username_taken", "username is already taken")
+	ErrAlreadyMember = NewError(ErrConflict, "already_member", "user is already a member of the list")
+	ErrLabelExists   = NewError(ErrConflict, "label_exists", "label with this name already exists")
+	ErrObjectExists  = NewError(ErrConflict, "object_exists", "calendar object with this name already exists")
+
+	ErrDuplicateOccurrence = NewError(ErrConflict, "duplicate_occurrence", "series already has an occurrence at this time")
+
+	ErrInvalidCredentials  = NewError(ErrUnauthorized, "invalid_credentials", "invalid username or password")
+	ErrInvalidToken        = NewError(ErrUnauthorized, "invalid_token", "invalid access token")
+	ErrInvalidRefreshToken = NewError(ErrUnauthorized, "invalid_refresh_token", "invalid refresh token")
+	ErrTokenRevoked        = NewError(ErrUnauthorized, "token_revoked", "token has been revoked")
+
+	ErrListForbidden    = NewError(ErrForbidden, "list_forbidden", "not enough permissions for this list")
+	ErrCommentForbidden = NewError(ErrForbidden, "comment_forbidden", "only the author can change a comment")
+
+	ErrFileTooLarge  = NewError(ErrTooLarge, "file_too_large", "file is larger than allowed")
+	ErrQuotaExceeded = NewError(ErrTooLarge, "quota_exceeded", "not enough space left for this file")
+
+	ErrPreconditionFailed = NewError(ErrPrecondition, "precondition_failed", "resource does not match the If-Match or If-None-Match header")
+
+	ErrEmptyUpdate          = NewError(ErrInvalidInput, "empty_update", "update structure has no values")
+	ErrInvalidCursor        = NewError(ErrInvalidInput, "invalid_cursor", "invalid cursor")
+	ErrInvalidRole          = NewError(ErrInvalidInput, "invalid_role", "role must be editor or viewer")
+	ErrInvalidScope         = NewError(ErrInvalidInput, "invalid_scope", "scope must be this or future")
+	ErrInvalidPosition      = NewError(ErrInvalidInput, "invalid_position", "position must not be negative")
+	ErrInvalidColor         = NewError(ErrInvalidInput, "invalid_color", "color must be a hex color like #1e90ff")
+	ErrInvalidLabelName     = NewError(ErrInvalidInput, "invalid_label_name", "label name must be 1 to 64 characters long")
+	ErrInvalidAnchor        = NewError(ErrInvalidInput, "invalid_anchor", "before and after must be other items of the target list in that order")
+	ErrInvalidComment       = NewError(ErrInvalidInput, "invalid_comment", "comment must be 1 to 10000 characters long")
+	ErrInvalidParentComment = NewError(ErrInvalidInput, "invalid_parent", "parent must be a comment of the same item")
+	ErrInvalidTrashType     = NewError(ErrInvalidInput, "invalid_trash_type", "type must be lists or items")
+	ErrInvalidPriority      = NewError(ErrInvalidInput, "invalid_priority", "priority must be one of none, low, medium, high, urgent")
+	ErrInvalidFormat        = NewError(ErrInvalidInput, "invalid_format", "format must be json, csv or md")
+	ErrInvalidSearchQuery   = NewError(ErrInvalidInput, "invalid_query", "query must contain a word and be at most 256 characters long")
+	ErrInvalidContentType   = NewError(ErrInvalidInput, "invalid_content_type", "content type must be at most 255 characters long")
+
+	ErrRRuleWithoutDeadline = NewError(ErrInvalidInput, "invalid_rrule", "a recurring item needs a deadline")
+	ErrSeriesRuleScope      = NewError(ErrInvalidInput, "invalid_scope", "the rule of a series can only be changed for future occurrences")
+)
@@ -0,0 +1,114 @@
+package todo
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Formats lists are exported to and imported from.
+const (
+	FormatJSON     = "json"
+	FormatCSV      = "csv"
+	FormatMarkdown = "md"
+
+	MaxImportItems = 1000
+	// maxTextLength is the size of the title and description columns
+	maxTextLength = 255
+)
+
+// ListExport is a list with its items in a form that does not depend on
+// this server, e.g. without ids.
+type ListExport struct {
+	Title       string       `json:"title"`
+	Description string       `json:"description,omitempty"`
+	Items       []ExportItem `json:"items"`
+}
+
+type ExportItem struct {
+	Title       string     `json:"title"`
+	Description string     `json:"description,omitempty"`
+	Done        bool       `json:"done"`
+	Deadline    *time.Time `json:"deadline,omitempty"`
+	Priority    Priority   `json:"priority"`
+}
+
+// Validate checks what the database would reject, so an import fails with a
+// message pointing at the item instead of a database error.
+func (l ListExport) Validate() error {
+	if err := validateText("list title", l.Title, true); err != nil {
+		return err
+	}
+
+	if err := validateText("list description", l.Description, false); err != nil {
+		return err
+	}
+
+	return ValidateExportItems(l.Items)
+}
+
+// ValidateExportItems checks items to be imported into a list.
+func ValidateExportItems(items []ExportItem) error {
+	if len(items) > MaxImportItems {
+		return InvalidImport(fmt.Sprintf("at most %d items can be imported at once", MaxImportItems))
+	}
+
+	for i, item := range items {
+		if err := validateText(fmt.Sprintf("item %d: title", i+1), item.Title, true); err != nil {
+			return err
+		}
+
+		if err := validateText(fmt.Sprintf("item %d: description", i+1), item.Description, false); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func validateText(name, text string, required bool) error {
+	if required && strings.TrimSpace(text) == "" {
+		return InvalidImport(name + " is required")
+	}
+
+	if utf8.RuneCountInString(text) > maxTextLength {
+		return InvalidImport(fmt.Sprintf("%s is longer than %d characters", name, maxTextLength))
+	}
+
+	return nil
+}
+
+// ImportInput tells how to read an import. Title, when set, replaces the
+// title found in the data, CSV has no place for one.
+type ImportInput struct {
+	Format string
+	Title  string
+	DryRun bool
+}
+
+func (i ImportInput) Validate() error {
+	return ValidateFormat(i.Format)
+}
+
+func ValidateFormat(format string) error {
+	switch format {
+	case FormatJSON, FormatCSV, FormatMarkdown:
+		return nil
+	default:
+		return ErrInvalidFormat
+	}
+}
+
+// ImportResult is what was imported, or with DryRun what would have been.
+// ListId is only set when the list was created.
+type ImportResult struct {
+	ListId int        `json:"list_id,omitempty"`
+	DryRun bool       `json:"dry_run"`
+	List   ListExport `json:"list"`
+}
+
+// InvalidImport is the error of data that cannot be imported.
+func InvalidImport(message string) *Error {
+	return NewError(ErrInvalidInput, "invalid_import", message)
+}
@@ -0,0 +1,69 @@
+package todo
+
+import "time"
+
+const (
+	SortByDeadline  = "deadline"
+	SortByCreatedAt = "created_at"
+	SortByTitle     = "title"
+	SortByPriority  = "priority"
+	SortByPosition  = "position"
+
+	OrderAsc  = "asc"
+	OrderDesc = "desc"
+
+	DefaultItemsLimit = 100
+	MaxItemsLimit     = 500
+
+	TagMatchAny = "any"
+	TagMatchAll = "all"
+
+	MaxFilterTags = 20
+)
+
+// ItemFilter narrows and orders the items of a list. Pages are addressed by
+// an opaque Cursor taken from the previous page.
+type ItemFilter struct {
+	Done      *bool
+	DueBefore *time.Time
+	DueAfter  *time.Time
+	Query     string
+	// Tags are label names, items match when they carry any or, with
+	// TagMatchAll, all of them
+	Tags     []string
+	TagMatch string
+	Sort     string
+	Order    string
+	Cursor   string
+	Limit    int
+}
+
+func (f ItemFilter) Validate() error {
+	switch f.Sort {
+	case SortByPosition, SortByDeadline, SortByCreatedAt, SortByTitle, SortByPriority:
+	default:
+		return NewError(ErrInvalidInput, "invalid_filter", "sort must be one of position, deadline, created_at, title, priority")
+	}
+
+	if f.Order != OrderAsc && f.Order != OrderDesc {
+		return NewError(ErrInvalidInput, "invalid_filter", "order must be asc or desc")
+	}
+
+	if f.Limit < 1 || f.Limit > MaxItemsLimit {
+		return NewError(ErrInvalidInput, "invalid_filter", "limit is out of range")
+	}
+
+	if f.TagMatch != TagMatchAny && f.TagMatch != TagMatchAll {
+		return NewError(ErrInvalidInput, "invalid_filter", "match must be any or all")
+	}
+
+	if len(f.Tags) > MaxFilterTags {
+		return NewError(ErrInvalidInput, "invalid_filter", "too many tags")
+	}
+
+	if f.DueBefore != nil && f.DueAfter != nil && !f.DueAfter.Before(*f.DueBefore) {
+		return NewError(ErrInvalidInput, "invalid_filter", "due_after must be before due_before")
+	}
+
+	return nil
+}
@@ -0,0 +1,60 @@
+package todo
+
+import (
+	"regexp"
+	"strings"
+)
+
+const DefaultLabelColor = "#808080"
+
+var labelColor = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+// Label is a personal tag of a user that can be put on any item the user can see.
+type Label struct {
+	Id    int    `json:"id" db:"id"`
+	Name  string `json:"name" db:"name" binding:"required"`
+	Color string `json:"color" db:"color"`
+}
+
+func (l Label) Validate() error {
+	if err := validateLabelName(l.Name); err != nil {
+		return err
+	}
+
+	if l.Color != "" && !labelColor.MatchString(l.Color) {
+		return ErrInvalidColor
+	}
+
+	return nil
+}
+
+type UpdateLabelInput struct {
+	Name  *string `json:"name"`
+	Color *string `json:"color"`
+}
+
+func (i UpdateLabelInput) Validate() error {
+	if i.Name == nil && i.Color == nil {
+		return ErrEmptyUpdate
+	}
+
+	if i.Name != nil {
+		if err := validateLabelName(*i.Name); err != nil {
+			return err
+		}
+	}
+
+	if i.Color != nil && !labelColor.MatchString(*i.Color) {
+		return ErrInvalidColor
+	}
+
+	return nil
+}
+
+func validateLabelName(name string) error {
+	if strings.TrimSpace(name) == "" || len(name) > 64 {
+		return ErrInvalidLabelName
+	}
+
+	return nil
+}
@@ -0,0 +1,45 @@
+package todo
+
+const (
+	RoleOwner  = "owner"
+	RoleEditor = "editor"
+	RoleViewer = "viewer"
+)
+
+type ListMember struct {
+	UserId   int    `json:"user_id" db:"user_id"`
+	Name     string `json:"name" db:"name"`
+	Username string `json:"username" db:"username"`
+	Role     string `json:"role" db:"role"`
+}
+
+type AddMemberInput struct {
+	Username string `json:"username" binding:"required"`
+	Role     string `json:"role" binding:"required"`
+}
+
+func (i AddMemberInput) Validate() error {
+	return validateMemberRole(i.Role)
+}
+
+type UpdateMemberInput struct {
+	Role string `json:"role" binding:"required"`
+}
+
+func (i UpdateMemberInput) Validate() error {
+	return validateMemberRole(i.Role)
+}
+
+type TransferOwnershipInput struct {
+	UserId int `json:"user_id" binding:"required"`
+}
+
+// validateMemberRole allows the roles that can be granted directly;
+// ownership only changes hands through a transfer.
+func validateMemberRole(role string) error {
+	if role != RoleEditor && role != RoleViewer {
+		return ErrInvalidRole
+	}
+
+	return nil
+}
@@ -0,0 +1,92 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lypolix/todo-app"
+)
+
+type getActivityResponse struct {
+	Data       []todo.Activity `json:"data"`
+	NextCursor string          `json:"next_cursor,omitempty"`
+}
+
+func (h *Handler) getListActivity(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		return
+	}
+
+	listId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		newErrorResponse(c, invalidParam("list id"))
+		return
+	}
+
+	page, err := parsePage(c)
+	if err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	activity, nextCursor, err := h.services.Activity.GetByList(c.Request.Context(), userId, listId, page)
+	if err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, getActivityResponse{
+		Data:       activity,
+		NextCursor: nextCursor,
+	})
+}
+
+func (h *Handler) getItemHistory(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		return
+	}
+
+	itemId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		newErrorResponse(c, invalidParam("item id"))
+		return
+	}
+
+	page, err := parsePage(c)
+	if err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	activity, nextCursor, err := h.services.Activity.GetByItem(c.Request.Context(), userId, itemId, page)
+	if err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, getActivityResponse{
+		Data:       activity,
+		NextCursor: nextCursor,
+	})
+}
+
+// parsePage reads the cursor and limit query parameters.
+func parsePage(c *gin.Context) (todo.Page, error) {
+	page := todo.Page{
+		Cursor: c.Query("cursor"),
+		Limit:  todo.DefaultActivityLimit,
+	}
+
+	if value, ok := c.GetQuery("limit"); ok {
+		limit, err := strconv.Atoi(value)
+		if err != nil {
+			return page, invalidParam("limit")
+		}
+		page.Limit = limit
+	}
+
+	return page, page.Validate()
+}
@@ -0,0 +1,32 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// getAgenda groups the open items of all the user's lists by when they are
+// due. Days start at midnight in the IANA time zone from the tz query
+// parameter, UTC by default.
+func (h *Handler) getAgenda(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		return
+	}
+
+	location, err := time.LoadLocation(c.DefaultQuery("tz", "UTC"))
+	if err != nil {
+		newErrorResponse(c, invalidParam("tz"))
+		return
+	}
+
+	agenda, err := h.services.TodoItem.GetAgenda(c.Request.Context(), userId, time.Now().In(location))
+	if err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, agenda)
+}
@@ -0,0 +1,160 @@
+package handler
+
+import (
+	"errors"
+	"mime"
+	"net/http"
+	"strconv"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lypolix/todo-app"
+)
+
+// multipartOverhead is what the request body may take on top of the file
+// for the boundaries and part headers.
+const multipartOverhead = 1 << 20
+
+// maxContentTypeLength is the size of item_attachments.content_type.
+const maxContentTypeLength = 255
+
+func (h *Handler) createAttachment(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		return
+	}
+
+	itemId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		newErrorResponse(c, invalidParam("item id"))
+		return
+	}
+
+	if h.cfg.MaxUploadSize > 0 {
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.cfg.MaxUploadSize+multipartOverhead)
+	}
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			newErrorResponse(c, todo.ErrFileTooLarge)
+			return
+		}
+		newErrorResponse(c, todo.InvalidInput(err))
+		return
+	}
+
+	contentType := header.Header.Get("Content-Type")
+	if utf8.RuneCountInString(contentType) > maxContentTypeLength {
+		newErrorResponse(c, todo.ErrInvalidContentType)
+		return
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+	defer file.Close()
+
+	id, err := h.services.Attachment.Create(c.Request.Context(), userId, itemId, todo.Attachment{
+		FileName:    header.Filename,
+		ContentType: contentType,
+		Size:        header.Size,
+	}, file)
+	if err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, map[string]interface{}{
+		"id": id,
+	})
+}
+
+type getAllAttachmentsResponse struct {
+	Data []todo.Attachment `json:"data"`
+}
+
+func (h *Handler) getAllAttachments(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		return
+	}
+
+	itemId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		newErrorResponse(c, invalidParam("item id"))
+		return
+	}
+
+	attachments, err := h.services.Attachment.GetAll(c.Request.Context(), userId, itemId)
+	if err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, getAllAttachmentsResponse{
+		Data: attachments,
+	})
+}
+
+// downloadAttachment always sends the file as a download, so uploaded HTML
+// or SVG is never rendered on the API's origin.
+func (h *Handler) downloadAttachment(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		return
+	}
+
+	itemId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		newErrorResponse(c, invalidParam("item id"))
+		return
+	}
+
+	attachmentId, err := strconv.Atoi(c.Param("attachmentId"))
+	if err != nil {
+		newErrorResponse(c, invalidParam("attachment id"))
+		return
+	}
+
+	attachment, content, err := h.services.Attachment.Open(c.Request.Context(), userId, itemId, attachmentId)
+	if err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+	defer content.Close()
+
+	c.DataFromReader(http.StatusOK, attachment.Size, attachment.ContentType, content, map[string]string{
+		"Content-Disposition":    mime.FormatMediaType("attachment", map[string]string{"filename": attachment.FileName}),
+		"X-Content-Type-Options": "nosniff",
+	})
+}
+
+func (h *Handler) deleteAttachment(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		return
+	}
+
+	itemId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		newErrorResponse(c, invalidParam("item id"))
+		return
+	}
+
+	attachmentId, err := strconv.Atoi(c.Param("attachmentId"))
+	if err != nil {
+		newErrorResponse(c, invalidParam("attachment id"))
+		return
+	}
+
+	if err := h.services.Attachment.Delete(c.Request.Context(), userId, itemId, attachmentId); err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, statusResponse{"ok"})
+}
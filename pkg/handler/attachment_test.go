@@ -0,0 +1,89 @@
+package handler
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http/httptest"
+	"net/textproto"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/lypolix/todo-app"
+	"github.com/lypolix/todo-app/pkg/service"
+	mock_service "github.com/lypolix/todo-app/pkg/service/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_createAttachment(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockAttachment, contentType string)
+
+	testTable := []struct {
+		name                string
+		contentType         string
+		mockBehavior        mockBehavior
+		expectedStatusCode  int
+		expectedRequestBody string
+	}{
+		{
+			name:        "OK",
+			contentType: "text/plain",
+			mockBehavior: func(s *mock_service.MockAttachment, contentType string) {
+				s.EXPECT().Create(gomock.Any(), 1, 5, todo.Attachment{FileName: "notes.txt", ContentType: contentType, Size: 5}, gomock.Any()).Return(7, nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `{"id":7}`,
+		},
+		{
+			name:        "Longest Content Type",
+			contentType: "text/" + strings.Repeat("x", 250),
+			mockBehavior: func(s *mock_service.MockAttachment, contentType string) {
+				s.EXPECT().Create(gomock.Any(), 1, 5, todo.Attachment{FileName: "notes.txt", ContentType: contentType, Size: 5}, gomock.Any()).Return(7, nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `{"id":7}`,
+		},
+		{
+			name:                "Content Type Too Long",
+			contentType:         "text/" + strings.Repeat("x", 251),
+			mockBehavior:        func(s *mock_service.MockAttachment, contentType string) {},
+			expectedStatusCode:  400,
+			expectedRequestBody: `{"code":"invalid_content_type","message":"content type must be at most 255 characters long"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			attachments := mock_service.NewMockAttachment(c)
+			testCase.mockBehavior(attachments, testCase.contentType)
+
+			handler := NewHandler(&service.Service{Attachment: attachments}, Config{MaxUploadSize: 1 << 10})
+
+			r := gin.New()
+			r.POST("/items/:id/attachments", func(c *gin.Context) { c.Set(userCtx, 1) }, handler.createAttachment)
+
+			body := new(bytes.Buffer)
+			form := multipart.NewWriter(body)
+			part, err := form.CreatePart(textproto.MIMEHeader{
+				"Content-Disposition": {`form-data; name="file"; filename="notes.txt"`},
+				"Content-Type":        {testCase.contentType},
+			})
+			assert.NoError(t, err)
+			part.Write([]byte("hello"))
+			form.Close()
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/items/5/attachments", body)
+			req.Header.Set("Content-Type", form.FormDataContentType())
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedRequestBody, w.Body.String())
+		})
+	}
+}
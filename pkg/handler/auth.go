@@ -25,13 +25,13 @@ func (h *Handler) signUp(c *gin.Context){
 	var input todo.User
 
 	if err := c.BindJSON(&input); err != nil {
-		newErrorResponse(c, http.StatusBadRequest, err.Error())
+		newErrorResponse(c, todo.InvalidInput(err))
 		return
 	}
 
-	id, err := h.services.Authorization.CreateUser(input)
+	id, err := h.services.Authorization.CreateUser(c.Request.Context(), input)
 	if err != nil {
-		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		newErrorResponse(c, err)
 		return 
 	}
 
@@ -53,7 +53,7 @@ type signInInput struct {
 // @Accept json
 // @Produce json
 // @Param input body signInInput true "credentials"
-// @Success 200 {string} string "token"
+// @Success 200 {object} todo.Tokens
 // @Failure 400 {object} errorResponse
 // @Failure 404 {object} errorResponse
 // @Failure 500 {object} errorResponse
@@ -63,17 +63,117 @@ func (h *Handler) signIn(c *gin.Context){
 	var input signInInput
 
 	if err := c.BindJSON(&input); err != nil {
-		newErrorResponse(c, http.StatusBadRequest, err.Error())
+		newErrorResponse(c, todo.InvalidInput(err))
 		return
 	}
 
-	token, err := h.services.Authorization.GenerateToken(input.Username, input.Password)
+	tokens, err := h.services.Authorization.GenerateToken(c.Request.Context(), input.Username, input.Password)
 	if err != nil {
-		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		newErrorResponse(c, err)
 		return 
 	}
 
-	c.JSON(http.StatusOK, map[string]interface{}{
-		"token": token,
-	})
-}
\ No newline at end of file
+	c.JSON(http.StatusOK, tokens)
+}
+
+type refreshInput struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+// @Summary Refresh
+// @Tags auth
+// @Description exchange a refresh token for a new token pair
+// @ID refresh
+// @Accept json
+// @Produce json
+// @Param input body refreshInput true "refresh token"
+// @Success 200 {object} todo.Tokens
+// @Failure 400 {object} errorResponse
+// @Failure 401 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /auth/refresh [post]
+func (h *Handler) refresh(c *gin.Context){
+	var input refreshInput
+
+	if err := c.BindJSON(&input); err != nil {
+		newErrorResponse(c, todo.InvalidInput(err))
+		return
+	}
+
+	tokens, err := h.services.Authorization.RefreshToken(c.Request.Context(), input.RefreshToken)
+	if err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, tokens)
+}
+
+type logoutInput struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+// @Summary Logout
+// @Security ApiKeyAuth
+// @Tags auth
+// @Description revoke the access token and the refresh token of the session
+// @ID logout
+// @Accept json
+// @Produce json
+// @Param input body logoutInput false "refresh token"
+// @Success 200 {object} statusResponse
+// @Failure 401 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /auth/logout [post]
+func (h *Handler) logout(c *gin.Context){
+	var input logoutInput
+
+	if c.Request.ContentLength != 0 {
+		if err := c.BindJSON(&input); err != nil {
+			newErrorResponse(c, todo.InvalidInput(err))
+			return
+		}
+	}
+
+	if err := h.services.Authorization.Logout(c.Request.Context(), c.GetString(tokenCtx), input.RefreshToken); err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, statusResponse{"ok"})
+}
+
+// @Summary Logout from all devices
+// @Security ApiKeyAuth
+// @Tags auth
+// @Description revoke every access and refresh token of the user
+// @ID logout-all
+// @Produce json
+// @Success 200 {object} statusResponse
+// @Failure 401 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /auth/logout-all [post]
+func (h *Handler) logoutAll(c *gin.Context){
+	userId, err := getUserId(c)
+	if err != nil {
+		return
+	}
+
+	if err := h.services.Authorization.LogoutAll(c.Request.Context(), userId); err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, statusResponse{"ok"})
+}
+
+// @Summary JWKS
+// @Tags auth
+// @Description public keys for verifying access tokens
+// @ID jwks
+// @Produce json
+// @Success 200 {object} service.JWKS
+// @Router /.well-known/jwks.json [get]
+func (h *Handler) jwks(c *gin.Context){
+	c.JSON(http.StatusOK, h.services.Authorization.JWKS())
+}
@@ -2,20 +2,16 @@ package handler
 
 import (
 	"bytes"
-	"net/http"
+	"errors"
 	"net/http/httptest"
 	"testing"
 
 	"github.com/gin-gonic/gin"
-	"github.com/go-playground/assert/v2"
 	"github.com/golang/mock/gomock"
 	"github.com/lypolix/todo-app"
-	"github.com/lypolix/todo-app/pkg/handler"
 	"github.com/lypolix/todo-app/pkg/service"
 	mock_service "github.com/lypolix/todo-app/pkg/service/mocks"
 	"github.com/stretchr/testify/assert"
-	"golang.org/x/crypto/nacl/auth"
-	"golang.org/x/tools/go/expect"
 )
 
 func TestHandler_signUp(t *testing.T) {
@@ -39,11 +35,46 @@ func TestHandler_signUp(t *testing.T) {
 				Password: "qwerty",
 			},
 			mockBehavior: func(authorization *mock_service.MockAuthorization, user todo.User){
-				authorization.EXPECT(user).Return(1, nil)
+				authorization.EXPECT().CreateUser(gomock.Any(), user).Return(1, nil)
 			},
 			expectedStatusCode: 200,
 			expectedRequestBody: `{"id":1}`,
 		},
+		{
+			name:"Empty Fields",
+			inputBody: `{"username":"test","password":"qwerty"}`,
+			mockBehavior: func(authorization *mock_service.MockAuthorization, user todo.User){},
+			expectedStatusCode: 400,
+			expectedRequestBody: `{"code":"invalid_input","message":"Key: 'User.Name' Error:Field validation for 'Name' failed on the 'required' tag"}`,
+		},
+		{
+			name:"Username Taken",
+			inputBody: `{"name":"Test","username":"test","password":"qwerty"}`,
+			inputUser: todo.User{
+				Name: "Test",
+				Username: "test",
+				Password: "qwerty",
+			},
+			mockBehavior: func(authorization *mock_service.MockAuthorization, user todo.User){
+				authorization.EXPECT().CreateUser(gomock.Any(), user).Return(0, todo.ErrUsernameTaken)
+			},
+			expectedStatusCode: 409,
+			expectedRequestBody: `{"code":"username_taken","message":"username is already taken"}`,
+		},
+		{
+			name:"Service Failure",
+			inputBody: `{"name":"Test","username":"test","password":"qwerty"}`,
+			inputUser: todo.User{
+				Name: "Test",
+				Username: "test",
+				Password: "qwerty",
+			},
+			mockBehavior: func(authorization *mock_service.MockAuthorization, user todo.User){
+				authorization.EXPECT().CreateUser(gomock.Any(), user).Return(0, errors.New("connection refused"))
+			},
+			expectedStatusCode: 500,
+			expectedRequestBody: `{"code":"internal_error","message":"internal server error"}`,
+		},
 	}
 
 	for _, testCase := range testTable {
@@ -55,7 +86,7 @@ func TestHandler_signUp(t *testing.T) {
 			testCase.mockBehavior(auth, testCase.inputUser)
 
 			service := &service.Service{Authorization: auth}
-			handler := NewHandler(service)
+			handler := NewHandler(service, Config{})
 
 
 			r := gin.New()
@@ -0,0 +1,350 @@
+package handler
+
+import (
+	"encoding/xml"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lypolix/todo-app"
+)
+
+// Paths of the CalDAV resources. The principal of every user is /dav/, the
+// lists are the calendars in the home collection under it.
+const (
+	davPrincipalPath = "/dav/"
+	davHomePath      = "/dav/calendars/"
+)
+
+const calendarObjectType = "text/calendar; charset=utf-8; component=VTODO"
+
+var errUnsupportedReport = todo.NewError(todo.ErrForbidden, "unsupported_report", "only calendar-query and calendar-multiget reports are supported")
+
+// davRedirect points clients that discover the server by RFC 6764 to the
+// principal.
+func (h *Handler) davRedirect(c *gin.Context) {
+	c.Redirect(http.StatusMovedPermanently, davPrincipalPath)
+}
+
+func (h *Handler) davOptions(c *gin.Context) {
+	c.Header("DAV", "1, 3, calendar-access")
+	c.Header("Allow", "OPTIONS, GET, HEAD, PUT, DELETE, PROPFIND, REPORT")
+	c.Status(http.StatusOK)
+}
+
+func (h *Handler) propfindPrincipal(c *gin.Context) {
+	req, err := bindDAVRequest(c)
+	if err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	principal := davResource{href: davPrincipalPath, props: map[xml.Name]string{
+		propResourceType:         `<collection xmlns="DAV:"/><principal xmlns="DAV:"/>`,
+		propDisplayName:          "Todo",
+		propCurrentUserPrincipal: davHref(davPrincipalPath),
+		propPrincipalURL:         davHref(davPrincipalPath),
+		propCalendarHomeSet:      davHref(davHomePath),
+	}}
+
+	davMultiStatus(c, []davResponse{principal.response(req)})
+}
+
+// propfindHome lists the calendars of the user with depth 1.
+func (h *Handler) propfindHome(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		return
+	}
+
+	req, err := bindDAVRequest(c)
+	if err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	home := davResource{href: davHomePath, props: map[xml.Name]string{
+		propResourceType:         `<collection xmlns="DAV:"/>`,
+		propDisplayName:          "Calendars",
+		propCurrentUserPrincipal: davHref(davPrincipalPath),
+	}}
+	responses := []davResponse{home.response(req)}
+
+	if c.GetHeader("Depth") != "0" {
+		calendars, err := h.services.CalDAV.GetCalendars(c.Request.Context(), userId)
+		if err != nil {
+			newErrorResponse(c, err)
+			return
+		}
+
+		for _, calendar := range calendars {
+			responses = append(responses, calendarResource(calendar).response(req))
+		}
+	}
+
+	davMultiStatus(c, responses)
+}
+
+// propfindCalendar returns the properties of the calendar of a list and,
+// with depth 1, of its objects.
+func (h *Handler) propfindCalendar(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		return
+	}
+
+	listId, err := strconv.Atoi(c.Param("listId"))
+	if err != nil {
+		newErrorResponse(c, invalidParam("list id"))
+		return
+	}
+
+	req, err := bindDAVRequest(c)
+	if err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	calendar, err := h.services.CalDAV.GetCalendar(c.Request.Context(), userId, listId)
+	if err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+	responses := []davResponse{calendarResource(calendar).response(req)}
+
+	if c.GetHeader("Depth") != "0" {
+		objects, err := h.services.CalDAV.GetObjects(c.Request.Context(), userId, listId)
+		if err != nil {
+			newErrorResponse(c, err)
+			return
+		}
+
+		for _, object := range objects {
+			responses = append(responses, objectResource(object).response(req))
+		}
+	}
+
+	davMultiStatus(c, responses)
+}
+
+func (h *Handler) propfindCalendarObject(c *gin.Context) {
+	userId, listId, name, err := objectParams(c)
+	if err != nil {
+		return
+	}
+
+	req, err := bindDAVRequest(c)
+	if err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	object, err := h.services.CalDAV.GetObject(c.Request.Context(), userId, listId, name)
+	if err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	davMultiStatus(c, []davResponse{objectResource(object).response(req)})
+}
+
+// reportCalendar answers calendar-multiget and calendar-query reports. A
+// query returns every to-do of the calendar, time ranges are left to the
+// client to apply.
+func (h *Handler) reportCalendar(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		return
+	}
+
+	listId, err := strconv.Atoi(c.Param("listId"))
+	if err != nil {
+		newErrorResponse(c, invalidParam("list id"))
+		return
+	}
+
+	req, err := bindDAVRequest(c)
+	if err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	if req.XMLName.Space != nsCalDAV || (req.XMLName.Local != "calendar-multiget" && req.XMLName.Local != "calendar-query") {
+		newErrorResponse(c, errUnsupportedReport)
+		return
+	}
+
+	objects, err := h.services.CalDAV.GetObjects(c.Request.Context(), userId, listId)
+	if err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	responses := make([]davResponse, 0)
+	if req.XMLName.Local == "calendar-query" {
+		if queriesTodos(req.Filter) {
+			for _, object := range objects {
+				responses = append(responses, objectResource(object).response(req))
+			}
+		}
+		davMultiStatus(c, responses)
+		return
+	}
+
+	byPath := make(map[string]todo.CalendarObject, len(objects))
+	for _, object := range objects {
+		byPath[calendarHref(listId)+object.Name] = object
+	}
+
+	for _, href := range req.Hrefs {
+		object, ok := byPath[davPath(href)]
+		if !ok {
+			responses = append(responses, davResponse{Href: href, Status: davStatus(http.StatusNotFound)})
+			continue
+		}
+		responses = append(responses, objectResource(object).response(req))
+	}
+
+	davMultiStatus(c, responses)
+}
+
+func (h *Handler) getCalendarObject(c *gin.Context) {
+	userId, listId, name, err := objectParams(c)
+	if err != nil {
+		return
+	}
+
+	object, err := h.services.CalDAV.GetObject(c.Request.Context(), userId, listId, name)
+	if err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	c.Header("ETag", object.ETag())
+	c.Data(http.StatusOK, calendarObjectType, object.Data)
+}
+
+// putCalendarObject creates or replaces a to-do. If-Match and If-None-Match
+// keep clients from overwriting changes they have not seen.
+func (h *Handler) putCalendarObject(c *gin.Context) {
+	userId, listId, name, err := objectParams(c)
+	if err != nil {
+		return
+	}
+
+	body := newImportBody(c)
+	object, created, err := h.services.CalDAV.PutObject(c.Request.Context(), userId, listId, name, body, requestPrecondition(c))
+	if err != nil {
+		newErrorResponse(c, importError(body, err))
+		return
+	}
+
+	c.Header("ETag", object.ETag())
+	if created {
+		c.Status(http.StatusCreated)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (h *Handler) deleteCalendarObject(c *gin.Context) {
+	userId, listId, name, err := objectParams(c)
+	if err != nil {
+		return
+	}
+
+	if err := h.services.CalDAV.DeleteObject(c.Request.Context(), userId, listId, name, requestPrecondition(c)); err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func objectParams(c *gin.Context) (int, int, string, error) {
+	userId, err := getUserId(c)
+	if err != nil {
+		return 0, 0, "", err
+	}
+
+	listId, err := strconv.Atoi(c.Param("listId"))
+	if err != nil {
+		newErrorResponse(c, invalidParam("list id"))
+		return 0, 0, "", err
+	}
+
+	return userId, listId, c.Param("object"), nil
+}
+
+func requestPrecondition(c *gin.Context) todo.Precondition {
+	return todo.Precondition{
+		IfMatch:     c.GetHeader("If-Match"),
+		IfNoneMatch: c.GetHeader("If-None-Match"),
+	}
+}
+
+func calendarHref(listId int) string {
+	return davHomePath + strconv.Itoa(listId) + "/"
+}
+
+func objectHref(object todo.CalendarObject) string {
+	return calendarHref(object.ListId) + url.PathEscape(object.Name)
+}
+
+// davPath is the unescaped path of a href, which clients may send as a full
+// URL.
+func davPath(href string) string {
+	u, err := url.Parse(href)
+	if err != nil {
+		return href
+	}
+
+	return u.Path
+}
+
+func calendarResource(calendar todo.Calendar) davResource {
+	privileges := `<privilege xmlns="DAV:"><read/></privilege>`
+	if calendar.Role != todo.RoleViewer {
+		privileges += `<privilege xmlns="DAV:"><write/></privilege>`
+	}
+
+	return davResource{href: calendarHref(calendar.Id), props: map[xml.Name]string{
+		propResourceType:          `<collection xmlns="DAV:"/><calendar xmlns="urn:ietf:params:xml:ns:caldav"/>`,
+		propDisplayName:           davText(calendar.Title),
+		propCalendarDescription:   davText(calendar.Description),
+		propSupportedComponents:   `<comp xmlns="urn:ietf:params:xml:ns:caldav" name="VTODO"/>`,
+		propSupportedReports:      supportedReports,
+		propCurrentUserPrivileges: privileges,
+		propCurrentUserPrincipal:  davHref(davPrincipalPath),
+		propGetCTag:               davText(calendar.CTag),
+	}}
+}
+
+var supportedReports = `<supported-report xmlns="DAV:"><report><calendar-query xmlns="urn:ietf:params:xml:ns:caldav"/></report></supported-report>` +
+	`<supported-report xmlns="DAV:"><report><calendar-multiget xmlns="urn:ietf:params:xml:ns:caldav"/></report></supported-report>`
+
+func objectResource(object todo.CalendarObject) davResource {
+	return davResource{href: objectHref(object), props: map[xml.Name]string{
+		propResourceType:   "",
+		propGetETag:        davText(object.ETag()),
+		propGetContentType: calendarObjectType,
+		propCalendarData:   davText(string(object.Data)),
+	}}
+}
+
+// queriesTodos tells whether a calendar-query filter can match to-dos.
+func queriesTodos(filter *davFilter) bool {
+	if filter == nil || len(filter.CompFilter.CompFilters) == 0 {
+		return true
+	}
+
+	for _, comp := range filter.CompFilter.CompFilters {
+		if strings.EqualFold(comp.Name, "VTODO") {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,206 @@
+package handler
+
+import (
+	"context"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/lypolix/todo-app"
+	"github.com/lypolix/todo-app/pkg/service"
+	mock_service "github.com/lypolix/todo-app/pkg/service/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_caldav(t *testing.T) {
+	type mockBehavior func(auth *mock_service.MockAuthorization, caldav *mock_service.MockCalDAV)
+
+	object := todo.CalendarObject{
+		TodoItem: todo.TodoItem{Id: 7, ListId: 3, Version: 2},
+		Name:     "a b.ics",
+		Data:     []byte("BEGIN:VCALENDAR\r\nEND:VCALENDAR\r\n"),
+	}
+	authenticated := func(auth *mock_service.MockAuthorization) {
+		auth.EXPECT().Authenticate(gomock.Any(), "test", "qwerty").Return(1, nil)
+	}
+
+	testTable := []struct {
+		name               string
+		method             string
+		path               string
+		headers            map[string]string
+		body               string
+		noAuth             bool
+		mockBehavior       mockBehavior
+		expectedStatusCode int
+		expectedHeaders    map[string]string
+		expectedInBody     []string
+	}{
+		{
+			name:               "No Credentials",
+			method:             "PROPFIND",
+			path:               "/dav/",
+			noAuth:             true,
+			mockBehavior:       func(auth *mock_service.MockAuthorization, caldav *mock_service.MockCalDAV) {},
+			expectedStatusCode: 401,
+			expectedHeaders:    map[string]string{"WWW-Authenticate": davChallenge},
+		},
+		{
+			name:   "Wrong Password",
+			method: "PROPFIND",
+			path:   "/dav/",
+			mockBehavior: func(auth *mock_service.MockAuthorization, caldav *mock_service.MockCalDAV) {
+				auth.EXPECT().Authenticate(gomock.Any(), "test", "qwerty").Return(0, todo.ErrInvalidCredentials)
+			},
+			expectedStatusCode: 401,
+			expectedHeaders:    map[string]string{"WWW-Authenticate": davChallenge},
+		},
+		{
+			name:    "Calendar With Objects",
+			method:  "PROPFIND",
+			path:    "/dav/calendars/3/",
+			headers: map[string]string{"Depth": "1"},
+			body: `<?xml version="1.0"?><d:propfind xmlns:d="DAV:" xmlns:cs="http://calendarserver.org/ns/">
+				<d:prop><d:getetag/><cs:getctag/><d:owner/></d:prop></d:propfind>`,
+			mockBehavior: func(auth *mock_service.MockAuthorization, caldav *mock_service.MockCalDAV) {
+				authenticated(auth)
+				caldav.EXPECT().GetCalendar(gomock.Any(), 1, 3).Return(todo.Calendar{TodoList: todo.TodoList{Id: 3}, CTag: "c1"}, nil)
+				caldav.EXPECT().GetObjects(gomock.Any(), 1, 3).Return([]todo.CalendarObject{object}, nil)
+			},
+			expectedStatusCode: 207,
+			expectedInBody: []string{
+				`<href>/dav/calendars/3/</href>`,
+				`<getctag xmlns="http://calendarserver.org/ns/">c1</getctag>`,
+				`<href>/dav/calendars/3/a%20b.ics</href>`,
+				`<getetag xmlns="DAV:">&#34;7-2&#34;</getetag>`,
+				`<owner xmlns="DAV:"></owner></prop><status>HTTP/1.1 404 Not Found</status>`,
+			},
+		},
+		{
+			name:   "Multiget",
+			method: "REPORT",
+			path:   "/dav/calendars/3/",
+			body: `<c:calendar-multiget xmlns:d="DAV:" xmlns:c="urn:ietf:params:xml:ns:caldav">
+				<d:prop><c:calendar-data/></d:prop>
+				<d:href>http://localhost/dav/calendars/3/a%20b.ics</d:href><d:href>/dav/calendars/3/gone.ics</d:href>
+				</c:calendar-multiget>`,
+			mockBehavior: func(auth *mock_service.MockAuthorization, caldav *mock_service.MockCalDAV) {
+				authenticated(auth)
+				caldav.EXPECT().GetObjects(gomock.Any(), 1, 3).Return([]todo.CalendarObject{object}, nil)
+			},
+			expectedStatusCode: 207,
+			expectedInBody: []string{
+				`<calendar-data xmlns="urn:ietf:params:xml:ns:caldav">BEGIN:VCALENDAR&#xD;`,
+				`<href>/dav/calendars/3/gone.ics</href><status>HTTP/1.1 404 Not Found</status>`,
+			},
+		},
+		{
+			name:   "Unsupported Report",
+			method: "REPORT",
+			path:   "/dav/calendars/3/",
+			body:   `<d:sync-collection xmlns:d="DAV:"><d:sync-token/></d:sync-collection>`,
+			mockBehavior: func(auth *mock_service.MockAuthorization, caldav *mock_service.MockCalDAV) {
+				authenticated(auth)
+			},
+			expectedStatusCode: 403,
+		},
+		{
+			name:   "Get Object",
+			method: "GET",
+			path:   "/dav/calendars/3/a%20b.ics",
+			mockBehavior: func(auth *mock_service.MockAuthorization, caldav *mock_service.MockCalDAV) {
+				authenticated(auth)
+				caldav.EXPECT().GetObject(gomock.Any(), 1, 3, "a b.ics").Return(object, nil)
+			},
+			expectedStatusCode: 200,
+			expectedHeaders:    map[string]string{"ETag": `"7-2"`, "Content-Type": calendarObjectType},
+			expectedInBody:     []string{"BEGIN:VCALENDAR"},
+		},
+		{
+			name:    "Create Object",
+			method:  "PUT",
+			path:    "/dav/calendars/3/a%20b.ics",
+			headers: map[string]string{"If-None-Match": "*"},
+			body:    "BEGIN:VCALENDAR\r\nEND:VCALENDAR\r\n",
+			mockBehavior: func(auth *mock_service.MockAuthorization, caldav *mock_service.MockCalDAV) {
+				authenticated(auth)
+				caldav.EXPECT().PutObject(gomock.Any(), 1, 3, "a b.ics", gomock.Any(), todo.Precondition{IfNoneMatch: "*"}).Return(object, true, nil)
+			},
+			expectedStatusCode: 201,
+			expectedHeaders:    map[string]string{"ETag": `"7-2"`},
+		},
+		{
+			name:    "Stale Update",
+			method:  "PUT",
+			path:    "/dav/calendars/3/a%20b.ics",
+			headers: map[string]string{"If-Match": `"7-1"`},
+			body:    "BEGIN:VCALENDAR\r\nEND:VCALENDAR\r\n",
+			mockBehavior: func(auth *mock_service.MockAuthorization, caldav *mock_service.MockCalDAV) {
+				authenticated(auth)
+				caldav.EXPECT().PutObject(gomock.Any(), 1, 3, "a b.ics", gomock.Any(), todo.Precondition{IfMatch: `"7-1"`}).Return(todo.CalendarObject{}, false, todo.ErrPreconditionFailed)
+			},
+			expectedStatusCode: 412,
+		},
+		{
+			name:   "Object Too Large",
+			method: "PUT",
+			path:   "/dav/calendars/3/a%20b.ics",
+			body:   strings.Repeat("a", maxImportSize+1),
+			mockBehavior: func(auth *mock_service.MockAuthorization, caldav *mock_service.MockCalDAV) {
+				authenticated(auth)
+				caldav.EXPECT().PutObject(gomock.Any(), 1, 3, "a b.ics", gomock.Any(), todo.Precondition{}).DoAndReturn(
+					func(_ context.Context, _, _ int, _ string, data io.Reader, _ todo.Precondition) (todo.CalendarObject, bool, error) {
+						_, err := io.ReadAll(data)
+						return todo.CalendarObject{}, false, todo.InvalidCalendarObject(err.Error())
+					})
+			},
+			expectedStatusCode: 413,
+			expectedInBody:     []string{"file_too_large"},
+		},
+		{
+			name:   "Delete Object",
+			method: "DELETE",
+			path:   "/dav/calendars/3/a%20b.ics",
+			mockBehavior: func(auth *mock_service.MockAuthorization, caldav *mock_service.MockCalDAV) {
+				authenticated(auth)
+				caldav.EXPECT().DeleteObject(gomock.Any(), 1, 3, "a b.ics", todo.Precondition{}).Return(nil)
+			},
+			expectedStatusCode: 204,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			auth := mock_service.NewMockAuthorization(c)
+			caldav := mock_service.NewMockCalDAV(c)
+			testCase.mockBehavior(auth, caldav)
+
+			services := &service.Service{Authorization: auth, CalDAV: caldav}
+			r := NewHandler(services, Config{}).InitRoutes()
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(testCase.method, testCase.path, strings.NewReader(testCase.body))
+			if !testCase.noAuth {
+				req.SetBasicAuth("test", "qwerty")
+			}
+			for name, value := range testCase.headers {
+				req.Header.Set(name, value)
+			}
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			for name, value := range testCase.expectedHeaders {
+				assert.Equal(t, value, w.Header().Get(name))
+			}
+			for _, part := range testCase.expectedInBody {
+				assert.Contains(t, w.Body.String(), part)
+			}
+		})
+	}
+}
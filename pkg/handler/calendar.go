@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lypolix/todo-app"
+)
+
+type calendarTokenResponse struct {
+	Token string `json:"token"`
+	Url   string `json:"url"`
+}
+
+// createCalendarToken issues the secret of the user's feed, a new one
+// replaces the old one.
+func (h *Handler) createCalendarToken(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		return
+	}
+
+	token, err := h.services.Calendar.CreateToken(c.Request.Context(), userId)
+	if err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, calendarTokenResponse{
+		Token: token,
+		Url:   "/ical/" + token + ".ics",
+	})
+}
+
+func (h *Handler) deleteCalendarToken(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		return
+	}
+
+	if err := h.services.Calendar.DeleteToken(c.Request.Context(), userId); err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, statusResponse{"ok"})
+}
+
+// getCalendarFeed serves /ical/:token.ics. Calendar apps cannot send a JWT,
+// the token in the path is the only credential.
+func (h *Handler) getCalendarFeed(c *gin.Context) {
+	token, ok := strings.CutSuffix(c.Param("file"), ".ics")
+	if !ok || token == "" {
+		newErrorResponse(c, todo.ErrCalendarNotFound)
+		return
+	}
+
+	data, err := h.services.Calendar.Feed(c.Request.Context(), token, c.Query("component"))
+	if err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	c.Data(http.StatusOK, "text/calendar; charset=utf-8", data)
+}
+
+func (h *Handler) importCalendar(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		return
+	}
+
+	listId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		newErrorResponse(c, invalidParam("list id"))
+		return
+	}
+
+	body := newImportBody(c)
+	ids, err := h.services.Calendar.Import(c.Request.Context(), userId, listId, body)
+	if err != nil {
+		newErrorResponse(c, importError(body, err))
+		return
+	}
+
+	c.JSON(http.StatusOK, map[string]interface{}{
+		"ids": ids,
+	})
+}
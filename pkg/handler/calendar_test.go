@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/lypolix/todo-app"
+	"github.com/lypolix/todo-app/pkg/ical"
+	"github.com/lypolix/todo-app/pkg/service"
+	mock_service "github.com/lypolix/todo-app/pkg/service/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+// parseCalendar reads the import like the service does, the parser reports
+// read errors as invalid data.
+func parseCalendar(_ context.Context, _, _ int, data io.Reader) ([]int, error) {
+	if _, err := ical.Parse(data); err != nil {
+		return nil, todo.InvalidImport(err.Error())
+	}
+	return []int{}, nil
+}
+
+func TestHandler_importCalendar(t *testing.T) {
+	filler := "X-FILLER:" + strings.Repeat("a", 1000) + "\r\n"
+	calendar := func(lines int) string {
+		return "BEGIN:VCALENDAR\r\n" + strings.Repeat(filler, lines) + "END:VCALENDAR\r\n"
+	}
+
+	testTable := []struct {
+		name                string
+		inputBody           string
+		expectedStatusCode  int
+		expectedRequestBody string
+	}{
+		{
+			name:                "OK",
+			inputBody:           calendar(1),
+			expectedStatusCode:  200,
+			expectedRequestBody: `{"ids":[]}`,
+		},
+		{
+			name:                "Body Too Large",
+			inputBody:           calendar(maxImportSize/len(filler) + 1),
+			expectedStatusCode:  413,
+			expectedRequestBody: `{"code":"file_too_large","message":"file is larger than allowed"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			calendars := mock_service.NewMockCalendar(c)
+			calendars.EXPECT().Import(gomock.Any(), 1, 3, gomock.Any()).DoAndReturn(parseCalendar)
+
+			handler := NewHandler(&service.Service{Calendar: calendars}, Config{})
+
+			r := gin.New()
+			r.POST("/lists/:id/import/ics", func(c *gin.Context) { c.Set(userCtx, 1) }, handler.importCalendar)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/lists/3/import/ics", bytes.NewBufferString(testCase.inputBody))
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedRequestBody, w.Body.String())
+		})
+	}
+}
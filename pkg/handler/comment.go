@@ -0,0 +1,123 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lypolix/todo-app"
+)
+
+func (h *Handler) createComment(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		return
+	}
+
+	itemId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		newErrorResponse(c, invalidParam("item id"))
+		return
+	}
+
+	var input todo.Comment
+	if err := c.BindJSON(&input); err != nil {
+		newErrorResponse(c, todo.InvalidInput(err))
+		return
+	}
+
+	id, err := h.services.Comment.Create(c.Request.Context(), userId, itemId, input)
+	if err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, map[string]interface{}{
+		"id": id,
+	})
+}
+
+type getAllCommentsResponse struct {
+	Data []todo.Comment `json:"data"`
+}
+
+func (h *Handler) getAllComments(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		return
+	}
+
+	itemId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		newErrorResponse(c, invalidParam("item id"))
+		return
+	}
+
+	comments, err := h.services.Comment.GetAll(c.Request.Context(), userId, itemId)
+	if err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, getAllCommentsResponse{
+		Data: comments,
+	})
+}
+
+func (h *Handler) updateComment(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		return
+	}
+
+	itemId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		newErrorResponse(c, invalidParam("item id"))
+		return
+	}
+
+	commentId, err := strconv.Atoi(c.Param("commentId"))
+	if err != nil {
+		newErrorResponse(c, invalidParam("comment id"))
+		return
+	}
+
+	var input todo.UpdateCommentInput
+	if err := c.BindJSON(&input); err != nil {
+		newErrorResponse(c, todo.InvalidInput(err))
+		return
+	}
+
+	if err := h.services.Comment.Update(c.Request.Context(), userId, itemId, commentId, input); err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, statusResponse{"ok"})
+}
+
+func (h *Handler) deleteComment(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		return
+	}
+
+	itemId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		newErrorResponse(c, invalidParam("item id"))
+		return
+	}
+
+	commentId, err := strconv.Atoi(c.Param("commentId"))
+	if err != nil {
+		newErrorResponse(c, invalidParam("comment id"))
+		return
+	}
+
+	if err := h.services.Comment.Delete(c.Request.Context(), userId, itemId, commentId); err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, statusResponse{"ok"})
+}
@@ -0,0 +1,180 @@
+package handler
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lypolix/todo-app"
+)
+
+// XML namespaces of WebDAV, CalDAV and the calendar server extensions.
+const (
+	nsDAV       = "DAV:"
+	nsCalDAV    = "urn:ietf:params:xml:ns:caldav"
+	nsCalServer = "http://calendarserver.org/ns/"
+)
+
+// maxDAVRequestSize bounds the XML body of a PROPFIND or REPORT request.
+const maxDAVRequestSize = 1 << 20
+
+var (
+	propResourceType          = xml.Name{Space: nsDAV, Local: "resourcetype"}
+	propDisplayName           = xml.Name{Space: nsDAV, Local: "displayname"}
+	propCurrentUserPrincipal  = xml.Name{Space: nsDAV, Local: "current-user-principal"}
+	propPrincipalURL          = xml.Name{Space: nsDAV, Local: "principal-URL"}
+	propCurrentUserPrivileges = xml.Name{Space: nsDAV, Local: "current-user-privilege-set"}
+	propSupportedReports      = xml.Name{Space: nsDAV, Local: "supported-report-set"}
+	propGetETag               = xml.Name{Space: nsDAV, Local: "getetag"}
+	propGetContentType        = xml.Name{Space: nsDAV, Local: "getcontenttype"}
+	propCalendarHomeSet       = xml.Name{Space: nsCalDAV, Local: "calendar-home-set"}
+	propCalendarDescription   = xml.Name{Space: nsCalDAV, Local: "calendar-description"}
+	propSupportedComponents   = xml.Name{Space: nsCalDAV, Local: "supported-calendar-component-set"}
+	propCalendarData          = xml.Name{Space: nsCalDAV, Local: "calendar-data"}
+	propGetCTag               = xml.Name{Space: nsCalServer, Local: "getctag"}
+)
+
+var errInvalidDAVRequest = todo.NewError(todo.ErrInvalidInput, "invalid_dav_request", "request body is not a valid WebDAV request")
+
+// davRequest is the body of a PROPFIND or REPORT request, XMLName tells
+// which one it is.
+type davRequest struct {
+	XMLName  xml.Name
+	AllProp  *struct{}  `xml:"DAV: allprop"`
+	PropName *struct{}  `xml:"DAV: propname"`
+	Prop     *davNames  `xml:"DAV: prop"`
+	Hrefs    []string   `xml:"DAV: href"`
+	Filter   *davFilter `xml:"urn:ietf:params:xml:ns:caldav filter"`
+}
+
+type davNames struct {
+	Names []struct {
+		XMLName xml.Name
+	} `xml:",any"`
+}
+
+type davFilter struct {
+	CompFilter davCompFilter `xml:"urn:ietf:params:xml:ns:caldav comp-filter"`
+}
+
+type davCompFilter struct {
+	Name        string          `xml:"name,attr"`
+	CompFilters []davCompFilter `xml:"urn:ietf:params:xml:ns:caldav comp-filter"`
+}
+
+type davMultistatus struct {
+	XMLName   xml.Name      `xml:"DAV: multistatus"`
+	Responses []davResponse `xml:"response"`
+}
+
+type davResponse struct {
+	Href      string        `xml:"href"`
+	Status    string        `xml:"status,omitempty"`
+	Propstats []davPropstat `xml:"propstat"`
+}
+
+type davPropstat struct {
+	Prop   davPropList `xml:"prop"`
+	Status string      `xml:"status"`
+}
+
+type davPropList struct {
+	Props []davProp
+}
+
+// davProp is a property with its value as XML.
+type davProp struct {
+	XMLName xml.Name
+	Value   string `xml:",innerxml"`
+}
+
+// davResource is a resource with the values of the properties it has.
+type davResource struct {
+	href  string
+	props map[xml.Name]string
+}
+
+// response answers the request for the properties of the resource, those
+// it does not have are reported as not found. Without a list of properties
+// all of them are returned except calendar-data, which must be asked for.
+func (r davResource) response(req davRequest) davResponse {
+	var found, missing []davProp
+	if req.Prop != nil {
+		for _, name := range req.Prop.Names {
+			value, ok := r.props[name.XMLName]
+			if !ok {
+				missing = append(missing, davProp{XMLName: name.XMLName})
+				continue
+			}
+			found = append(found, davProp{XMLName: name.XMLName, Value: value})
+		}
+	} else {
+		for name, value := range r.props {
+			if name == propCalendarData {
+				continue
+			}
+			if req.PropName != nil {
+				value = ""
+			}
+			found = append(found, davProp{XMLName: name, Value: value})
+		}
+		sort.Slice(found, func(i, j int) bool {
+			if found[i].XMLName.Space != found[j].XMLName.Space {
+				return found[i].XMLName.Space < found[j].XMLName.Space
+			}
+			return found[i].XMLName.Local < found[j].XMLName.Local
+		})
+	}
+
+	response := davResponse{Href: r.href}
+	if len(found) > 0 {
+		response.Propstats = append(response.Propstats, davPropstat{Prop: davPropList{found}, Status: davStatus(http.StatusOK)})
+	}
+	if len(missing) > 0 {
+		response.Propstats = append(response.Propstats, davPropstat{Prop: davPropList{missing}, Status: davStatus(http.StatusNotFound)})
+	}
+
+	return response
+}
+
+// bindDAVRequest reads the XML body of the request. An empty body asks for
+// all properties.
+func bindDAVRequest(c *gin.Context) (davRequest, error) {
+	var req davRequest
+	body := http.MaxBytesReader(c.Writer, c.Request.Body, maxDAVRequestSize)
+	if err := xml.NewDecoder(body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			return req, todo.NewError(todo.ErrTooLarge, "request_too_large", "request body is too large")
+		}
+		return req, errInvalidDAVRequest
+	}
+
+	return req, nil
+}
+
+func davMultiStatus(c *gin.Context, responses []davResponse) {
+	c.XML(http.StatusMultiStatus, davMultistatus{Responses: responses})
+}
+
+func davStatus(code int) string {
+	return fmt.Sprintf("HTTP/1.1 %d %s", code, http.StatusText(code))
+}
+
+// davText escapes text for an XML element.
+func davText(text string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(text))
+
+	return b.String()
+}
+
+// davHref is a DAV:href element, for the values of properties.
+func davHref(href string) string {
+	return `<href xmlns="DAV:">` + davText(href) + `</href>`
+}
@@ -0,0 +1,126 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lypolix/todo-app"
+)
+
+// maxImportSize bounds the body of an import request.
+const maxImportSize = 5 << 20
+
+// importBody is the body of an import request, limited to maxImportSize. It
+// remembers whether the limit was hit, as decoders report a cut off body as
+// invalid data.
+type importBody struct {
+	io.ReadCloser
+	tooLarge bool
+}
+
+func newImportBody(c *gin.Context) *importBody {
+	return &importBody{ReadCloser: http.MaxBytesReader(c.Writer, c.Request.Body, maxImportSize)}
+}
+
+func (b *importBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if errors.As(err, new(*http.MaxBytesError)) {
+		b.tooLarge = true
+	}
+	return n, err
+}
+
+// importError is the error to answer a failed import of the body with.
+func importError(body *importBody, err error) error {
+	if body.tooLarge {
+		return todo.ErrFileTooLarge
+	}
+	return err
+}
+
+var exchangeContentTypes = map[string]string{
+	todo.FormatJSON:     "application/json; charset=utf-8",
+	todo.FormatCSV:      "text/csv; charset=utf-8",
+	todo.FormatMarkdown: "text/markdown; charset=utf-8",
+}
+
+// exportList sends the list as a file in the format from the format query
+// parameter, json by default.
+func (h *Handler) exportList(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		return
+	}
+
+	listId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		newErrorResponse(c, invalidParam("list id"))
+		return
+	}
+
+	format := c.DefaultQuery("format", todo.FormatJSON)
+	data, err := h.services.Exchange.Export(c.Request.Context(), userId, listId, format)
+	if err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{
+		"filename": fmt.Sprintf("list-%d.%s", listId, format),
+	}))
+	c.Data(http.StatusOK, exchangeContentTypes[format], data)
+}
+
+// importList creates a list from the request body. The format comes from the
+// format query parameter or else from the content type, title replaces the
+// title found in the data and dry_run only reports what would be created.
+func (h *Handler) importList(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		return
+	}
+
+	input := todo.ImportInput{
+		Format: c.Query("format"),
+		Title:  c.Query("title"),
+	}
+
+	if input.Format == "" {
+		input.Format = formatOf(c.ContentType())
+	}
+
+	if value, ok := c.GetQuery("dry_run"); ok {
+		if input.DryRun, err = strconv.ParseBool(value); err != nil {
+			newErrorResponse(c, invalidParam("dry_run"))
+			return
+		}
+	}
+
+	body := newImportBody(c)
+	result, err := h.services.Exchange.Import(c.Request.Context(), userId, input, body)
+	if err != nil {
+		newErrorResponse(c, importError(body, err))
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// formatOf is the import format of a request body with the content type.
+func formatOf(contentType string) string {
+	switch contentType {
+	case "application/json":
+		return todo.FormatJSON
+	case "text/csv":
+		return todo.FormatCSV
+	case "text/markdown":
+		return todo.FormatMarkdown
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/lypolix/todo-app"
+	"github.com/lypolix/todo-app/pkg/service"
+	mock_service "github.com/lypolix/todo-app/pkg/service/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+// readImport reads the import like the decoders do, which report any read
+// error as invalid data.
+func readImport(_ context.Context, _ int, _ todo.ImportInput, data io.Reader) (todo.ImportResult, error) {
+	if _, err := io.ReadAll(data); err != nil {
+		return todo.ImportResult{}, todo.InvalidImport(err.Error())
+	}
+	return todo.ImportResult{}, nil
+}
+
+func TestHandler_importList(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockExchange)
+
+	testTable := []struct {
+		name                string
+		inputBody           string
+		mockBehavior        mockBehavior
+		expectedStatusCode  int
+		expectedRequestBody string
+	}{
+		{
+			name:      "Largest Body",
+			inputBody: strings.Repeat("a", maxImportSize),
+			mockBehavior: func(s *mock_service.MockExchange) {
+				s.EXPECT().Import(gomock.Any(), 1, todo.ImportInput{Format: todo.FormatJSON}, gomock.Any()).DoAndReturn(readImport)
+			},
+			expectedStatusCode: 200,
+		},
+		{
+			name:      "Body Too Large",
+			inputBody: strings.Repeat("a", maxImportSize+1),
+			mockBehavior: func(s *mock_service.MockExchange) {
+				s.EXPECT().Import(gomock.Any(), 1, todo.ImportInput{Format: todo.FormatJSON}, gomock.Any()).DoAndReturn(readImport)
+			},
+			expectedStatusCode:  413,
+			expectedRequestBody: `{"code":"file_too_large","message":"file is larger than allowed"}`,
+		},
+		{
+			name:      "Invalid Import",
+			inputBody: "[",
+			mockBehavior: func(s *mock_service.MockExchange) {
+				s.EXPECT().Import(gomock.Any(), 1, todo.ImportInput{Format: todo.FormatJSON}, gomock.Any()).Return(todo.ImportResult{}, todo.InvalidImport("unexpected EOF"))
+			},
+			expectedStatusCode:  400,
+			expectedRequestBody: `{"code":"invalid_import","message":"unexpected EOF"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			exchange := mock_service.NewMockExchange(c)
+			testCase.mockBehavior(exchange)
+
+			handler := NewHandler(&service.Service{Exchange: exchange}, Config{})
+
+			r := gin.New()
+			r.POST("/lists/import", func(c *gin.Context) { c.Set(userCtx, 1) }, handler.importList)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/lists/import", bytes.NewBufferString(testCase.inputBody))
+			req.Header.Set("Content-Type", "application/json")
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			if testCase.expectedRequestBody != "" {
+				assert.Equal(t, testCase.expectedRequestBody, w.Body.String())
+			}
+		})
+	}
+}
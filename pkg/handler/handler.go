@@ -1,6 +1,8 @@
 package handler
 
 import (
+	"time"
+
 	"github.com/gin-gonic/gin"
 	"github.com/lypolix/todo-app/pkg/service"
 	
@@ -25,23 +27,55 @@ import (
 
 type Handler struct{
 	services *service.Service
+	cfg Config
 }
 
+type Config struct {
+	// QueryTimeout bounds the time a request may spend in the database; zero means no limit
+	QueryTimeout time.Duration
+	// MaxUploadSize is the largest file that can be uploaded; zero means no limit
+	MaxUploadSize int64
+	// UploadTimeout replaces QueryTimeout for file uploads, which also store the file; zero means no limit
+	UploadTimeout time.Duration
+}
 
-func NewHandler(services *service.Service) *Handler{
-	return &Handler{services: services}
+func NewHandler(services *service.Service, cfg Config) *Handler{
+	return &Handler{services: services, cfg: cfg}
 }
 
 
 func (h *Handler) InitRoutes() *gin.Engine {
 	router := gin.New()
+	router.Use(h.queryTimeout)
 
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+	router.GET("/.well-known/jwks.json", h.jwks)
+	router.GET("/ical/:file", h.getCalendarFeed)
+
+	router.GET("/.well-known/caldav", h.davRedirect)
+	router.Handle("PROPFIND", "/.well-known/caldav", h.davRedirect)
+	router.OPTIONS("/dav/*path", h.davOptions)
+
+	dav := router.Group("/dav", h.davAuth)
+	{
+		dav.Handle("PROPFIND", "/", h.propfindPrincipal)
+		dav.Handle("PROPFIND", "/calendars/", h.propfindHome)
+		dav.Handle("PROPFIND", "/calendars/:listId/", h.propfindCalendar)
+		dav.Handle("REPORT", "/calendars/:listId/", h.reportCalendar)
+		dav.Handle("PROPFIND", "/calendars/:listId/:object", h.propfindCalendarObject)
+		dav.GET("/calendars/:listId/:object", h.getCalendarObject)
+		dav.HEAD("/calendars/:listId/:object", h.getCalendarObject)
+		dav.PUT("/calendars/:listId/:object", h.putCalendarObject)
+		dav.DELETE("/calendars/:listId/:object", h.deleteCalendarObject)
+	}
 	
 	auth := router.Group("/auth")
 	{
 		auth.POST("/sign-up", h.signUp)
 		auth.POST("/sign-in", h.signIn)
+		auth.POST("/refresh", h.refresh)
+		auth.POST("/logout", h.userIdentity, h.logout)
+		auth.POST("/logout-all", h.userIdentity, h.logoutAll)
 	}
 
 	api := router.Group("/api", h.userIdentity)
@@ -53,6 +87,19 @@ func (h *Handler) InitRoutes() *gin.Engine {
 			lists.GET("/:id", h.getListById)
 			lists.PUT("/:id", h.updateList)
 			lists.DELETE("/:id", h.deleteList)
+			lists.POST("/:id/transfer", h.transferList)
+			lists.GET("/:id/activity", h.getListActivity)
+			lists.GET("/:id/export", h.exportList)
+			lists.POST("/import", h.importList)
+			lists.POST("/:id/import/ics", h.importCalendar)
+
+			members := lists.Group(":id/members")
+			{
+				members.POST("/", h.addListMember)
+				members.GET("/", h.getListMembers)
+				members.PUT("/:userId", h.updateListMember)
+				members.DELETE("/:userId", h.deleteListMember)
+			}
 
 			items := lists.Group(":id/items")
 			{
@@ -62,9 +109,62 @@ func (h *Handler) InitRoutes() *gin.Engine {
 		}
 		items := api.Group("items")
 		{
+			items.GET("/", h.findItems)
 			items.GET("/:id", h.getItemById)
 			items.PUT("/:id", h.updateItem)
 			items.DELETE("/:id", h.deleteItem)
+			items.POST("/:id/move", h.moveItem)
+			items.GET("/:id/history", h.getItemHistory)
+
+			subtasks := items.Group(":id/subtasks")
+			{
+				subtasks.POST("/", h.createSubtask)
+				subtasks.GET("/", h.getAllSubtasks)
+				subtasks.PUT("/:subtaskId", h.updateSubtask)
+				subtasks.DELETE("/:subtaskId", h.deleteSubtask)
+			}
+
+			comments := items.Group(":id/comments")
+			{
+				comments.POST("/", h.createComment)
+				comments.GET("/", h.getAllComments)
+				comments.PUT("/:commentId", h.updateComment)
+				comments.DELETE("/:commentId", h.deleteComment)
+			}
+
+			attachments := items.Group(":id/attachments")
+			{
+				attachments.POST("/", h.createAttachment)
+				attachments.GET("/", h.getAllAttachments)
+				attachments.GET("/:attachmentId", h.downloadAttachment)
+				attachments.DELETE("/:attachmentId", h.deleteAttachment)
+			}
+
+			items.POST("/:id/labels/:labelId", h.attachLabel)
+			items.DELETE("/:id/labels/:labelId", h.detachLabel)
+		}
+
+		api.GET("/agenda", h.getAgenda)
+		api.GET("/search", h.search)
+
+		calendar := api.Group("/ical")
+		{
+			calendar.POST("/token", h.createCalendarToken)
+			calendar.DELETE("/token", h.deleteCalendarToken)
+		}
+
+		trash := api.Group("/trash")
+		{
+			trash.GET("/", h.getTrash)
+			trash.POST("/:type/:id/restore", h.restoreFromTrash)
+		}
+
+		labels := api.Group("/labels")
+		{
+			labels.POST("/", h.createLabel)
+			labels.GET("/", h.getAllLabels)
+			labels.PUT("/:id", h.updateLabel)
+			labels.DELETE("/:id", h.deleteLabel)
 		}
 	}
 	return router
@@ -3,6 +3,7 @@ package handler
 import (
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/lypolix/todo-app"
@@ -16,19 +17,19 @@ func (h *Handler) createItem(c *gin.Context){
 
 	listId, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		newErrorResponse(c, http.StatusBadRequest, "invalid list id param")
+		newErrorResponse(c, invalidParam("list id"))
 		return 
 	}
 
-	var input todo.TodoItem
+	var input todo.CreateItemInput
 	if err := c.BindJSON(&input); err != nil {
-		newErrorResponse(c, http.StatusBadRequest, err.Error())
+		newErrorResponse(c, todo.InvalidInput(err))
 		return
 	}
 
-	id, err := h.services.TodoItem.Create(UserId, listId, input)
+	id, err := h.services.TodoItem.Create(c.Request.Context(), UserId, listId, input)
 	if err != nil {
-		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		newErrorResponse(c, err)
 		return
 	}
 
@@ -45,17 +46,105 @@ func (h *Handler) getAllItems(c *gin.Context) {
 
 	listId, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		newErrorResponse(c, http.StatusBadRequest, "invalid list id param")
+		newErrorResponse(c, invalidParam("list id"))
 		return
 	}
 
-	items, err := h.services.TodoItem.GetAll(UserId, listId)
+	filter, err := parseItemFilter(c, todo.SortByPosition)
 	if err != nil {
-		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		newErrorResponse(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, items)
+	items, nextCursor, err := h.services.TodoItem.GetAll(c.Request.Context(), UserId, listId, filter)
+	if err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, getAllItemsResponse{
+		Data: items,
+		NextCursor: nextCursor,
+	})
+}
+
+// findItems looks for items across all lists of the user, e.g. by tag.
+func (h *Handler) findItems(c *gin.Context) {
+	UserId, err := getUserId(c)
+	if err != nil {
+		return
+	}
+
+	filter, err := parseItemFilter(c, todo.SortByCreatedAt)
+	if err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	items, nextCursor, err := h.services.TodoItem.Find(c.Request.Context(), UserId, filter)
+	if err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, getAllItemsResponse{
+		Data: items,
+		NextCursor: nextCursor,
+	})
+}
+
+type getAllItemsResponse struct {
+	Data []todo.TodoItem `json:"data"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// parseItemFilter reads done, due_before, due_after, q, tag, match, sort,
+// order, cursor and limit query parameters. Items are sorted by defaultSort
+// unless sort is given.
+func parseItemFilter(c *gin.Context, defaultSort string) (todo.ItemFilter, error) {
+	filter := todo.ItemFilter{
+		Query: c.Query("q"),
+		Tags: c.QueryArray("tag"),
+		TagMatch: c.DefaultQuery("match", todo.TagMatchAny),
+		Sort: c.DefaultQuery("sort", defaultSort),
+		Order: c.DefaultQuery("order", todo.OrderAsc),
+		Cursor: c.Query("cursor"),
+		Limit: todo.DefaultItemsLimit,
+	}
+
+	if value, ok := c.GetQuery("done"); ok {
+		done, err := strconv.ParseBool(value)
+		if err != nil {
+			return filter, invalidParam("done")
+		}
+		filter.Done = &done
+	}
+
+	if value, ok := c.GetQuery("due_before"); ok {
+		dueBefore, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return filter, invalidParam("due_before")
+		}
+		filter.DueBefore = &dueBefore
+	}
+
+	if value, ok := c.GetQuery("due_after"); ok {
+		dueAfter, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return filter, invalidParam("due_after")
+		}
+		filter.DueAfter = &dueAfter
+	}
+
+	if value, ok := c.GetQuery("limit"); ok {
+		limit, err := strconv.Atoi(value)
+		if err != nil {
+			return filter, invalidParam("limit")
+		}
+		filter.Limit = limit
+	}
+
+	return filter, nil
 }
 
 func (h *Handler) getItemById(c *gin.Context){
@@ -66,16 +155,17 @@ func (h *Handler) getItemById(c *gin.Context){
 
 	itemId, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		newErrorResponse(c, http.StatusBadRequest, "invalid list id param")
+		newErrorResponse(c, invalidParam("list id"))
 		return
 	}
 
-	item, err := h.services.TodoItem.GetById(UserId, itemId)
+	item, err := h.services.TodoItem.GetById(c.Request.Context(), UserId, itemId)
 	if err != nil {
-		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		newErrorResponse(c, err)
 		return
 	}
 
+	c.Header("ETag", versionETag(item.Version))
 	c.JSON(http.StatusOK, item)
 }
 
@@ -88,18 +178,49 @@ func (h *Handler) updateItem(c *gin.Context){
 
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		newErrorResponse(c, http.StatusBadRequest, "invalid id param")
+		newErrorResponse(c, invalidParam("id"))
 		return 
 	}
 
 	var input todo.UpdateItemInput
 	if err := c.BindJSON(&input); err != nil {
-		newErrorResponse(c, http.StatusBadRequest, err.Error())
+		newErrorResponse(c, todo.InvalidInput(err))
+		return
+	}
+
+	if input.Version, err = ifMatchVersion(c); err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	if err := h.services.TodoItem.Update(c.Request.Context(), UserId, id, input); err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, statusResponse{"ok"})
+}
+
+func (h *Handler) moveItem(c *gin.Context) {
+	UserId, err := getUserId(c)
+	if err != nil {
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		newErrorResponse(c, invalidParam("id"))
+		return
+	}
+
+	var input todo.MoveItemInput
+	if err := c.BindJSON(&input); err != nil {
+		newErrorResponse(c, todo.InvalidInput(err))
 		return
 	}
 
-	if err := h.services.TodoItem.Update(UserId, id, input); err != nil {
-		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+	if err := h.services.TodoItem.Move(c.Request.Context(), UserId, id, input); err != nil {
+		newErrorResponse(c, err)
 		return
 	}
 
@@ -114,13 +235,19 @@ func (h *Handler) deleteItem(c *gin.Context){
 
 	itemId, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		newErrorResponse(c, http.StatusBadRequest, "invalid list id param")
+		newErrorResponse(c, invalidParam("list id"))
+		return
+	}
+
+	version, err := ifMatchVersion(c)
+	if err != nil {
+		newErrorResponse(c, err)
 		return
 	}
 
-	err = h.services.TodoItem.Delete(UserId, itemId)
+	err = h.services.TodoItem.Delete(c.Request.Context(), UserId, itemId, version)
 	if err != nil {
-		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		newErrorResponse(c, err)
 		return
 	}
 
@@ -0,0 +1,207 @@
+package handler
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/lypolix/todo-app"
+	"github.com/lypolix/todo-app/pkg/service"
+	mock_service "github.com/lypolix/todo-app/pkg/service/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_getItemById(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	items := mock_service.NewMockTodoItem(c)
+	items.EXPECT().GetById(gomock.Any(), 1, 5).Return(todo.TodoItem{Id: 5, Title: "Milk", Version: 4}, nil)
+
+	handler := NewHandler(&service.Service{TodoItem: items}, Config{})
+
+	r := gin.New()
+	r.GET("/items/:id", func(c *gin.Context) { c.Set(userCtx, 1) }, handler.getItemById)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/items/5", nil)
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, 200, w.Code)
+	assert.Equal(t, `"4"`, w.Header().Get("ETag"))
+}
+
+func TestHandler_updateItem(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockTodoItem, input todo.UpdateItemInput)
+
+	title := "Oat milk"
+	version := 3
+
+	testTable := []struct {
+		name                string
+		ifMatch             string
+		input               todo.UpdateItemInput
+		mockBehavior        mockBehavior
+		expectedStatusCode  int
+		expectedRequestBody string
+	}{
+		{
+			name:  "Unconditional",
+			input: todo.UpdateItemInput{Title: &title},
+			mockBehavior: func(s *mock_service.MockTodoItem, input todo.UpdateItemInput) {
+				s.EXPECT().Update(gomock.Any(), 1, 5, input).Return(nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `{"status":"ok"}`,
+		},
+		{
+			name:    "Current Version",
+			ifMatch: `"3"`,
+			input:   todo.UpdateItemInput{Title: &title, Version: &version},
+			mockBehavior: func(s *mock_service.MockTodoItem, input todo.UpdateItemInput) {
+				s.EXPECT().Update(gomock.Any(), 1, 5, input).Return(nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `{"status":"ok"}`,
+		},
+		{
+			name:    "Stale Version",
+			ifMatch: `"3"`,
+			input:   todo.UpdateItemInput{Title: &title, Version: &version},
+			mockBehavior: func(s *mock_service.MockTodoItem, input todo.UpdateItemInput) {
+				s.EXPECT().Update(gomock.Any(), 1, 5, input).Return(todo.ErrPreconditionFailed)
+			},
+			expectedStatusCode:  412,
+			expectedRequestBody: `{"code":"precondition_failed","message":"resource does not match the If-Match or If-None-Match header"}`,
+		},
+		{
+			name:                "Weak Tag",
+			ifMatch:             `W/"3"`,
+			mockBehavior:        func(s *mock_service.MockTodoItem, input todo.UpdateItemInput) {},
+			expectedStatusCode:  412,
+			expectedRequestBody: `{"code":"precondition_failed","message":"resource does not match the If-Match or If-None-Match header"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			items := mock_service.NewMockTodoItem(c)
+			testCase.mockBehavior(items, testCase.input)
+
+			handler := NewHandler(&service.Service{TodoItem: items}, Config{})
+
+			r := gin.New()
+			r.PUT("/items/:id", func(c *gin.Context) { c.Set(userCtx, 1) }, handler.updateItem)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("PUT", "/items/5", bytes.NewBufferString(`{"title":"Oat milk"}`))
+			if testCase.ifMatch != "" {
+				req.Header.Set("If-Match", testCase.ifMatch)
+			}
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedRequestBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_createItem(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	// the series of another user's item cannot be joined by naming it
+	items := mock_service.NewMockTodoItem(c)
+	items.EXPECT().Create(gomock.Any(), 1, 3, todo.CreateItemInput{Title: "Milk"}).Return(5, nil)
+
+	handler := NewHandler(&service.Service{TodoItem: items}, Config{})
+
+	r := gin.New()
+	r.POST("/lists/:id/items", func(c *gin.Context) { c.Set(userCtx, 1) }, handler.createItem)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("POST", "/lists/3/items",
+		bytes.NewBufferString(`{"title":"Milk","series_id":9,"recurrence_id":"2025-03-01T09:00:00Z","done":true}`))
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, 200, w.Code)
+	assert.Equal(t, `{"id":5}`, w.Body.String())
+}
+
+func TestHandler_getAllItems_due(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockTodoItem)
+
+	march := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	april := time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)
+
+	testTable := []struct {
+		name                string
+		query               string
+		mockBehavior        mockBehavior
+		expectedStatusCode  int
+		expectedRequestBody string
+	}{
+		{
+			name:  "Due In March",
+			query: "?due_after=2025-03-01T00:00:00Z&due_before=2025-04-01T00:00:00Z&sort=deadline",
+			mockBehavior: func(s *mock_service.MockTodoItem) {
+				s.EXPECT().GetAll(gomock.Any(), 1, 3, todo.ItemFilter{
+					DueAfter:  &march,
+					DueBefore: &april,
+					TagMatch:  todo.TagMatchAny,
+					Sort:      todo.SortByDeadline,
+					Order:     todo.OrderAsc,
+					Limit:     todo.DefaultItemsLimit,
+				}).Return(nil, "", nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `{"data":null}`,
+		},
+		{
+			name:                "Bad Due Before",
+			query:               "?due_before=tomorrow",
+			mockBehavior:        func(s *mock_service.MockTodoItem) {},
+			expectedStatusCode:  400,
+			expectedRequestBody: `{"code":"invalid_param","message":"invalid due_before param"}`,
+		},
+		{
+			name:                "Bad Due After",
+			query:               "?due_after=2025-03-01",
+			mockBehavior:        func(s *mock_service.MockTodoItem) {},
+			expectedStatusCode:  400,
+			expectedRequestBody: `{"code":"invalid_param","message":"invalid due_after param"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			items := mock_service.NewMockTodoItem(c)
+			testCase.mockBehavior(items)
+
+			handler := NewHandler(&service.Service{TodoItem: items}, Config{})
+
+			r := gin.New()
+			r.GET("/lists/:id/items", func(c *gin.Context) { c.Set(userCtx, 1) }, handler.getAllItems)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/lists/3/items"+testCase.query, nil)
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedRequestBody, w.Body.String())
+		})
+	}
+}
@@ -0,0 +1,151 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lypolix/todo-app"
+)
+
+func (h *Handler) createLabel(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		return
+	}
+
+	var input todo.Label
+	if err := c.BindJSON(&input); err != nil {
+		newErrorResponse(c, todo.InvalidInput(err))
+		return
+	}
+
+	id, err := h.services.Label.Create(c.Request.Context(), userId, input)
+	if err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, map[string]interface{}{
+		"id": id,
+	})
+}
+
+type getAllLabelsResponse struct {
+	Data []todo.Label `json:"data"`
+}
+
+func (h *Handler) getAllLabels(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		return
+	}
+
+	labels, err := h.services.Label.GetAll(c.Request.Context(), userId)
+	if err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, getAllLabelsResponse{
+		Data: labels,
+	})
+}
+
+func (h *Handler) updateLabel(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		return
+	}
+
+	labelId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		newErrorResponse(c, invalidParam("label id"))
+		return
+	}
+
+	var input todo.UpdateLabelInput
+	if err := c.BindJSON(&input); err != nil {
+		newErrorResponse(c, todo.InvalidInput(err))
+		return
+	}
+
+	if err := h.services.Label.Update(c.Request.Context(), userId, labelId, input); err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, statusResponse{"ok"})
+}
+
+func (h *Handler) deleteLabel(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		return
+	}
+
+	labelId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		newErrorResponse(c, invalidParam("label id"))
+		return
+	}
+
+	if err := h.services.Label.Delete(c.Request.Context(), userId, labelId); err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, statusResponse{"ok"})
+}
+
+func (h *Handler) attachLabel(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		return
+	}
+
+	itemId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		newErrorResponse(c, invalidParam("item id"))
+		return
+	}
+
+	labelId, err := strconv.Atoi(c.Param("labelId"))
+	if err != nil {
+		newErrorResponse(c, invalidParam("label id"))
+		return
+	}
+
+	if err := h.services.Label.Attach(c.Request.Context(), userId, itemId, labelId); err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, statusResponse{"ok"})
+}
+
+func (h *Handler) detachLabel(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		return
+	}
+
+	itemId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		newErrorResponse(c, invalidParam("item id"))
+		return
+	}
+
+	labelId, err := strconv.Atoi(c.Param("labelId"))
+	if err != nil {
+		newErrorResponse(c, invalidParam("label id"))
+		return
+	}
+
+	if err := h.services.Label.Detach(c.Request.Context(), userId, itemId, labelId); err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, statusResponse{"ok"})
+}
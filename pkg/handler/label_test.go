@@ -0,0 +1,200 @@
+package handler
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/lypolix/todo-app"
+	"github.com/lypolix/todo-app/pkg/service"
+	mock_service "github.com/lypolix/todo-app/pkg/service/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_createLabel(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockLabel)
+
+	testTable := []struct {
+		name                string
+		inputBody           string
+		mockBehavior        mockBehavior
+		expectedStatusCode  int
+		expectedRequestBody string
+	}{
+		{
+			name:      "OK",
+			inputBody: `{"name":"Home","color":"#ff0000"}`,
+			mockBehavior: func(s *mock_service.MockLabel) {
+				s.EXPECT().Create(gomock.Any(), 1, todo.Label{Name: "Home", Color: "#ff0000"}).Return(9, nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `{"id":9}`,
+		},
+		{
+			name:               "Missing Name",
+			inputBody:          `{"color":"#ff0000"}`,
+			mockBehavior:       func(s *mock_service.MockLabel) {},
+			expectedStatusCode: 400,
+		},
+		{
+			name:      "Name Taken",
+			inputBody: `{"name":"Home"}`,
+			mockBehavior: func(s *mock_service.MockLabel) {
+				s.EXPECT().Create(gomock.Any(), 1, todo.Label{Name: "Home"}).Return(0, todo.ErrLabelExists)
+			},
+			expectedStatusCode:  409,
+			expectedRequestBody: `{"code":"label_exists","message":"label with this name already exists"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			labels := mock_service.NewMockLabel(c)
+			testCase.mockBehavior(labels)
+
+			handler := NewHandler(&service.Service{Label: labels}, Config{})
+
+			r := gin.New()
+			r.POST("/labels", func(c *gin.Context) { c.Set(userCtx, 1) }, handler.createLabel)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/labels", bytes.NewBufferString(testCase.inputBody))
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			if testCase.expectedRequestBody != "" {
+				assert.Equal(t, testCase.expectedRequestBody, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestHandler_attachLabel(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockLabel)
+
+	testTable := []struct {
+		name                string
+		method              string
+		path                string
+		mockBehavior        mockBehavior
+		expectedStatusCode  int
+		expectedRequestBody string
+	}{
+		{
+			name:   "Attach",
+			method: "POST",
+			path:   "/items/5/labels/9",
+			mockBehavior: func(s *mock_service.MockLabel) {
+				s.EXPECT().Attach(gomock.Any(), 1, 5, 9).Return(nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `{"status":"ok"}`,
+		},
+		{
+			// labels of other users are not found
+			name:   "Attach Foreign Label",
+			method: "POST",
+			path:   "/items/5/labels/9",
+			mockBehavior: func(s *mock_service.MockLabel) {
+				s.EXPECT().Attach(gomock.Any(), 1, 5, 9).Return(todo.ErrLabelNotFound)
+			},
+			expectedStatusCode:  404,
+			expectedRequestBody: `{"code":"label_not_found","message":"label not found"}`,
+		},
+		{
+			name:                "Attach Bad Label Id",
+			method:              "POST",
+			path:                "/items/5/labels/home",
+			mockBehavior:        func(s *mock_service.MockLabel) {},
+			expectedStatusCode:  400,
+			expectedRequestBody: `{"code":"invalid_param","message":"invalid label id param"}`,
+		},
+		{
+			name:   "Detach",
+			method: "DELETE",
+			path:   "/items/5/labels/9",
+			mockBehavior: func(s *mock_service.MockLabel) {
+				s.EXPECT().Detach(gomock.Any(), 1, 5, 9).Return(nil)
+			},
+			expectedStatusCode:  200,
+			expectedRequestBody: `{"status":"ok"}`,
+		},
+		{
+			name:   "Detach Missing",
+			method: "DELETE",
+			path:   "/items/5/labels/9",
+			mockBehavior: func(s *mock_service.MockLabel) {
+				s.EXPECT().Detach(gomock.Any(), 1, 5, 9).Return(todo.ErrLabelNotFound)
+			},
+			expectedStatusCode:  404,
+			expectedRequestBody: `{"code":"label_not_found","message":"label not found"}`,
+		},
+		{
+			name:                "Detach Bad Item Id",
+			method:              "DELETE",
+			path:                "/items/milk/labels/9",
+			mockBehavior:        func(s *mock_service.MockLabel) {},
+			expectedStatusCode:  400,
+			expectedRequestBody: `{"code":"invalid_param","message":"invalid item id param"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			labels := mock_service.NewMockLabel(c)
+			testCase.mockBehavior(labels)
+
+			handler := NewHandler(&service.Service{Label: labels}, Config{})
+
+			r := gin.New()
+			setUser := func(c *gin.Context) { c.Set(userCtx, 1) }
+			r.POST("/items/:id/labels/:labelId", setUser, handler.attachLabel)
+			r.DELETE("/items/:id/labels/:labelId", setUser, handler.detachLabel)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(testCase.method, testCase.path, nil)
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedRequestBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_getAllItems_tags(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	// the tags are passed on as given, the service validates them
+	items := mock_service.NewMockTodoItem(c)
+	items.EXPECT().GetAll(gomock.Any(), 1, 3, todo.ItemFilter{
+		Tags:     []string{"Home", "work"},
+		TagMatch: todo.TagMatchAll,
+		Sort:     todo.SortByPosition,
+		Order:    todo.OrderAsc,
+		Limit:    todo.DefaultItemsLimit,
+	}).Return(nil, "", nil)
+
+	handler := NewHandler(&service.Service{TodoItem: items}, Config{})
+
+	r := gin.New()
+	r.GET("/lists/:id/items", func(c *gin.Context) { c.Set(userCtx, 1) }, handler.getAllItems)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/lists/3/items?tag=Home&tag=work&match=all", nil)
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, 200, w.Code)
+	assert.Equal(t, `{"data":null}`, w.Body.String())
+}
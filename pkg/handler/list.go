@@ -30,13 +30,13 @@ func (h *Handler) createList(c *gin.Context){
 
 	var input todo.TodoList
 	if err := c.BindJSON(&input); err != nil {
-		newErrorResponse(c, http.StatusBadRequest, err.Error())
+		newErrorResponse(c, todo.InvalidInput(err))
 		return 
 	}
 
-	id, err := h.services.TodoList.Create(UserId, input)
+	id, err := h.services.TodoList.Create(c.Request.Context(), UserId, input)
 	if err != nil {
-		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		newErrorResponse(c, err)
 		return 
 	}
 
@@ -57,9 +57,9 @@ func (h *Handler) getAllLists(c *gin.Context){
 		return
 	}
 
-	lists, err := h.services.TodoList.GetAll(UserId)
+	lists, err := h.services.TodoList.GetAll(c.Request.Context(), UserId)
 	if err != nil {
-		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		newErrorResponse(c, err)
 		return 
 	}
 
@@ -76,16 +76,17 @@ func (h *Handler) getListById(c *gin.Context){
 
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		newErrorResponse(c, http.StatusBadRequest, "invalid id param")
+		newErrorResponse(c, invalidParam("id"))
 		return 
 	}
 
-	list, err := h.services.TodoList.GetById(UserId, id)
+	list, err := h.services.TodoList.GetById(c.Request.Context(), UserId, id)
 	if err != nil {
-		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		newErrorResponse(c, err)
 		return 
 	}
 
+	c.Header("ETag", versionETag(list.Version))
 	c.JSON(http.StatusOK, list)
 }
 
@@ -98,18 +99,23 @@ func (h *Handler) updateList(c *gin.Context){
 
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		newErrorResponse(c, http.StatusBadRequest, "invalid id param")
+		newErrorResponse(c, invalidParam("id"))
 		return 
 	}
 
 	var input todo.UpdateListInput
 	if err := c.BindJSON(&input); err != nil {
-		newErrorResponse(c, http.StatusBadRequest, err.Error())
+		newErrorResponse(c, todo.InvalidInput(err))
 		return
 	}
 
-	if err := h.services.TodoList.Update(UserId, id, input); err != nil {
-		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+	if input.Version, err = ifMatchVersion(c); err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	if err := h.services.TodoList.Update(c.Request.Context(), UserId, id, input); err != nil {
+		newErrorResponse(c, err)
 		return
 	}
 
@@ -125,13 +131,19 @@ func (h *Handler) deleteList(c *gin.Context){
 
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		newErrorResponse(c, http.StatusBadRequest, "invalid id param")
+		newErrorResponse(c, invalidParam("id"))
 		return 
 	}
 
-	err = h.services.TodoList.Delete(UserId, id)
+	version, err := ifMatchVersion(c)
+	if err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	err = h.services.TodoList.Delete(c.Request.Context(), UserId, id, version)
 	if err != nil {
-		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		newErrorResponse(c, err)
 		return 
 	}
 
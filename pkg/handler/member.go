@@ -0,0 +1,224 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lypolix/todo-app"
+)
+
+// @Summary Add list member
+// @Security ApiKeyAuth
+// @Tags members
+// @Description share the list with another user as editor or viewer
+// @ID add-list-member
+// @Accept json
+// @Produce json
+// @Param id path int true "list id"
+// @Param input body todo.AddMemberInput true "member info"
+// @Success 200 {object} statusResponse
+// @Failure 400 {object} errorResponse
+// @Failure 403 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 409 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /api/lists/{id}/members/ [post]
+func (h *Handler) addListMember(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		return
+	}
+
+	listId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		newErrorResponse(c, invalidParam("list id"))
+		return
+	}
+
+	var input todo.AddMemberInput
+	if err := c.BindJSON(&input); err != nil {
+		newErrorResponse(c, todo.InvalidInput(err))
+		return
+	}
+
+	if err := h.services.ListMember.Add(c.Request.Context(), userId, listId, input); err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, statusResponse{"ok"})
+}
+
+type getListMembersResponse struct {
+	Data []todo.ListMember `json:"data"`
+}
+
+// @Summary Get list members
+// @Security ApiKeyAuth
+// @Tags members
+// @Description get the users the list is shared with and their roles
+// @ID get-list-members
+// @Produce json
+// @Param id path int true "list id"
+// @Success 200 {object} getListMembersResponse
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /api/lists/{id}/members/ [get]
+func (h *Handler) getListMembers(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		return
+	}
+
+	listId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		newErrorResponse(c, invalidParam("list id"))
+		return
+	}
+
+	members, err := h.services.ListMember.GetAll(c.Request.Context(), userId, listId)
+	if err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, getListMembersResponse{
+		Data: members,
+	})
+}
+
+// @Summary Update list member
+// @Security ApiKeyAuth
+// @Tags members
+// @Description change the role of a list member
+// @ID update-list-member
+// @Accept json
+// @Produce json
+// @Param id path int true "list id"
+// @Param userId path int true "member user id"
+// @Param input body todo.UpdateMemberInput true "new role"
+// @Success 200 {object} statusResponse
+// @Failure 400 {object} errorResponse
+// @Failure 403 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /api/lists/{id}/members/{userId} [put]
+func (h *Handler) updateListMember(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		return
+	}
+
+	listId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		newErrorResponse(c, invalidParam("list id"))
+		return
+	}
+
+	memberId, err := strconv.Atoi(c.Param("userId"))
+	if err != nil {
+		newErrorResponse(c, invalidParam("user id"))
+		return
+	}
+
+	var input todo.UpdateMemberInput
+	if err := c.BindJSON(&input); err != nil {
+		newErrorResponse(c, todo.InvalidInput(err))
+		return
+	}
+
+	if err := h.services.ListMember.UpdateRole(c.Request.Context(), userId, listId, memberId, input); err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, statusResponse{"ok"})
+}
+
+// @Summary Remove list member
+// @Security ApiKeyAuth
+// @Tags members
+// @Description remove a member from the list; members may remove themselves
+// @ID delete-list-member
+// @Produce json
+// @Param id path int true "list id"
+// @Param userId path int true "member user id"
+// @Success 200 {object} statusResponse
+// @Failure 400 {object} errorResponse
+// @Failure 403 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /api/lists/{id}/members/{userId} [delete]
+func (h *Handler) deleteListMember(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		return
+	}
+
+	listId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		newErrorResponse(c, invalidParam("list id"))
+		return
+	}
+
+	memberId, err := strconv.Atoi(c.Param("userId"))
+	if err != nil {
+		newErrorResponse(c, invalidParam("user id"))
+		return
+	}
+
+	if err := h.services.ListMember.Delete(c.Request.Context(), userId, listId, memberId); err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, statusResponse{"ok"})
+}
+
+// @Summary Transfer list ownership
+// @Security ApiKeyAuth
+// @Tags members
+// @Description make another member the owner; the current owner becomes an editor
+// @ID transfer-list
+// @Accept json
+// @Produce json
+// @Param id path int true "list id"
+// @Param input body todo.TransferOwnershipInput true "new owner"
+// @Success 200 {object} statusResponse
+// @Failure 400 {object} errorResponse
+// @Failure 403 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /api/lists/{id}/transfer [post]
+func (h *Handler) transferList(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		return
+	}
+
+	listId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		newErrorResponse(c, invalidParam("list id"))
+		return
+	}
+
+	var input todo.TransferOwnershipInput
+	if err := c.BindJSON(&input); err != nil {
+		newErrorResponse(c, todo.InvalidInput(err))
+		return
+	}
+
+	if err := h.services.ListMember.TransferOwnership(c.Request.Context(), userId, listId, input.UserId); err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, statusResponse{"ok"})
+}
@@ -1,51 +1,109 @@
 package handler
 
 import (
+	"context"
 	"errors"
 	"net/http"
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/lypolix/todo-app"
 )
 
 const(
 	authorizationHeader = "Authorization"
 	userCtx = "userId"
+	tokenCtx = "accessToken"
+	davChallenge = `Basic realm="todo-app", charset="UTF-8"`
+)
+
+var (
+	errEmptyAuthHeader = todo.NewError(todo.ErrUnauthorized, "empty_auth_header", "empty auth header")
+	errInvalidAuthHeader = todo.NewError(todo.ErrUnauthorized, "invalid_auth_header", "invalid auth header")
 )
 
 func (h *Handler) userIdentity(c *gin.Context){
 	header := c.GetHeader(authorizationHeader)
 	if header == "" {
-		newErrorResponse(c, http.StatusUnauthorized, "empty auth header")
+		newErrorResponse(c, errEmptyAuthHeader)
 		return 
 	}
 
 	headerParts := strings.Split(header, " ")
 	if len(headerParts) != 2 {
-		newErrorResponse(c, http.StatusUnauthorized, "invalid auth header")
+		newErrorResponse(c, errInvalidAuthHeader)
 		return 
 	}
 
-	userId, err := h.services.Authorization.ParseToken(headerParts[1])
+	userId, err := h.services.Authorization.ParseToken(c.Request.Context(), headerParts[1])
+	if err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	c.Set(userCtx, userId)
+	c.Set(tokenCtx, headerParts[1])
+}
+
+// davAuth authenticates CalDAV clients, which send the username and password
+// with every request instead of a token.
+func (h *Handler) davAuth(c *gin.Context) {
+	username, password, ok := c.Request.BasicAuth()
+	if !ok {
+		c.Header("WWW-Authenticate", davChallenge)
+		newErrorResponse(c, errEmptyAuthHeader)
+		return
+	}
+
+	userId, err := h.services.Authorization.Authenticate(c.Request.Context(), username, password)
 	if err != nil {
-		newErrorResponse(c, http.StatusUnauthorized, err.Error())
+		if errors.Is(err, todo.ErrUnauthorized) {
+			c.Header("WWW-Authenticate", davChallenge)
+		}
+		newErrorResponse(c, err)
 		return
 	}
 
 	c.Set(userCtx, userId)
 }
 
+// uploadRoute is the route of file uploads, see queryTimeout.
+const uploadRoute = "/api/items/:id/attachments/"
+
+// queryTimeout puts a deadline on the request context, which every service
+// and repository call is made with. Client disconnects cancel it as well.
+// Uploads get their own deadline, storing a file is not bound by the one of
+// the queries.
+func (h *Handler) queryTimeout(c *gin.Context) {
+	timeout := h.cfg.QueryTimeout
+	if c.Request.Method == http.MethodPost && c.FullPath() == uploadRoute {
+		timeout = h.cfg.UploadTimeout
+	}
+
+	if timeout <= 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+	defer cancel()
+
+	c.Request = c.Request.WithContext(ctx)
+	c.Next()
+}
+
 func getUserId(c *gin.Context) (int, error) {
 	id, ok := c.Get(userCtx)
 	if !ok {
-		newErrorResponse(c, http.StatusInternalServerError, "user id not found")
-		return 0, errors.New("user id not found")
+		err := errors.New("user id not found")
+		newErrorResponse(c, err)
+		return 0, err
 	}
 
 	idInt, ok := id.(int)
 	if !ok {
-		newErrorResponse(c, http.StatusInternalServerError, "user id is of invalid type")
-		return 0, errors.New("user id not found")
+		err := errors.New("user id is of invalid type")
+		newErrorResponse(c, err)
+		return 0, err
 	}
 
 	return idInt, nil
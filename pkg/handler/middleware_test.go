@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_queryTimeout(t *testing.T) {
+	testTable := []struct {
+		name             string
+		method           string
+		path             string
+		expectedDeadline time.Duration
+	}{
+		{
+			name:             "Query",
+			method:           "GET",
+			path:             "/api/items/5/attachments/",
+			expectedDeadline: time.Second,
+		},
+		{
+			name:             "Upload",
+			method:           "POST",
+			path:             "/api/items/5/attachments/",
+			expectedDeadline: time.Minute,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			handler := NewHandler(nil, Config{QueryTimeout: time.Second, UploadTimeout: time.Minute})
+
+			var left time.Duration
+			record := func(c *gin.Context) {
+				deadline, _ := c.Request.Context().Deadline()
+				left = time.Until(deadline)
+			}
+
+			r := gin.New()
+			r.Use(handler.queryTimeout)
+			r.GET(uploadRoute, record)
+			r.POST(uploadRoute, record)
+
+			r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(testCase.method, testCase.path, nil))
+
+			assert.InDelta(t, testCase.expectedDeadline, left, float64(time.Second/2))
+		})
+	}
+}
+
+func TestHandler_uploadRoute(t *testing.T) {
+	routes := NewHandler(nil, Config{}).InitRoutes().Routes()
+
+	var found bool
+	for _, route := range routes {
+		if route.Method == "POST" && route.Path == uploadRoute {
+			found = true
+		}
+	}
+
+	assert.True(t, found, "uploads are served at uploadRoute")
+}
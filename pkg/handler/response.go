@@ -1,21 +1,96 @@
 package handler
 
 import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
 	"github.com/gin-gonic/gin"
+	"github.com/lypolix/todo-app"
 	"github.com/sirupsen/logrus"
 )
 
 type errorResponse struct {
-	Message string `json:message`
+	Code    string `json:"code"`
+	Message string `json:"message"`
 }
 
 type statusResponse struct {
 	Status string `json:"status"`
 }
 
-func newErrorResponse(c *gin.Context, statusCode int, message string) {
-	logrus.Error(message)
-	c.AbortWithStatusJSON(statusCode, errorResponse{message})
+// errorStatuses maps the kinds of domain errors to HTTP status codes.
+var errorStatuses = map[error]int{
+	todo.ErrNotFound:     http.StatusNotFound,
+	todo.ErrConflict:     http.StatusConflict,
+	todo.ErrInvalidInput: http.StatusBadRequest,
+	todo.ErrUnauthorized: http.StatusUnauthorized,
+	todo.ErrForbidden:    http.StatusForbidden,
+	todo.ErrTooLarge:     http.StatusRequestEntityTooLarge,
+	todo.ErrPrecondition: http.StatusPreconditionFailed,
+}
+
+var (
+	errInternal = errorResponse{Code: "internal_error", Message: "internal server error"}
+	errTimeout  = errorResponse{Code: "timeout", Message: "request timed out"}
+)
+
+// newErrorResponse aborts the request with the status code and body of err.
+// Anything that is not a domain error is reported as an internal error and
+// only its log entry carries the details.
+func newErrorResponse(c *gin.Context, err error) {
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(c.Request.Context().Err(), context.DeadlineExceeded) {
+		logrus.Error(err.Error())
+		c.AbortWithStatusJSON(http.StatusGatewayTimeout, errTimeout)
+		return
+	}
+
+	var domainErr *todo.Error
+	if !errors.As(err, &domainErr) {
+		logrus.Error(err.Error())
+		c.AbortWithStatusJSON(http.StatusInternalServerError, errInternal)
+		return
+	}
+
+	status, ok := errorStatuses[domainErr.Kind]
+	if !ok {
+		logrus.Error(err.Error())
+		c.AbortWithStatusJSON(http.StatusInternalServerError, errInternal)
+		return
+	}
+
+	c.AbortWithStatusJSON(status, errorResponse{Code: domainErr.Code, Message: domainErr.Message})
+}
+
+// invalidParam is the error of a path or query parameter that cannot be parsed.
+func invalidParam(name string) error {
+	return todo.NewError(todo.ErrInvalidInput, "invalid_param", "invalid "+name+" param")
 }
 
+// versionETag is the entity tag of a list or an item at a version.
+func versionETag(version int) string {
+	return `"` + strconv.Itoa(version) + `"`
+}
 
+// ifMatchVersion reads the version a change is conditional on from the
+// If-Match header, nil without one or for "*". Anything else than the tag
+// of a single version can never match.
+func ifMatchVersion(c *gin.Context) (*int, error) {
+	header := strings.TrimSpace(c.GetHeader("If-Match"))
+	if header == "" || header == "*" {
+		return nil, nil
+	}
+
+	tag, ok := strings.CutPrefix(header, `"`)
+	if ok {
+		tag, ok = strings.CutSuffix(tag, `"`)
+	}
+	version, err := strconv.Atoi(tag)
+	if !ok || err != nil {
+		return nil, todo.ErrPreconditionFailed
+	}
+
+	return &version, nil
+}
@@ -0,0 +1,46 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lypolix/todo-app"
+)
+
+type searchResponse struct {
+	Data []todo.SearchResult `json:"data"`
+}
+
+// search looks for the words of the q query parameter in every list the
+// user is a member of, the best matches come first.
+func (h *Handler) search(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		return
+	}
+
+	query := todo.SearchQuery{
+		Query: c.Query("q"),
+		Limit: todo.DefaultSearchLimit,
+	}
+
+	if value, ok := c.GetQuery("limit"); ok {
+		limit, err := strconv.Atoi(value)
+		if err != nil {
+			newErrorResponse(c, invalidParam("limit"))
+			return
+		}
+		query.Limit = limit
+	}
+
+	results, err := h.services.Search.Search(c.Request.Context(), userId, query)
+	if err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, searchResponse{
+		Data: results,
+	})
+}
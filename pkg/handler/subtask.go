@@ -0,0 +1,123 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lypolix/todo-app"
+)
+
+func (h *Handler) createSubtask(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		return
+	}
+
+	itemId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		newErrorResponse(c, invalidParam("item id"))
+		return
+	}
+
+	var input todo.Subtask
+	if err := c.BindJSON(&input); err != nil {
+		newErrorResponse(c, todo.InvalidInput(err))
+		return
+	}
+
+	id, err := h.services.Subtask.Create(c.Request.Context(), userId, itemId, input)
+	if err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, map[string]interface{}{
+		"id": id,
+	})
+}
+
+type getAllSubtasksResponse struct {
+	Data []todo.Subtask `json:"data"`
+}
+
+func (h *Handler) getAllSubtasks(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		return
+	}
+
+	itemId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		newErrorResponse(c, invalidParam("item id"))
+		return
+	}
+
+	subtasks, err := h.services.Subtask.GetAll(c.Request.Context(), userId, itemId)
+	if err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, getAllSubtasksResponse{
+		Data: subtasks,
+	})
+}
+
+func (h *Handler) updateSubtask(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		return
+	}
+
+	itemId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		newErrorResponse(c, invalidParam("item id"))
+		return
+	}
+
+	subtaskId, err := strconv.Atoi(c.Param("subtaskId"))
+	if err != nil {
+		newErrorResponse(c, invalidParam("subtask id"))
+		return
+	}
+
+	var input todo.UpdateSubtaskInput
+	if err := c.BindJSON(&input); err != nil {
+		newErrorResponse(c, todo.InvalidInput(err))
+		return
+	}
+
+	if err := h.services.Subtask.Update(c.Request.Context(), userId, itemId, subtaskId, input); err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, statusResponse{"ok"})
+}
+
+func (h *Handler) deleteSubtask(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		return
+	}
+
+	itemId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		newErrorResponse(c, invalidParam("item id"))
+		return
+	}
+
+	subtaskId, err := strconv.Atoi(c.Param("subtaskId"))
+	if err != nil {
+		newErrorResponse(c, invalidParam("subtask id"))
+		return
+	}
+
+	if err := h.services.Subtask.Delete(c.Request.Context(), userId, itemId, subtaskId); err != nil {
+		newErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, statusResponse{"ok"})
+}
@@ -0,0 +1,296 @@
+// Package ical reads and writes iCalendar (RFC 5545) data. It knows the
+// syntax of components and properties, what they mean is up to the caller.
+package ical
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Component is a calendar object such as VCALENDAR, VTODO or VEVENT.
+type Component struct {
+	Name       string
+	Properties []Property
+	Components []Component
+}
+
+// Property is a content line. Value is kept raw, see Text and Time.
+type Property struct {
+	Name   string
+	Params map[string]string
+	Value  string
+}
+
+// ErrInvalid is wrapped by every error about malformed data.
+var ErrInvalid = errors.New("invalid icalendar data")
+
+const (
+	dateTimeFormat    = "20060102T150405Z"
+	localDateTimeForm = "20060102T150405"
+	dateFormat        = "20060102"
+	// lines are folded after this many octets, without the line break
+	maxLineLength = 75
+)
+
+// Add appends a property with a raw value.
+func (c *Component) Add(name, value string) {
+	c.Properties = append(c.Properties, Property{Name: name, Value: value})
+}
+
+// AddText appends a property with a text value, escaping it.
+func (c *Component) AddText(name, text string) {
+	c.Add(name, escapeText(text))
+}
+
+// AddTime appends a property with a date-time in UTC.
+func (c *Component) AddTime(name string, t time.Time) {
+	c.Add(name, t.UTC().Format(dateTimeFormat))
+}
+
+// Get returns the first property with the name.
+func (c Component) Get(name string) (Property, bool) {
+	for _, p := range c.Properties {
+		if p.Name == name {
+			return p, true
+		}
+	}
+
+	return Property{}, false
+}
+
+// Text is the unescaped text value of the first property with the name, or
+// an empty string.
+func (c Component) Text(name string) string {
+	p, ok := c.Get(name)
+	if !ok {
+		return ""
+	}
+
+	return p.Text()
+}
+
+func (p Property) Text() string {
+	var b strings.Builder
+	escaped := false
+	for _, r := range p.Value {
+		if escaped {
+			switch r {
+			case 'n', 'N':
+				b.WriteRune('\n')
+			default:
+				b.WriteRune(r)
+			}
+			escaped = false
+			continue
+		}
+
+		if r == '\\' {
+			escaped = true
+			continue
+		}
+		b.WriteRune(r)
+	}
+
+	return b.String()
+}
+
+// Time reads a DATE or DATE-TIME value. Local times are taken in the zone
+// of the TZID parameter when the zone is known and in UTC otherwise, dates
+// are midnight UTC.
+func (p Property) Time() (time.Time, error) {
+	value := p.Value
+	if p.Params["VALUE"] == "DATE" || len(value) == len(dateFormat) {
+		return time.Parse(dateFormat, value)
+	}
+
+	if strings.HasSuffix(value, "Z") {
+		return time.Parse(dateTimeFormat, value)
+	}
+
+	location := time.UTC
+	if tzid := p.Params["TZID"]; tzid != "" {
+		if l, err := time.LoadLocation(strings.Trim(tzid, `"`)); err == nil {
+			location = l
+		}
+	}
+
+	return time.ParseInLocation(localDateTimeForm, value, location)
+}
+
+// Encode writes the component with CRLF line breaks and long lines folded.
+func (c Component) Encode(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	c.encode(bw)
+	return bw.Flush()
+}
+
+func (c Component) encode(w *bufio.Writer) {
+	writeLine(w, "BEGIN:"+c.Name)
+	for _, p := range c.Properties {
+		var b strings.Builder
+		b.WriteString(p.Name)
+		// sorted, so the same component is always written the same way
+		names := make([]string, 0, len(p.Params))
+		for name := range p.Params {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			fmt.Fprintf(&b, ";%s=%s", name, p.Params[name])
+		}
+		b.WriteString(":")
+		b.WriteString(p.Value)
+		writeLine(w, b.String())
+	}
+	for _, child := range c.Components {
+		child.encode(w)
+	}
+	writeLine(w, "END:"+c.Name)
+}
+
+// writeLine folds the line so no part is longer than maxLineLength octets,
+// without splitting a UTF-8 sequence.
+func writeLine(w *bufio.Writer, line string) {
+	limit := maxLineLength
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		w.WriteString(line[:cut])
+		w.WriteString("\r\n ")
+		line = line[cut:]
+		// the leading space of a continuation line counts too
+		limit = maxLineLength - 1
+	}
+	w.WriteString(line)
+	w.WriteString("\r\n")
+}
+
+func escapeText(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`).Replace(s)
+}
+
+// Parse reads one top-level component, usually a VCALENDAR.
+func Parse(r io.Reader) (Component, error) {
+	lines, err := unfold(r)
+	if err != nil {
+		return Component{}, err
+	}
+
+	var stack []Component
+	for i, line := range lines {
+		if line == "" {
+			continue
+		}
+
+		p, err := parseLine(line)
+		if err != nil {
+			return Component{}, fmt.Errorf("%w: line %d: %s", ErrInvalid, i+1, err.Error())
+		}
+
+		switch p.Name {
+		case "BEGIN":
+			stack = append(stack, Component{Name: strings.ToUpper(p.Value)})
+		case "END":
+			if len(stack) == 0 || stack[len(stack)-1].Name != strings.ToUpper(p.Value) {
+				return Component{}, fmt.Errorf("%w: line %d: unexpected END:%s", ErrInvalid, i+1, p.Value)
+			}
+			done := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if len(stack) == 0 {
+				return done, nil
+			}
+			parent := &stack[len(stack)-1]
+			parent.Components = append(parent.Components, done)
+		default:
+			if len(stack) == 0 {
+				return Component{}, fmt.Errorf("%w: line %d: property outside of a component", ErrInvalid, i+1)
+			}
+			current := &stack[len(stack)-1]
+			current.Properties = append(current.Properties, p)
+		}
+	}
+
+	return Component{}, fmt.Errorf("%w: unexpected end of data", ErrInvalid)
+}
+
+// unfold joins folded lines, continuation lines start with a space or a tab.
+func unfold(r io.Reader) ([]string, error) {
+	var lines []string
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSuffix(scanner.Text(), "\r")
+		if len(lines) > 0 && line != "" && (line[0] == ' ' || line[0] == '\t') {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalid, err.Error())
+	}
+
+	return lines, nil
+}
+
+// parseLine splits a content line into name, parameters and value. Quoted
+// parameter values may contain the separators.
+func parseLine(line string) (Property, error) {
+	p := Property{}
+
+	nameEnd := strings.IndexAny(line, ";:")
+	if nameEnd <= 0 {
+		return p, errors.New("missing property name")
+	}
+	p.Name = strings.ToUpper(line[:nameEnd])
+
+	rest := line[nameEnd:]
+	for strings.HasPrefix(rest, ";") {
+		rest = rest[1:]
+		eq := strings.IndexByte(rest, '=')
+		if eq <= 0 {
+			return p, errors.New("invalid parameter")
+		}
+		name := strings.ToUpper(rest[:eq])
+		rest = rest[eq+1:]
+
+		var value string
+		if strings.HasPrefix(rest, `"`) {
+			end := strings.IndexByte(rest[1:], '"')
+			if end < 0 {
+				return p, errors.New("unterminated quoted parameter")
+			}
+			value = rest[1 : end+1]
+			rest = rest[end+2:]
+		} else {
+			end := strings.IndexAny(rest, ";:")
+			if end < 0 {
+				return p, errors.New("missing value")
+			}
+			value = rest[:end]
+			rest = rest[end:]
+		}
+
+		if p.Params == nil {
+			p.Params = make(map[string]string)
+		}
+		p.Params[name] = value
+	}
+
+	if !strings.HasPrefix(rest, ":") {
+		return p, errors.New("missing value")
+	}
+	p.Value = rest[1:]
+
+	return p, nil
+}
@@ -0,0 +1,113 @@
+package ical
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncodeParse(t *testing.T) {
+	due := time.Date(2025, time.August, 20, 15, 0, 0, 0, time.UTC)
+
+	todo := Component{Name: "VTODO"}
+	todo.Add("UID", "item-1@todo-app")
+	todo.AddText("SUMMARY", "Отчёт; черновик, v2")
+	todo.AddText("DESCRIPTION", strings.Repeat("длинное описание ", 10)+"\nc:\\temp")
+	todo.AddTime("DUE", due)
+	calendar := Component{Name: "VCALENDAR", Components: []Component{todo}}
+
+	var buf bytes.Buffer
+	assert.NoError(t, calendar.Encode(&buf))
+
+	for _, line := range strings.Split(strings.TrimSuffix(buf.String(), "\r\n"), "\r\n") {
+		assert.LessOrEqual(t, len(line), 75, line)
+	}
+
+	parsed, err := Parse(&buf)
+	if !assert.NoError(t, err) || !assert.Len(t, parsed.Components, 1) {
+		return
+	}
+
+	vtodo := parsed.Components[0]
+	assert.Equal(t, "VTODO", vtodo.Name)
+	assert.Equal(t, "Отчёт; черновик, v2", vtodo.Text("SUMMARY"))
+	assert.Equal(t, strings.Repeat("длинное описание ", 10)+"\nc:\\temp", vtodo.Text("DESCRIPTION"))
+
+	p, ok := vtodo.Get("DUE")
+	if assert.True(t, ok) {
+		parsedDue, err := p.Time()
+		assert.NoError(t, err)
+		assert.True(t, due.Equal(parsedDue))
+	}
+}
+
+func TestPropertyTime(t *testing.T) {
+	moscow, err := time.LoadLocation("Europe/Moscow")
+	if err != nil {
+		t.Skip("no time zone data")
+	}
+
+	testTable := []struct {
+		name     string
+		line     string
+		expected time.Time
+	}{
+		{
+			name:     "utc",
+			line:     "DUE:20250820T150000Z",
+			expected: time.Date(2025, time.August, 20, 15, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "time zone",
+			line:     `DTSTART;TZID="Europe/Moscow":20250820T150000`,
+			expected: time.Date(2025, time.August, 20, 15, 0, 0, 0, moscow),
+		},
+		{
+			name:     "unknown time zone",
+			line:     "DTSTART;TZID=Mars/Olympus:20250820T150000",
+			expected: time.Date(2025, time.August, 20, 15, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "date",
+			line:     "DTSTART;VALUE=DATE:20250820",
+			expected: time.Date(2025, time.August, 20, 0, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			p, err := parseLine(testCase.line)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			value, err := p.Time()
+
+			assert.NoError(t, err)
+			assert.True(t, testCase.expected.Equal(value), "expected %s, got %s", testCase.expected, value)
+		})
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	testTable := []struct {
+		name string
+		data string
+	}{
+		{name: "unterminated", data: "BEGIN:VCALENDAR\r\nBEGIN:VTODO\r\nSUMMARY:x\r\nEND:VTODO\r\n"},
+		{name: "mismatched end", data: "BEGIN:VCALENDAR\r\nEND:VTODO\r\n"},
+		{name: "no value", data: "BEGIN:VCALENDAR\r\nSUMMARY\r\nEND:VCALENDAR\r\n"},
+		{name: "not icalendar", data: "hello"},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			_, err := Parse(strings.NewReader(testCase.data))
+
+			assert.ErrorIs(t, err, ErrInvalid)
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lypolix/todo-app"
+)
+
+// activitySort tells activity cursors apart from item cursors.
+const activitySort = "activity"
+
+type ActivityPostgres struct {
+	db *sqlx.DB
+}
+
+func NewActivityPostgres(db *sqlx.DB) *ActivityPostgres {
+	return &ActivityPostgres{db: db}
+}
+
+// GetByList returns the changes of the list and its items, newest first.
+func (r *ActivityPostgres) GetByList(ctx context.Context, userId, listId int, page todo.Page) ([]todo.Activity, string, error) {
+	if err := requireListRole(ctx, r.db, userId, listId, todo.RoleOwner, todo.RoleEditor, todo.RoleViewer); err != nil {
+		return nil, "", err
+	}
+
+	return r.selectActivity(ctx, "list_id", listId, page)
+}
+
+// GetByItem returns the changes of the item, newest first.
+func (r *ActivityPostgres) GetByItem(ctx context.Context, userId, itemId int, page todo.Page) ([]todo.Activity, string, error) {
+	if err := requireItemRole(ctx, r.db, userId, itemId, todo.RoleOwner, todo.RoleEditor, todo.RoleViewer); err != nil {
+		return nil, "", err
+	}
+
+	return r.selectActivity(ctx, "item_id", itemId, page)
+}
+
+func (r *ActivityPostgres) selectActivity(ctx context.Context, column string, id int, page todo.Page) ([]todo.Activity, string, error) {
+	conditions := fmt.Sprintf("%s = $1", column)
+	args := []interface{}{id}
+
+	if page.Cursor != "" {
+		after, err := decodeCursor(page.Cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		if after.Sort != activitySort {
+			return nil, "", todo.ErrInvalidCursor
+		}
+
+		conditions += " AND id < $2"
+		args = append(args, after.Id)
+	}
+
+	// one extra row tells whether there is a next page
+	query := fmt.Sprintf(`SELECT id, actor_id, list_id, item_id, action, changes, created_at FROM %s
+							WHERE %s ORDER BY id DESC LIMIT %d`, activityTable, conditions, page.Limit+1)
+
+	activity := make([]todo.Activity, 0)
+	if err := r.db.SelectContext(ctx, &activity, query, args...); err != nil {
+		return nil, "", err
+	}
+
+	if len(activity) <= page.Limit {
+		return activity, "", nil
+	}
+
+	activity = activity[:page.Limit]
+
+	return activity, encodeCursor(cursor{Sort: activitySort, Id: activity[len(activity)-1].Id}), nil
+}
+
+// logActivity records a change, it is meant to run in the transaction of
+// the change itself.
+func logActivity(ctx context.Context, e sqlx.ExecerContext, activity todo.Activity) error {
+	query := fmt.Sprintf("INSERT INTO %s (actor_id, list_id, item_id, action, changes) VALUES ($1, $2, $3, $4, $5)", activityTable)
+	_, err := e.ExecContext(ctx, query, activity.ActorId, activity.ListId, activity.ItemId, activity.Action, activity.Changes)
+
+	return err
+}
+
+// addChange records the field in changes if its value differs.
+func addChange(changes todo.Changes, field string, old, new interface{}) {
+	old, new = changeValue(old), changeValue(new)
+
+	oldData, _ := json.Marshal(old)
+	newData, _ := json.Marshal(new)
+	if !bytes.Equal(oldData, newData) {
+		changes[field] = todo.Change{Old: old, New: new}
+	}
+}
+
+// changeValue brings times to UTC, so the same instant does not look like a change.
+func changeValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case time.Time:
+		return v.UTC()
+	case *time.Time:
+		if v == nil {
+			return nil
+		}
+		return v.UTC()
+	}
+
+	return v
+}
@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lypolix/todo-app"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestItemChanges(t *testing.T) {
+	deadline := time.Date(2025, time.January, 6, 9, 0, 0, 0, time.UTC)
+	sameDeadline := deadline.In(time.FixedZone("MSK", 3*60*60))
+	laterDeadline := deadline.Add(time.Hour)
+
+	before := todo.TodoItem{Title: "report", Deadline: &deadline}
+
+	testTable := []struct {
+		name     string
+		after    todo.TodoItem
+		expected todo.Changes
+	}{
+		{
+			name:     "nothing changed",
+			after:    todo.TodoItem{Title: "report", Deadline: &sameDeadline},
+			expected: todo.Changes{},
+		},
+		{
+			name:  "fields changed",
+			after: todo.TodoItem{Title: "weekly report", Done: true, Deadline: &laterDeadline, Priority: todo.PriorityHigh},
+			expected: todo.Changes{
+				"title":    {Old: "report", New: "weekly report"},
+				"done":     {Old: false, New: true},
+				"deadline": {Old: deadline, New: laterDeadline},
+				"priority": {Old: todo.PriorityNone, New: todo.PriorityHigh},
+			},
+		},
+		{
+			name:  "deadline removed",
+			after: todo.TodoItem{Title: "report"},
+			expected: todo.Changes{
+				"deadline": {Old: deadline, New: nil},
+			},
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			assert.Equal(t, testCase.expected, itemChanges(before, testCase.after))
+		})
+	}
+}
@@ -0,0 +1,145 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lypolix/todo-app"
+)
+
+const attachmentColumns = `id, item_id, user_id, file_name, content_type, size, created_at, storage_key`
+
+type AttachmentPostgres struct {
+	db *sqlx.DB
+}
+
+func NewAttachmentPostgres(db *sqlx.DB) *AttachmentPostgres {
+	return &AttachmentPostgres{db: db}
+}
+
+// CheckUpload tells whether the user may upload a file of size to the item,
+// so a file that would be rejected is not stored first. Create checks again
+// once the file is stored.
+func (r *AttachmentPostgres) CheckUpload(ctx context.Context, userId, itemId int, size, quota int64) error {
+	if err := requireItemRole(ctx, r.db, userId, itemId, todo.RoleOwner, todo.RoleEditor); err != nil {
+		return err
+	}
+
+	if quota > 0 {
+		return checkQuota(ctx, r.db, userId, size, quota)
+	}
+
+	return nil
+}
+
+// Create saves the metadata of a file uploaded to the item. The uploader is
+// locked while their usage is summed up, so parallel uploads cannot exceed
+// the quota together. A quota of zero or less is no limit.
+func (r *AttachmentPostgres) Create(ctx context.Context, userId, itemId int, attachment todo.Attachment, quota int64) (int, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := requireItemRole(ctx, tx, userId, itemId, todo.RoleOwner, todo.RoleEditor); err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	if quota > 0 {
+		lockQuery := fmt.Sprintf("SELECT id FROM %s WHERE id = $1 FOR UPDATE", usersTable)
+		if _, err := tx.ExecContext(ctx, lockQuery, userId); err != nil {
+			tx.Rollback()
+			return 0, err
+		}
+
+		if err := checkQuota(ctx, tx, userId, attachment.Size, quota); err != nil {
+			tx.Rollback()
+			return 0, err
+		}
+	}
+
+	var id int
+	query := fmt.Sprintf(`INSERT INTO %s (item_id, user_id, file_name, content_type, size, storage_key)
+							VALUES ($1, $2, $3, $4, $5, $6) RETURNING id`, attachmentsTable)
+	if err := tx.QueryRowContext(ctx, query, itemId, userId, attachment.FileName, attachment.ContentType,
+		attachment.Size, attachment.StorageKey).Scan(&id); err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	return id, tx.Commit()
+}
+
+func (r *AttachmentPostgres) GetAll(ctx context.Context, userId, itemId int) ([]todo.Attachment, error) {
+	if err := requireItemRole(ctx, r.db, userId, itemId, todo.RoleOwner, todo.RoleEditor, todo.RoleViewer); err != nil {
+		return nil, err
+	}
+
+	attachments := make([]todo.Attachment, 0)
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE item_id = $1 ORDER BY created_at, id", attachmentColumns, attachmentsTable)
+	if err := r.db.SelectContext(ctx, &attachments, query, itemId); err != nil {
+		return nil, err
+	}
+
+	return attachments, nil
+}
+
+func (r *AttachmentPostgres) GetById(ctx context.Context, userId, itemId, attachmentId int) (todo.Attachment, error) {
+	var attachment todo.Attachment
+	if err := requireItemRole(ctx, r.db, userId, itemId, todo.RoleOwner, todo.RoleEditor, todo.RoleViewer); err != nil {
+		return attachment, err
+	}
+
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE id = $1 AND item_id = $2", attachmentColumns, attachmentsTable)
+	err := r.db.GetContext(ctx, &attachment, query, attachmentId, itemId)
+
+	return attachment, notFound(err, todo.ErrAttachmentNotFound)
+}
+
+// Delete removes the metadata and returns it, so the caller can remove the
+// file from the storage as well.
+func (r *AttachmentPostgres) Delete(ctx context.Context, userId, itemId, attachmentId int) (todo.Attachment, error) {
+	var attachment todo.Attachment
+	if err := requireItemRole(ctx, r.db, userId, itemId, todo.RoleOwner, todo.RoleEditor); err != nil {
+		return attachment, err
+	}
+
+	query := fmt.Sprintf("DELETE FROM %s WHERE id = $1 AND item_id = $2 RETURNING %s", attachmentsTable, attachmentColumns)
+	err := r.db.GetContext(ctx, &attachment, query, attachmentId, itemId)
+
+	return attachment, notFound(err, todo.ErrAttachmentNotFound)
+}
+
+// GetDetached returns the storage keys of the files whose items were purged.
+func (r *AttachmentPostgres) GetDetached(ctx context.Context) ([]string, error) {
+	keys := make([]string, 0)
+	query := fmt.Sprintf("SELECT storage_key FROM %s WHERE item_id IS NULL", attachmentsTable)
+	err := r.db.SelectContext(ctx, &keys, query)
+
+	return keys, err
+}
+
+func (r *AttachmentPostgres) DeleteDetached(ctx context.Context, storageKey string) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE storage_key = $1 AND item_id IS NULL", attachmentsTable)
+	_, err := r.db.ExecContext(ctx, query, storageKey)
+
+	return err
+}
+
+// checkQuota fails with todo.ErrQuotaExceeded when size more bytes would take
+// the user's files over quota.
+func checkQuota(ctx context.Context, q sqlx.QueryerContext, userId int, size, quota int64) error {
+	var used int64
+	usageQuery := fmt.Sprintf("SELECT COALESCE(SUM(size), 0) FROM %s WHERE user_id = $1", attachmentsTable)
+	if err := sqlx.GetContext(ctx, q, &used, usageQuery, userId); err != nil {
+		return err
+	}
+
+	if used+size > quota {
+		return todo.ErrQuotaExceeded
+	}
+
+	return nil
+}
@@ -1,7 +1,10 @@
 package repository
 
 import (
+	"context"
+	"database/sql"
 	"fmt"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 	"github.com/lypolix/todo-app"
@@ -15,23 +18,146 @@ func NewAuthPostgres(db *sqlx.DB) *AuthPostgres {
 	return &AuthPostgres{db: db}
 }	
 
-func (r *AuthPostgres) CreateUser(user todo.User) (int, error) {
+func (r *AuthPostgres) CreateUser(ctx context.Context, user todo.User) (int, error) {
 	var id int
 	query := fmt.Sprintf("INSERT INTO %s(name, username, password_hash) values ($1, $2, $3) RETURNING id", usersTable)
 
-	row:= r.db.QueryRow(query, user.Name, user.Username, user.Password)
+	row:= r.db.QueryRowContext(ctx, query, user.Name, user.Username, user.PasswordHash)
 	if err := row.Scan(&id); err != nil {
+		if isUniqueViolation(err) {
+			return 0, todo.ErrUsernameTaken
+		}
 		return 0, err
 	}
 	return id, nil
 }
 
-func (r *AuthPostgres) GetUser(username, password string) (todo.User, error){
+func (r *AuthPostgres) GetUser(ctx context.Context, username string) (todo.User, error){
 	var user todo.User
-	query := fmt.Sprintf("SELECT id FROM %s WHERE username=$1 AND password_hash=$2", usersTable)
-	err := r.db.Get(&user, query, username, password)
+	query := fmt.Sprintf("SELECT id, password_hash FROM %s WHERE username=$1", usersTable)
+	err := r.db.GetContext(ctx, &user, query, username)
 
 	return user, err
+}
+
+func (r *AuthPostgres) UpdatePasswordHash(ctx context.Context, userId int, passwordHash string) error {
+	query := fmt.Sprintf("UPDATE %s SET password_hash=$1 WHERE id=$2", usersTable)
+	_, err := r.db.ExecContext(ctx, query, passwordHash, userId)
+
+	return err
+}
+
+func (r *AuthPostgres) CreateRefreshToken(ctx context.Context, token todo.RefreshToken) error {
+	query := fmt.Sprintf("INSERT INTO %s (user_id, token_hash, expires_at) VALUES ($1, $2, $3)", refreshTokensTable)
+	_, err := r.db.ExecContext(ctx, query, token.UserId, token.TokenHash, token.ExpiresAt)
+
+	return err
+}
+
+func (r *AuthPostgres) GetRefreshToken(ctx context.Context, tokenHash string) (todo.RefreshToken, error) {
+	var token todo.RefreshToken
+	query := fmt.Sprintf("SELECT id, user_id, token_hash, expires_at, revoked_at, replaced_by FROM %s WHERE token_hash=$1", refreshTokensTable)
+	err := r.db.GetContext(ctx, &token, query, tokenHash)
+
+	return token, err
+}
+
+// RotateRefreshToken revokes the token and stores its replacement in one transaction.
+// It returns sql.ErrNoRows when the token has already been used concurrently.
+func (r *AuthPostgres) RotateRefreshToken(ctx context.Context, tokenId int, next todo.RefreshToken) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	var nextId int
+	createQuery := fmt.Sprintf("INSERT INTO %s (user_id, token_hash, expires_at) VALUES ($1, $2, $3) RETURNING id", refreshTokensTable)
+	if err := tx.QueryRowContext(ctx, createQuery, next.UserId, next.TokenHash, next.ExpiresAt).Scan(&nextId); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	revokeQuery := fmt.Sprintf("UPDATE %s SET revoked_at=now(), replaced_by=$1 WHERE id=$2 AND revoked_at IS NULL", refreshTokensTable)
+	res, err := tx.ExecContext(ctx, revokeQuery, nextId, tokenId)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if rows, err := res.RowsAffected(); err != nil || rows == 0 {
+		tx.Rollback()
+		if err == nil {
+			err = sql.ErrNoRows
+		}
+		return err
+	}
+
+	return tx.Commit()
+}
 
+func (r *AuthPostgres) RevokeRefreshToken(ctx context.Context, userId int, tokenHash string) error {
+	query := fmt.Sprintf("UPDATE %s SET revoked_at=now() WHERE user_id=$1 AND token_hash=$2 AND revoked_at IS NULL", refreshTokensTable)
+	_, err := r.db.ExecContext(ctx, query, userId, tokenHash)
 
-}
\ No newline at end of file
+	return err
+}
+
+// RevokeAccessToken denylists the jti until the token expires on its own,
+// dropping denylist entries that are no longer needed.
+func (r *AuthPostgres) RevokeAccessToken(ctx context.Context, jti string, expiresAt time.Time) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	cleanupQuery := fmt.Sprintf("DELETE FROM %s WHERE expires_at < now()", revokedTokensTable)
+	if _, err := tx.ExecContext(ctx, cleanupQuery); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	revokeQuery := fmt.Sprintf("INSERT INTO %s (jti, expires_at) VALUES ($1, $2) ON CONFLICT (jti) DO NOTHING", revokedTokensTable)
+	if _, err := tx.ExecContext(ctx, revokeQuery, jti, expiresAt); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// RevokeAllTokens revokes every refresh token of the user and invalidates all
+// access tokens issued up to now.
+func (r *AuthPostgres) RevokeAllTokens(ctx context.Context, userId int) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	revokeRefreshQuery := fmt.Sprintf("UPDATE %s SET revoked_at=now() WHERE user_id=$1 AND revoked_at IS NULL", refreshTokensTable)
+	if _, err := tx.ExecContext(ctx, revokeRefreshQuery, userId); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	revokeAccessQuery := fmt.Sprintf("UPDATE %s SET tokens_revoked_at=now() WHERE id=$1", usersTable)
+	if _, err := tx.ExecContext(ctx, revokeAccessQuery, userId); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// IsTokenRevoked checks the jti and the time the user last revoked all tokens.
+// The iat claim only has seconds, so the revocation time is truncated too and
+// every token issued in the second of the revocation is revoked with it, as it
+// may have been issued before.
+func (r *AuthPostgres) IsTokenRevoked(ctx context.Context, userId int, jti string, issuedAt time.Time) (bool, error) {
+	var revoked bool
+	query := fmt.Sprintf(`SELECT EXISTS (SELECT 1 FROM %s WHERE jti=$1)
+							OR EXISTS (SELECT 1 FROM %s WHERE id=$2 AND date_trunc('second', tokens_revoked_at) >= $3)`,
+		revokedTokensTable, usersTable)
+	err := r.db.GetContext(ctx, &revoked, query, jti, userId, issuedAt)
+
+	return revoked, err
+}
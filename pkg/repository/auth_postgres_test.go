@@ -0,0 +1,149 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lypolix/todo-app"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuthPostgres_RotateRefreshToken(t *testing.T) {
+	expiresAt := time.Now().Add(time.Hour)
+	next := todo.RefreshToken{UserId: 2, TokenHash: "next", ExpiresAt: expiresAt}
+
+	testTable := []struct {
+		name         string
+		mockBehavior func(mock sqlmock.Sqlmock)
+		expectedErr  error
+	}{
+		{
+			name: "OK",
+			mockBehavior: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta("INSERT INTO refresh_tokens (user_id, token_hash, expires_at)")).
+					WithArgs(2, "next", expiresAt).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(8))
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE refresh_tokens SET revoked_at=now(), replaced_by=$1 WHERE id=$2 AND revoked_at IS NULL")).
+					WithArgs(8, 7).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			// another request rotated the token first
+			name: "Already Rotated",
+			mockBehavior: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta("INSERT INTO refresh_tokens (user_id, token_hash, expires_at)")).
+					WithArgs(2, "next", expiresAt).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(8))
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE refresh_tokens SET revoked_at=now(), replaced_by=$1 WHERE id=$2 AND revoked_at IS NULL")).
+					WithArgs(8, 7).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectRollback()
+			},
+			expectedErr: sql.ErrNoRows,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			db, mock := newMockDB(t)
+			testCase.mockBehavior(mock)
+
+			err := NewAuthPostgres(db).RotateRefreshToken(context.Background(), 7, next)
+
+			assert.ErrorIs(t, err, testCase.expectedErr)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestAuthPostgres_revoke(t *testing.T) {
+	expiresAt := time.Now().Add(time.Minute)
+
+	testTable := []struct {
+		name         string
+		mockBehavior func(mock sqlmock.Sqlmock)
+		revoke       func(r *AuthPostgres) error
+	}{
+		{
+			name: "Logout Refresh Token",
+			mockBehavior: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE refresh_tokens SET revoked_at=now() WHERE user_id=$1 AND token_hash=$2 AND revoked_at IS NULL")).
+					WithArgs(2, "hash").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			revoke: func(r *AuthPostgres) error {
+				return r.RevokeRefreshToken(context.Background(), 2, "hash")
+			},
+		},
+		{
+			name: "Logout Access Token",
+			mockBehavior: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta("DELETE FROM revoked_tokens WHERE expires_at < now()")).
+					WillReturnResult(sqlmock.NewResult(0, 3))
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO revoked_tokens (jti, expires_at) VALUES ($1, $2) ON CONFLICT (jti) DO NOTHING")).
+					WithArgs("jti", expiresAt).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			revoke: func(r *AuthPostgres) error {
+				return r.RevokeAccessToken(context.Background(), "jti", expiresAt)
+			},
+		},
+		{
+			name: "Logout All",
+			mockBehavior: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE refresh_tokens SET revoked_at=now() WHERE user_id=$1 AND revoked_at IS NULL")).
+					WithArgs(2).
+					WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE users SET tokens_revoked_at=now() WHERE id=$1")).
+					WithArgs(2).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			revoke: func(r *AuthPostgres) error {
+				return r.RevokeAllTokens(context.Background(), 2)
+			},
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			db, mock := newMockDB(t)
+			testCase.mockBehavior(mock)
+
+			err := testCase.revoke(NewAuthPostgres(db))
+
+			assert.NoError(t, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestAuthPostgres_IsTokenRevoked(t *testing.T) {
+	issuedAt := time.Unix(1740819600, 0)
+
+	for _, revoked := range []bool{true, false} {
+		db, mock := newMockDB(t)
+
+		// tokens issued in the second of logout-all are revoked too
+		mock.ExpectQuery(regexp.QuoteMeta("date_trunc('second', tokens_revoked_at) >= $3")).
+			WithArgs("jti", 2, issuedAt).
+			WillReturnRows(sqlmock.NewRows([]string{"revoked"}).AddRow(revoked))
+
+		got, err := NewAuthPostgres(db).IsTokenRevoked(context.Background(), 2, "jti", issuedAt)
+
+		assert.NoError(t, err)
+		assert.Equal(t, revoked, got)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	}
+}
@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lypolix/todo-app"
+)
+
+type CalDAVPostgres struct {
+	db *sqlx.DB
+}
+
+func NewCalDAVPostgres(db *sqlx.DB) *CalDAVPostgres {
+	return &CalDAVPostgres{db: db}
+}
+
+// objectName is the resource name of the ti item, the one its client chose
+// or <id>.ics.
+const objectName = `COALESCE(co.name, ti.id::text || '.ics')`
+
+// calendarsQuery selects the lists of the user with a hash of the names and
+// versions of their live items as the ctag.
+var calendarsQuery = fmt.Sprintf(`SELECT tl.id, tl.title, tl.description, ul.role,
+		md5(COALESCE(string_agg(%s || ':' || ti.id || '-' || ti.version, ',' ORDER BY ti.id), '')) AS ctag
+	FROM %s tl INNER JOIN %s ul on ul.list_id = tl.id
+	LEFT JOIN %s li on li.list_id = tl.id
+	LEFT JOIN %s ti on ti.id = li.item_id AND ti.deleted_at IS NULL
+	LEFT JOIN %s co on co.item_id = ti.id
+	WHERE ul.user_id = $1 AND tl.deleted_at IS NULL`,
+	objectName, todoListsTable, usersListsTable, listsItemsTable, todoItemsTable, caldavObjectsTable)
+
+func (r *CalDAVPostgres) GetCalendars(ctx context.Context, userId int) ([]todo.Calendar, error) {
+	calendars := make([]todo.Calendar, 0)
+	query := calendarsQuery + " GROUP BY tl.id, tl.title, tl.description, ul.role ORDER BY tl.id"
+	err := r.db.SelectContext(ctx, &calendars, query, userId)
+
+	return calendars, err
+}
+
+func (r *CalDAVPostgres) GetCalendar(ctx context.Context, userId, listId int) (todo.Calendar, error) {
+	var calendar todo.Calendar
+	query := calendarsQuery + " AND tl.id = $2 GROUP BY tl.id, tl.title, tl.description, ul.role"
+	err := r.db.GetContext(ctx, &calendar, query, userId, listId)
+
+	return calendar, notFound(err, todo.ErrListNotFound)
+}
+
+func (r *CalDAVPostgres) GetObjects(ctx context.Context, userId, listId int) ([]todo.CalendarObject, error) {
+	if err := requireListRole(ctx, r.db, userId, listId, todo.RoleOwner, todo.RoleEditor, todo.RoleViewer); err != nil {
+		return nil, err
+	}
+
+	objects := make([]todo.CalendarObject, 0)
+	query := fmt.Sprintf(`SELECT %s, li.list_id, %s AS name, COALESCE(co.uid, '') AS uid FROM %s ti
+							INNER JOIN %s li on li.item_id = ti.id LEFT JOIN %s co on co.item_id = ti.id
+							WHERE li.list_id = $1 AND %s ORDER BY ti.id`,
+		itemColumns, objectName, todoItemsTable, listsItemsTable, caldavObjectsTable, liveItem)
+	err := r.db.SelectContext(ctx, &objects, query, listId)
+
+	return objects, err
+}
+
+func (r *CalDAVPostgres) GetObject(ctx context.Context, userId, listId int, name string) (todo.CalendarObject, error) {
+	if err := requireListRole(ctx, r.db, userId, listId, todo.RoleOwner, todo.RoleEditor, todo.RoleViewer); err != nil {
+		return todo.CalendarObject{}, err
+	}
+
+	var object todo.CalendarObject
+	query := fmt.Sprintf(`SELECT %s, li.list_id, %s AS name, COALESCE(co.uid, '') AS uid FROM %s ti
+							INNER JOIN %s li on li.item_id = ti.id LEFT JOIN %s co on co.item_id = ti.id
+							WHERE li.list_id = $1 AND %s = $2 AND %s`,
+		itemColumns, objectName, todoItemsTable, listsItemsTable, caldavObjectsTable, objectName, liveItem)
+	err := r.db.GetContext(ctx, &object, query, listId, name)
+
+	return object, notFound(err, todo.ErrObjectNotFound)
+}
+
+// CreateObject appends the item of a new resource to the list and keeps its
+// name and UID. Names are unique within the list, names of items in the
+// trash can be taken by new resources.
+func (r *CalDAVPostgres) CreateObject(ctx context.Context, userId, listId int, object todo.CalendarObject) (int, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := requireListRole(ctx, tx, userId, listId, todo.RoleOwner, todo.RoleEditor); err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	releaseQuery := fmt.Sprintf(`DELETE FROM %s co USING %s ti
+							WHERE co.item_id = ti.id AND co.list_id = $1 AND co.name = $2 AND ti.deleted_at IS NOT NULL`,
+		caldavObjectsTable, todoItemsTable)
+	if _, err := tx.ExecContext(ctx, releaseQuery, listId, object.Name); err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	itemId, err := createItem(ctx, tx, userId, listId, object.TodoItem, object.Done)
+	if err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	query := fmt.Sprintf("INSERT INTO %s (item_id, list_id, name, uid) VALUES ($1, $2, $3, $4)", caldavObjectsTable)
+	if _, err := tx.ExecContext(ctx, query, itemId, listId, object.Name, object.UID); err != nil {
+		tx.Rollback()
+		return 0, notUnique(err, todo.ErrObjectExists)
+	}
+
+	return itemId, tx.Commit()
+}
+
+// moveObject takes the resource name of an item moved to another list along.
+// If the list already has a resource of that name, the item loses its name
+// and is served as <id>.ics there.
+func moveObject(ctx context.Context, tx *sqlx.Tx, itemId, listId int) error {
+	dropQuery := fmt.Sprintf(`DELETE FROM %s co WHERE co.item_id = $1
+							AND EXISTS (SELECT 1 FROM %s o WHERE o.list_id = $2 AND o.name = co.name)`,
+		caldavObjectsTable, caldavObjectsTable)
+	if _, err := tx.ExecContext(ctx, dropQuery, itemId, listId); err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf("UPDATE %s SET list_id = $1 WHERE item_id = $2", caldavObjectsTable)
+	_, err := tx.ExecContext(ctx, query, listId, itemId)
+
+	return err
+}
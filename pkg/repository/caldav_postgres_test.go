@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/lypolix/todo-app"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCalDAVPostgres_CreateObject(t *testing.T) {
+	testTable := []struct {
+		name        string
+		insertErr   error
+		expectedErr error
+	}{
+		{
+			name: "New Name",
+		},
+		{
+			name:        "Name Taken In The List",
+			insertErr:   &pq.Error{Code: uniqueViolation},
+			expectedErr: todo.ErrObjectExists,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			db, mock := newMockDB(t)
+
+			mock.ExpectBegin()
+			expectListRole(mock, 1, 3, todo.RoleEditor)
+			// only names in this list are released and checked
+			mock.ExpectExec(regexp.QuoteMeta("DELETE FROM caldav_objects co USING todo_items ti")).
+				WithArgs(3, "a.ics").
+				WillReturnResult(sqlmock.NewResult(0, 0))
+			mock.ExpectQuery(regexp.QuoteMeta("INSERT INTO todo_items")).
+				WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+			mock.ExpectExec(regexp.QuoteMeta("SELECT id FROM todo_lists WHERE id = $1 FOR UPDATE")).
+				WithArgs(3).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			mock.ExpectQuery(regexp.QuoteMeta("SELECT position FROM lists_items")).
+				WillReturnRows(sqlmock.NewRows([]string{"position"}))
+			mock.ExpectExec(regexp.QuoteMeta("INSERT INTO lists_items")).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			mock.ExpectExec(regexp.QuoteMeta("INSERT INTO activity")).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			insert := mock.ExpectExec(regexp.QuoteMeta("INSERT INTO caldav_objects (item_id, list_id, name, uid)")).
+				WithArgs(7, 3, "a.ics", "uid-a")
+			if testCase.insertErr != nil {
+				insert.WillReturnError(testCase.insertErr)
+				mock.ExpectRollback()
+			} else {
+				insert.WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			}
+
+			object := todo.CalendarObject{TodoItem: todo.TodoItem{Title: "Milk"}, Name: "a.ics", UID: "uid-a"}
+			_, err := NewCalDAVPostgres(db).CreateObject(context.Background(), 1, 3, object)
+
+			assert.ErrorIs(t, err, testCase.expectedErr)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lypolix/todo-app"
+)
+
+type CalendarPostgres struct {
+	db *sqlx.DB
+}
+
+func NewCalendarPostgres(db *sqlx.DB) *CalendarPostgres {
+	return &CalendarPostgres{db: db}
+}
+
+// SetToken replaces the user's feed token, the old feed URL stops working.
+func (r *CalendarPostgres) SetToken(ctx context.Context, userId int, tokenHash string) error {
+	query := fmt.Sprintf(`INSERT INTO %s (user_id, token_hash) VALUES ($1, $2)
+							ON CONFLICT (user_id) DO UPDATE SET token_hash = excluded.token_hash, created_at = now()`, calendarTokensTable)
+	_, err := r.db.ExecContext(ctx, query, userId, tokenHash)
+
+	return err
+}
+
+func (r *CalendarPostgres) DeleteToken(ctx context.Context, userId int) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE user_id = $1", calendarTokensTable)
+	res, err := r.db.ExecContext(ctx, query, userId)
+	if err != nil {
+		return err
+	}
+
+	return checkAffected(res, func() error { return todo.ErrCalendarNotFound })
+}
+
+func (r *CalendarPostgres) GetUserId(ctx context.Context, tokenHash string) (int, error) {
+	var userId int
+	query := fmt.Sprintf("SELECT user_id FROM %s WHERE token_hash = $1", calendarTokensTable)
+	err := r.db.GetContext(ctx, &userId, query, tokenHash)
+
+	return userId, notFound(err, todo.ErrCalendarNotFound)
+}
+
+// GetItems returns the items with a deadline from all lists the user is a
+// member of, done or not.
+func (r *CalendarPostgres) GetItems(ctx context.Context, userId int) ([]todo.TodoItem, error) {
+	items := make([]todo.TodoItem, 0)
+	query := fmt.Sprintf(`SELECT %s, li.list_id FROM %s ti INNER JOIN %s li on li.item_id = ti.id
+							INNER JOIN %s ul on ul.list_id = li.list_id
+							WHERE ul.user_id = $1 AND %s AND ti.deadline IS NOT NULL ORDER BY ti.deadline, ti.id`,
+		itemColumns, todoItemsTable, listsItemsTable, usersListsTable, liveItem)
+	err := r.db.SelectContext(ctx, &items, query, userId)
+
+	return items, err
+}
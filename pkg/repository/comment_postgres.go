@@ -0,0 +1,131 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lypolix/todo-app"
+)
+
+// commentColumns are the columns every comment query returns, with the
+// username of the author.
+const commentColumns = `c.id, c.item_id, c.parent_id, c.author_id, u.username AS author, c.body, c.created_at, c.edited_at`
+
+type CommentPostgres struct {
+	db *sqlx.DB
+}
+
+func NewCommentPostgres(db *sqlx.DB) *CommentPostgres {
+	return &CommentPostgres{db: db}
+}
+
+// Create adds a comment to an item the user can see, every member of the
+// list can take part in the discussion.
+func (r *CommentPostgres) Create(ctx context.Context, userId, itemId int, comment todo.Comment) (int, error) {
+	if err := requireItemRole(ctx, r.db, userId, itemId, todo.RoleOwner, todo.RoleEditor, todo.RoleViewer); err != nil {
+		return 0, err
+	}
+
+	if comment.ParentId != nil {
+		var parentItemId int
+		parentQuery := fmt.Sprintf("SELECT item_id FROM %s WHERE id = $1", commentsTable)
+		if err := r.db.GetContext(ctx, &parentItemId, parentQuery, *comment.ParentId); err != nil {
+			return 0, notFound(err, todo.ErrInvalidParentComment)
+		}
+		if parentItemId != itemId {
+			return 0, todo.ErrInvalidParentComment
+		}
+	}
+
+	var id int
+	query := fmt.Sprintf("INSERT INTO %s (item_id, parent_id, author_id, body) VALUES ($1, $2, $3, $4) RETURNING id", commentsTable)
+	err := r.db.QueryRowContext(ctx, query, itemId, comment.ParentId, userId, comment.Body).Scan(&id)
+
+	return id, err
+}
+
+// GetAll returns the comments of the item in the order they were written.
+func (r *CommentPostgres) GetAll(ctx context.Context, userId, itemId int) ([]todo.Comment, error) {
+	if err := requireItemRole(ctx, r.db, userId, itemId, todo.RoleOwner, todo.RoleEditor, todo.RoleViewer); err != nil {
+		return nil, err
+	}
+
+	comments := make([]todo.Comment, 0)
+	query := fmt.Sprintf(`SELECT %s FROM %s c INNER JOIN %s u on u.id = c.author_id
+							WHERE c.item_id = $1 ORDER BY c.created_at, c.id`, commentColumns, commentsTable, usersTable)
+	if err := r.db.SelectContext(ctx, &comments, query, itemId); err != nil {
+		return nil, err
+	}
+
+	return comments, nil
+}
+
+func (r *CommentPostgres) GetById(ctx context.Context, userId, itemId, commentId int) (todo.Comment, error) {
+	var comment todo.Comment
+	if err := requireItemRole(ctx, r.db, userId, itemId, todo.RoleOwner, todo.RoleEditor, todo.RoleViewer); err != nil {
+		return comment, err
+	}
+
+	query := fmt.Sprintf(`SELECT %s FROM %s c INNER JOIN %s u on u.id = c.author_id
+							WHERE c.id = $1 AND c.item_id = $2`, commentColumns, commentsTable, usersTable)
+	err := r.db.GetContext(ctx, &comment, query, commentId, itemId)
+
+	return comment, notFound(err, todo.ErrCommentNotFound)
+}
+
+func (r *CommentPostgres) Update(ctx context.Context, userId, itemId, commentId int, input todo.UpdateCommentInput) error {
+	if err := requireItemRole(ctx, r.db, userId, itemId, todo.RoleOwner, todo.RoleEditor, todo.RoleViewer); err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf("UPDATE %s SET body = $1, edited_at = now() WHERE id = $2 AND item_id = $3 AND author_id = $4", commentsTable)
+	res, err := r.db.ExecContext(ctx, query, input.Body, commentId, itemId, userId)
+	if err != nil {
+		return err
+	}
+
+	return checkAffected(res, func() error {
+		return r.requireAuthor(ctx, itemId, commentId)
+	})
+}
+
+// Delete removes the comment. Replies of other authors stay, they lose their
+// parent and become top-level comments.
+func (r *CommentPostgres) Delete(ctx context.Context, userId, itemId, commentId int) error {
+	if err := requireItemRole(ctx, r.db, userId, itemId, todo.RoleOwner, todo.RoleEditor, todo.RoleViewer); err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf("DELETE FROM %s WHERE id = $1 AND item_id = $2 AND author_id = $3", commentsTable)
+	res, err := r.db.ExecContext(ctx, query, commentId, itemId, userId)
+	if err != nil {
+		return err
+	}
+
+	return checkAffected(res, func() error {
+		return r.requireAuthor(ctx, itemId, commentId)
+	})
+}
+
+// GetItemUsers returns the users who have access to the item's list.
+func (r *CommentPostgres) GetItemUsers(ctx context.Context, itemId int) ([]int, error) {
+	var userIds []int
+	query := fmt.Sprintf("SELECT ul.user_id FROM %s ul INNER JOIN %s li on li.list_id = ul.list_id WHERE li.item_id = $1",
+		usersListsTable, listsItemsTable)
+	err := r.db.SelectContext(ctx, &userIds, query, itemId)
+
+	return userIds, err
+}
+
+// requireAuthor explains why a change of a comment matched nothing: the
+// comment either does not exist or was written by somebody else.
+func (r *CommentPostgres) requireAuthor(ctx context.Context, itemId, commentId int) error {
+	var id int
+	query := fmt.Sprintf("SELECT id FROM %s WHERE id = $1 AND item_id = $2", commentsTable)
+	if err := r.db.GetContext(ctx, &id, query, commentId, itemId); err != nil {
+		return notFound(err, todo.ErrCommentNotFound)
+	}
+
+	return todo.ErrCommentForbidden
+}
@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"encoding/base64"
+	"encoding/json"
+
+	"github.com/lypolix/todo-app"
+)
+
+// cursor is the position after the last row of a page for keyset pagination.
+// Sort and order are part of it so a cursor cannot be reused with another ordering.
+type cursor struct {
+	Sort  string `json:"s,omitempty"`
+	Order string `json:"o,omitempty"`
+	Value string `json:"v,omitempty"`
+	Id    int    `json:"id"`
+}
+
+func encodeCursor(c cursor) string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeCursor(s string) (cursor, error) {
+	var c cursor
+
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return c, todo.ErrInvalidCursor
+	}
+
+	if err := json.Unmarshal(data, &c); err != nil {
+		return c, todo.ErrInvalidCursor
+	}
+
+	return c, nil
+}
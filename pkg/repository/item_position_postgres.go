@@ -0,0 +1,177 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lypolix/todo-app"
+)
+
+// Move puts the item into the target list between its anchors. The user has
+// to be able to edit both the item's list and the target list. The series of
+// a recurring item and the CalDAV name of the item move with it.
+func (r *TodoItemPostgres) Move(ctx context.Context, userId, itemId int, input todo.MoveItemInput) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if err := requireItemRole(ctx, tx, userId, itemId, todo.RoleOwner, todo.RoleEditor); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	var before struct {
+		ListId   int    `db:"list_id"`
+		Position string `db:"position"`
+	}
+	listQuery := fmt.Sprintf("SELECT list_id, position FROM %s WHERE item_id = $1", listsItemsTable)
+	if err := tx.GetContext(ctx, &before, listQuery, itemId); err != nil {
+		tx.Rollback()
+		return notFound(err, todo.ErrItemNotFound)
+	}
+
+	listId := before.ListId
+	if input.ListId != nil && *input.ListId != listId {
+		listId = *input.ListId
+		if err := requireListRole(ctx, tx, userId, listId, todo.RoleOwner, todo.RoleEditor); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	if err := lockList(ctx, tx, listId); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	position, err := movePosition(ctx, tx, listId, itemId, input)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	query := fmt.Sprintf("UPDATE %s SET list_id = $1, position = $2 WHERE item_id = $3", listsItemsTable)
+	if _, err := tx.ExecContext(ctx, query, listId, position, itemId); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := bumpItemVersion(ctx, tx, itemId); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if listId != before.ListId {
+		if err := moveSeries(ctx, tx, itemId, before.ListId, listId); err != nil {
+			tx.Rollback()
+			return err
+		}
+
+		if err := moveObject(ctx, tx, itemId, listId); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	changes := todo.Changes{}
+	addChange(changes, "list_id", before.ListId, listId)
+	addChange(changes, "position", before.Position, position)
+	if err := logActivity(ctx, tx, todo.Activity{ActorId: &userId, ListId: listId, ItemId: &itemId, Action: todo.ActionMove, Changes: changes}); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// movePosition finds the neighbours the anchors stand for and returns a
+// position between them.
+func movePosition(ctx context.Context, tx *sqlx.Tx, listId, itemId int, input todo.MoveItemInput) (string, error) {
+	var after, before string
+	var err error
+
+	if input.After != nil {
+		if after, err = anchorPosition(ctx, tx, listId, itemId, *input.After); err != nil {
+			return "", err
+		}
+	}
+
+	if input.Before != nil {
+		if before, err = anchorPosition(ctx, tx, listId, itemId, *input.Before); err != nil {
+			return "", err
+		}
+	}
+
+	switch {
+	case input.After != nil && input.Before != nil:
+		if after >= before {
+			return "", todo.ErrInvalidAnchor
+		}
+	case input.After != nil:
+		before, err = neighbourPosition(ctx, tx, listId, itemId, "position > $3 ORDER BY position", after)
+	case input.Before != nil:
+		after, err = neighbourPosition(ctx, tx, listId, itemId, "position < $3 ORDER BY position DESC", before)
+	default:
+		after, err = neighbourPosition(ctx, tx, listId, itemId, "true ORDER BY position DESC")
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return rankBetween(after, before), nil
+}
+
+func anchorPosition(ctx context.Context, tx *sqlx.Tx, listId, itemId, anchorId int) (string, error) {
+	if anchorId == itemId {
+		return "", todo.ErrInvalidAnchor
+	}
+
+	var position string
+	query := fmt.Sprintf(`SELECT li.position FROM %s li INNER JOIN %s ti on ti.id = li.item_id
+							WHERE li.list_id = $1 AND li.item_id = $2 AND ti.deleted_at IS NULL`, listsItemsTable, todoItemsTable)
+	if err := tx.GetContext(ctx, &position, query, listId, anchorId); err != nil {
+		return "", notFound(err, todo.ErrInvalidAnchor)
+	}
+
+	return position, nil
+}
+
+// neighbourPosition returns the position of the first other item of the list
+// matching where, or an empty one if there is none.
+func neighbourPosition(ctx context.Context, tx *sqlx.Tx, listId, itemId int, where string, args ...interface{}) (string, error) {
+	var position string
+	query := fmt.Sprintf("SELECT position FROM %s WHERE list_id = $1 AND item_id <> $2 AND %s LIMIT 1", listsItemsTable, where)
+	err := tx.GetContext(ctx, &position, query, append([]interface{}{listId, itemId}, args...)...)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+
+	return position, err
+}
+
+// appendPosition locks the list and returns the position after its last item.
+func appendPosition(ctx context.Context, tx *sqlx.Tx, listId int) (string, error) {
+	if err := lockList(ctx, tx, listId); err != nil {
+		return "", err
+	}
+
+	last, err := neighbourPosition(ctx, tx, listId, 0, "true ORDER BY position DESC")
+	if err != nil {
+		return "", err
+	}
+
+	return rankBetween(last, ""), nil
+}
+
+// lockList serializes position changes within a list, so concurrent moves
+// and inserts don't pick the same position.
+func lockList(ctx context.Context, tx *sqlx.Tx, listId int) error {
+	query := fmt.Sprintf("SELECT id FROM %s WHERE id = $1 FOR UPDATE", todoListsTable)
+	_, err := tx.ExecContext(ctx, query, listId)
+
+	return err
+}
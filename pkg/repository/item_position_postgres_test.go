@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lypolix/todo-app"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTodoItemPostgres_Move(t *testing.T) {
+	otherList := 4
+
+	testTable := []struct {
+		name         string
+		input        todo.MoveItemInput
+		mockBehavior func(mock sqlmock.Sqlmock)
+		expectedErr  error
+	}{
+		{
+			name: "To The End",
+			mockBehavior: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				expectItemRole(mock, 1, 5, 3, todo.RoleEditor)
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT list_id, position FROM lists_items")).
+					WithArgs(5).
+					WillReturnRows(sqlmock.NewRows([]string{"list_id", "position"}).AddRow(3, "h"))
+				mock.ExpectExec(regexp.QuoteMeta("SELECT id FROM todo_lists WHERE id = $1 FOR UPDATE")).
+					WithArgs(3).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT position FROM lists_items")).
+					WithArgs(3, 5).
+					WillReturnRows(sqlmock.NewRows([]string{"position"}).AddRow("p"))
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE lists_items SET list_id = $1, position = $2 WHERE item_id = $3")).
+					WithArgs(3, sqlmock.AnyArg(), 5).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				expectItemVersion(mock, 5)
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO activity")).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name:  "To Another List",
+			input: todo.MoveItemInput{ListId: &otherList},
+			mockBehavior: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				expectItemRole(mock, 1, 5, 3, todo.RoleEditor)
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT list_id, position FROM lists_items")).
+					WithArgs(5).
+					WillReturnRows(sqlmock.NewRows([]string{"list_id", "position"}).AddRow(3, "h"))
+				expectListRole(mock, 1, 4, todo.RoleOwner)
+				mock.ExpectExec(regexp.QuoteMeta("SELECT id FROM todo_lists WHERE id = $1 FOR UPDATE")).
+					WithArgs(4).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT position FROM lists_items")).
+					WithArgs(4, 5).
+					WillReturnRows(sqlmock.NewRows([]string{"position"}))
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE lists_items SET list_id = $1, position = $2 WHERE item_id = $3")).
+					WithArgs(4, sqlmock.AnyArg(), 5).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				expectItemVersion(mock, 5)
+				mock.ExpectQuery(regexp.QuoteMeta("UPDATE item_series s SET list_id = $1")).
+					WithArgs(4, 5).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}))
+				mock.ExpectExec(regexp.QuoteMeta("DELETE FROM caldav_objects co WHERE co.item_id = $1")).
+					WithArgs(5, 4).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE caldav_objects SET list_id = $1 WHERE item_id = $2")).
+					WithArgs(4, 5).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO activity")).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "Viewer",
+			mockBehavior: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				expectItemRole(mock, 1, 5, 3, todo.RoleViewer)
+				mock.ExpectRollback()
+			},
+			expectedErr: todo.ErrListForbidden,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			db, mock := newMockDB(t)
+			testCase.mockBehavior(mock)
+
+			err := NewTodoItemPostgres(db).Move(context.Background(), 1, 5, testCase.input)
+
+			assert.ErrorIs(t, err, testCase.expectedErr)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
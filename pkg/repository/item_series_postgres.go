@@ -0,0 +1,250 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lypolix/todo-app"
+)
+
+// GetSeries returns the series of a recurring item from one of the user's
+// lists. A series is only visible to the users of the list it belongs to.
+func (r *TodoItemPostgres) GetSeries(ctx context.Context, userId, seriesId int) (todo.ItemSeries, error) {
+	var series todo.ItemSeries
+	query := fmt.Sprintf(`SELECT s.id, s.list_id, s.rrule, s.title, s.description, s.dtstart FROM %s s
+							INNER JOIN %s ul on ul.list_id = s.list_id
+							WHERE s.id = $1 AND ul.user_id = $2 AND s.list_id IN (SELECT id FROM %s WHERE deleted_at IS NULL)`,
+		itemSeriesTable, usersListsTable, todoListsTable)
+	err := r.db.GetContext(ctx, &series, query, seriesId, userId)
+
+	return series, notFound(err, todo.ErrItemNotFound)
+}
+
+// CreateOccurrence adds the occurrence of the item's series scheduled at
+// recurrenceId to the item's list, using the series template and the item's
+// checklist. It returns 0 when the series already has that occurrence, so
+// generating it is idempotent.
+func (r *TodoItemPostgres) CreateOccurrence(ctx context.Context, userId, itemId int, recurrenceId time.Time) (int, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := requireItemRole(ctx, tx, userId, itemId, todo.RoleOwner, todo.RoleEditor); err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	var occurrence todo.TodoItem
+	createQuery := fmt.Sprintf(`INSERT INTO %s (title, description, deadline, series_id, recurrence_id, auto_complete, priority)
+							SELECT s.title, s.description, $1, s.id, $1, ti.auto_complete, ti.priority FROM %s s INNER JOIN %s ti on ti.series_id = s.id
+								INNER JOIN %s li on li.item_id = ti.id AND li.list_id = s.list_id
+							WHERE ti.id = $2 ON CONFLICT (series_id, recurrence_id) DO NOTHING RETURNING id, title, deadline`,
+		todoItemsTable, itemSeriesTable, todoItemsTable, listsItemsTable)
+	err = tx.GetContext(ctx, &occurrence, createQuery, recurrenceId, itemId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, tx.Commit()
+	}
+	if err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	var listId int
+	listQuery := fmt.Sprintf("SELECT list_id FROM %s WHERE item_id = $1", listsItemsTable)
+	if err := tx.GetContext(ctx, &listId, listQuery, itemId); err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	position, err := appendPosition(ctx, tx, listId)
+	if err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	listItemQuery := fmt.Sprintf("INSERT INTO %s (list_id, item_id, position) VALUES ($1, $2, $3)", listsItemsTable)
+	if _, err := tx.ExecContext(ctx, listItemQuery, listId, occurrence.Id, position); err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	// the checklist repeats with the item, unchecked
+	subtasksQuery := fmt.Sprintf("INSERT INTO %s (item_id, title, position) SELECT $1, title, position FROM %s WHERE item_id = $2",
+		subtasksTable, subtasksTable)
+	if _, err := tx.ExecContext(ctx, subtasksQuery, occurrence.Id, itemId); err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	activity := todo.Activity{ActorId: &userId, ListId: listId, ItemId: &occurrence.Id, Action: todo.ActionCreate, Changes: itemChanges(todo.TodoItem{}, occurrence)}
+	if err := logActivity(ctx, tx, activity); err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	return occurrence.Id, tx.Commit()
+}
+
+// moveSeries takes the series of an item moved to another list along. The
+// items left in the old list leave the series, so a series stays in one list.
+func moveSeries(ctx context.Context, tx *sqlx.Tx, itemId, fromListId, toListId int) error {
+	var seriesId int
+	query := fmt.Sprintf(`UPDATE %s s SET list_id = $1 FROM %s ti
+							WHERE ti.id = $2 AND s.id = ti.series_id RETURNING s.id`, itemSeriesTable, todoItemsTable)
+	err := tx.GetContext(ctx, &seriesId, query, toListId, itemId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	detachQuery := fmt.Sprintf(`UPDATE %s SET series_id = NULL, recurrence_id = NULL, version = version + 1
+							WHERE series_id = $1 AND id IN (SELECT item_id FROM %s WHERE list_id = $2)`, todoItemsTable, listsItemsTable)
+	_, err = tx.ExecContext(ctx, detachQuery, seriesId, fromListId)
+
+	return err
+}
+
+func createSeries(ctx context.Context, tx *sqlx.Tx, series todo.ItemSeries) (int, error) {
+	var id int
+	query := fmt.Sprintf("INSERT INTO %s (list_id, rrule, title, description, dtstart) VALUES ($1, $2, $3, $4, $5) RETURNING id", itemSeriesTable)
+	err := tx.QueryRowContext(ctx, query, series.ListId, series.RRule, series.Title, series.Description, series.DtStart).Scan(&id)
+
+	return id, err
+}
+
+// updateSeries applies the recurrence part of an already stored item update.
+// An item that gets a rule starts a new series. A "future" edit of an
+// occurrence is carried over to the series template and to the later open
+// occurrences of the series' list, which are logged as changed by the user;
+// a new rule or deadline re-anchors the series at this occurrence.
+func updateSeries(ctx context.Context, tx *sqlx.Tx, userId, itemId int, input todo.UpdateItemInput) error {
+	var item todo.TodoItem
+	itemQuery := fmt.Sprintf(`SELECT %s, li.list_id FROM %s ti INNER JOIN %s li on li.item_id = ti.id
+							WHERE ti.id = $1 FOR UPDATE OF ti`, itemColumns, todoItemsTable, listsItemsTable)
+	if err := tx.GetContext(ctx, &item, itemQuery, itemId); err != nil {
+		return notFound(err, todo.ErrItemNotFound)
+	}
+
+	linkQuery := fmt.Sprintf("UPDATE %s SET series_id = $1, recurrence_id = deadline WHERE id = $2", todoItemsTable)
+
+	if item.SeriesId == nil {
+		if input.RRule == nil || *input.RRule == "" || item.Deadline == nil {
+			return nil
+		}
+
+		seriesId, err := createSeries(ctx, tx, todo.ItemSeries{
+			RRule:       *input.RRule,
+			Title:       item.Title,
+			Description: item.Description,
+			DtStart:     *item.Deadline,
+			ListId:      item.ListId,
+		})
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, linkQuery, seriesId, itemId)
+		return err
+	}
+
+	if input.Scope != todo.ScopeFuture {
+		return nil
+	}
+
+	templateValues := make([]string, 0)
+	args := make([]interface{}, 0)
+	argId := 1
+
+	if input.Title != nil {
+		templateValues = append(templateValues, fmt.Sprintf("title=$%d", argId))
+		args = append(args, *input.Title)
+		argId++
+	}
+
+	if input.Description != nil {
+		templateValues = append(templateValues, fmt.Sprintf("description=$%d", argId))
+		args = append(args, *input.Description)
+		argId++
+	}
+
+	setValues := append([]string{}, templateValues...)
+	if input.RRule != nil {
+		setValues = append(setValues, fmt.Sprintf("rrule=$%d", argId))
+		args = append(args, *input.RRule)
+		argId++
+	}
+
+	reanchor := (input.RRule != nil || input.Deadline != nil) && item.Deadline != nil
+	if reanchor {
+		setValues = append(setValues, fmt.Sprintf("dtstart=$%d", argId))
+		args = append(args, *item.Deadline)
+		argId++
+
+		if _, err := tx.ExecContext(ctx, linkQuery, *item.SeriesId, itemId); err != nil {
+			return err
+		}
+		item.RecurrenceId = item.Deadline
+	}
+
+	if len(setValues) == 0 {
+		return nil
+	}
+
+	// only an item of the series' own list can change it
+	seriesQuery := fmt.Sprintf("UPDATE %s SET %s WHERE id = $%d AND list_id = $%d", itemSeriesTable, strings.Join(setValues, ", "), argId, argId+1)
+	res, err := tx.ExecContext(ctx, seriesQuery, append(args, *item.SeriesId, item.ListId)...)
+	if err != nil {
+		return err
+	}
+	if err := checkAffected(res, func() error { return todo.ErrItemNotFound }); err != nil {
+		return err
+	}
+
+	if len(templateValues) == 0 || item.RecurrenceId == nil {
+		return nil
+	}
+
+	// the old values come from the locked rows before the update
+	templateArgs := append([]interface{}{}, args[:len(templateValues)]...)
+	occurrencesQuery := fmt.Sprintf(`UPDATE %s ti SET %s, version = ti.version + 1
+							FROM (SELECT id, title, description FROM %s WHERE series_id = $%d AND done = false AND recurrence_id > $%d
+								AND id IN (SELECT item_id FROM %s WHERE list_id = $%d) FOR UPDATE) old
+							WHERE ti.id = old.id RETURNING ti.id, old.title, old.description`,
+		todoItemsTable, strings.Join(templateValues, ", "), todoItemsTable, len(templateArgs)+1, len(templateArgs)+2, listsItemsTable, len(templateArgs)+3)
+
+	var occurrences []todo.TodoItem
+	if err := tx.SelectContext(ctx, &occurrences, occurrencesQuery, append(templateArgs, *item.SeriesId, *item.RecurrenceId, item.ListId)...); err != nil {
+		return err
+	}
+
+	for _, before := range occurrences {
+		after := before
+		if input.Title != nil {
+			after.Title = *input.Title
+		}
+		if input.Description != nil {
+			after.Description = *input.Description
+		}
+
+		changes := itemChanges(before, after)
+		if len(changes) == 0 {
+			continue
+		}
+
+		occurrenceId := before.Id
+		activity := todo.Activity{ActorId: &userId, ListId: item.ListId, ItemId: &occurrenceId, Action: todo.ActionUpdate, Changes: changes}
+		if err := logActivity(ctx, tx, activity); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lypolix/todo-app"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpdateSeries_future(t *testing.T) {
+	db, mock := newMockDB(t)
+
+	recurrenceId := time.Date(2025, time.March, 3, 9, 0, 0, 0, time.UTC)
+	title := "Weekly report"
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT ti.id, ti.title")).
+		WithArgs(5).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "description", "done", "deadline", "created_at", "series_id",
+			"recurrence_id", "auto_complete", "priority", "version", "rrule", "progress", "list_id"}).
+			AddRow(5, "Report", "", false, recurrenceId, recurrenceId, 2, recurrenceId, false, 0, 1, "FREQ=WEEKLY", nil, 3))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE item_series SET title=$1 WHERE id = $2 AND list_id = $3")).
+		WithArgs(title, 2, 3).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(regexp.QuoteMeta("UPDATE todo_items ti SET title=$1")).
+		WithArgs(title, 2, recurrenceId, 3).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "description"}).
+			AddRow(6, "Report", "").
+			AddRow(7, title, ""))
+	// only the occurrence that changed is logged
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO activity")).
+		WithArgs(1, 3, 6, todo.ActionUpdate, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	tx, err := db.Beginx()
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	err = updateSeries(context.Background(), tx, 1, 5, todo.UpdateItemInput{Title: &title, Scope: todo.ScopeFuture})
+	assert.NoError(t, err)
+	assert.NoError(t, tx.Commit())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,229 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/lypolix/todo-app"
+)
+
+type LabelPostgres struct {
+	db *sqlx.DB
+}
+
+func NewLabelPostgres(db *sqlx.DB) *LabelPostgres {
+	return &LabelPostgres{db: db}
+}
+
+func (r *LabelPostgres) Create(ctx context.Context, userId int, label todo.Label) (int, error) {
+	var id int
+	query := fmt.Sprintf("INSERT INTO %s (user_id, name, color) VALUES ($1, $2, $3) RETURNING id", labelsTable)
+	if err := r.db.QueryRowContext(ctx, query, userId, label.Name, label.Color).Scan(&id); err != nil {
+		return 0, notUnique(err, todo.ErrLabelExists)
+	}
+
+	return id, nil
+}
+
+func (r *LabelPostgres) GetAll(ctx context.Context, userId int) ([]todo.Label, error) {
+	labels := make([]todo.Label, 0)
+	query := fmt.Sprintf("SELECT id, name, color FROM %s WHERE user_id = $1 ORDER BY lower(name)", labelsTable)
+	err := r.db.SelectContext(ctx, &labels, query, userId)
+
+	return labels, err
+}
+
+// Update changes the label and with it every item carrying it.
+func (r *LabelPostgres) Update(ctx context.Context, userId, labelId int, input todo.UpdateLabelInput) error {
+	setValues := make([]string, 0)
+	args := make([]interface{}, 0)
+	argId := 1
+
+	if input.Name != nil {
+		setValues = append(setValues, fmt.Sprintf("name=$%d", argId))
+		args = append(args, *input.Name)
+		argId++
+	}
+
+	if input.Color != nil {
+		setValues = append(setValues, fmt.Sprintf("color=$%d", argId))
+		args = append(args, *input.Color)
+		argId++
+	}
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf("UPDATE %s SET %s WHERE id = $%d AND user_id = $%d", labelsTable, strings.Join(setValues, ", "), argId, argId+1)
+	args = append(args, labelId, userId)
+
+	res, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		tx.Rollback()
+		return notUnique(err, todo.ErrLabelExists)
+	}
+	if err := checkAffected(res, func() error { return todo.ErrLabelNotFound }); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := bumpLabeledVersions(ctx, tx, labelId); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Delete removes the label, also from the items carrying it.
+func (r *LabelPostgres) Delete(ctx context.Context, userId, labelId int) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	var id int
+	labelQuery := fmt.Sprintf("SELECT id FROM %s WHERE id = $1 AND user_id = $2 FOR UPDATE", labelsTable)
+	if err := tx.GetContext(ctx, &id, labelQuery, labelId, userId); err != nil {
+		tx.Rollback()
+		return notFound(err, todo.ErrLabelNotFound)
+	}
+
+	if err := bumpLabeledVersions(ctx, tx, labelId); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	query := fmt.Sprintf("DELETE FROM %s WHERE id = $1", labelsTable)
+	if _, err := tx.ExecContext(ctx, query, labelId); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Attach puts the label on an item the user can see; viewers may label items
+// too since labels are personal. Attaching a label twice is not an error and
+// leaves the item's version as it is.
+func (r *LabelPostgres) Attach(ctx context.Context, userId, itemId, labelId int) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if err := requireItemRole(ctx, tx, userId, itemId, todo.RoleOwner, todo.RoleEditor, todo.RoleViewer); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	var id int
+	labelQuery := fmt.Sprintf("SELECT id FROM %s WHERE id = $1 AND user_id = $2", labelsTable)
+	if err := tx.GetContext(ctx, &id, labelQuery, labelId, userId); err != nil {
+		tx.Rollback()
+		return notFound(err, todo.ErrLabelNotFound)
+	}
+
+	query := fmt.Sprintf("INSERT INTO %s (item_id, label_id) VALUES ($1, $2) ON CONFLICT DO NOTHING", itemsLabelsTable)
+	res, err := tx.ExecContext(ctx, query, itemId, labelId)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if rows > 0 {
+		if err := bumpItemVersion(ctx, tx, itemId); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *LabelPostgres) Detach(ctx context.Context, userId, itemId, labelId int) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf(`DELETE FROM %s il USING %s l
+							WHERE il.label_id = l.id AND l.user_id = $1 AND il.item_id = $2 AND il.label_id = $3`,
+		itemsLabelsTable, labelsTable)
+	res, err := tx.ExecContext(ctx, query, userId, itemId, labelId)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := checkAffected(res, func() error {
+		if err := requireItemRole(ctx, tx, userId, itemId, todo.RoleOwner, todo.RoleEditor, todo.RoleViewer); err != nil {
+			return err
+		}
+		return todo.ErrLabelNotFound
+	}); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := bumpItemVersion(ctx, tx, itemId); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// bumpLabeledVersions gives the items carrying the label a new version.
+func bumpLabeledVersions(ctx context.Context, tx *sqlx.Tx, labelId int) error {
+	query := fmt.Sprintf("UPDATE %s SET version = version + 1 WHERE id IN (SELECT item_id FROM %s WHERE label_id = $1)",
+		todoItemsTable, itemsLabelsTable)
+	_, err := tx.ExecContext(ctx, query, labelId)
+
+	return err
+}
+
+// loadLabels fills in the labels the user put on the items with one query.
+func loadLabels(ctx context.Context, q sqlx.QueryerContext, userId int, items []todo.TodoItem) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	ids := make([]int, len(items))
+	for i, item := range items {
+		ids[i] = item.Id
+	}
+
+	var rows []struct {
+		ItemId int `db:"item_id"`
+		todo.Label
+	}
+	query := fmt.Sprintf(`SELECT il.item_id, l.id, l.name, l.color FROM %s il INNER JOIN %s l on l.id = il.label_id
+							WHERE l.user_id = $1 AND il.item_id = ANY($2) ORDER BY lower(l.name)`,
+		itemsLabelsTable, labelsTable)
+	if err := sqlx.SelectContext(ctx, q, &rows, query, userId, pq.Array(ids)); err != nil {
+		return err
+	}
+
+	labels := make(map[int][]todo.Label)
+	for _, row := range rows {
+		labels[row.ItemId] = append(labels[row.ItemId], row.Label)
+	}
+
+	for i := range items {
+		items[i].Labels = labels[items[i].Id]
+	}
+
+	return nil
+}
@@ -0,0 +1,311 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/lypolix/todo-app"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLabelPostgres_Create(t *testing.T) {
+	testTable := []struct {
+		name         string
+		mockBehavior func(mock sqlmock.Sqlmock)
+		expectedId   int
+		expectedErr  error
+	}{
+		{
+			name: "OK",
+			mockBehavior: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta("INSERT INTO labels (user_id, name, color)")).
+					WithArgs(1, "Home", "#ff0000").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(9))
+			},
+			expectedId: 9,
+		},
+		{
+			name: "Name Taken",
+			mockBehavior: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta("INSERT INTO labels (user_id, name, color)")).
+					WithArgs(1, "Home", "#ff0000").
+					WillReturnError(&pq.Error{Code: uniqueViolation})
+			},
+			expectedErr: todo.ErrLabelExists,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			db, mock := newMockDB(t)
+			testCase.mockBehavior(mock)
+
+			id, err := NewLabelPostgres(db).Create(context.Background(), 1, todo.Label{Name: "Home", Color: "#ff0000"})
+
+			assert.ErrorIs(t, err, testCase.expectedErr)
+			assert.Equal(t, testCase.expectedId, id)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+// TestLabelPostgres_otherUser checks that the labels of other users can be
+// neither seen nor changed nor used.
+func TestLabelPostgres_otherUser(t *testing.T) {
+	name := "Work"
+
+	testTable := []struct {
+		name         string
+		mockBehavior func(mock sqlmock.Sqlmock)
+		use          func(r *LabelPostgres) error
+		expectedErr  error
+	}{
+		{
+			name: "Get All",
+			mockBehavior: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT id, name, color FROM labels WHERE user_id = $1")).
+					WithArgs(2).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name", "color"}))
+			},
+			use: func(r *LabelPostgres) error {
+				labels, err := r.GetAll(context.Background(), 2)
+				if len(labels) != 0 {
+					return errors.New("labels of another user returned")
+				}
+				return err
+			},
+		},
+		{
+			name: "Update",
+			mockBehavior: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE labels SET name=$1 WHERE id = $2 AND user_id = $3")).
+					WithArgs(name, 9, 2).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectRollback()
+			},
+			use: func(r *LabelPostgres) error {
+				return r.Update(context.Background(), 2, 9, todo.UpdateLabelInput{Name: &name})
+			},
+			expectedErr: todo.ErrLabelNotFound,
+		},
+		{
+			name: "Delete",
+			mockBehavior: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT id FROM labels WHERE id = $1 AND user_id = $2 FOR UPDATE")).
+					WithArgs(9, 2).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}))
+				mock.ExpectRollback()
+			},
+			use: func(r *LabelPostgres) error {
+				return r.Delete(context.Background(), 2, 9)
+			},
+			expectedErr: todo.ErrLabelNotFound,
+		},
+		{
+			name: "Attach",
+			mockBehavior: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				expectItemRole(mock, 2, 5, 3, todo.RoleEditor)
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT id FROM labels WHERE id = $1 AND user_id = $2")).
+					WithArgs(9, 2).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}))
+				mock.ExpectRollback()
+			},
+			use: func(r *LabelPostgres) error {
+				return r.Attach(context.Background(), 2, 5, 9)
+			},
+			expectedErr: todo.ErrLabelNotFound,
+		},
+		{
+			name: "Detach",
+			mockBehavior: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta("DELETE FROM items_labels il USING labels l")).
+					WithArgs(2, 5, 9).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				expectItemRole(mock, 2, 5, 3, todo.RoleEditor)
+				mock.ExpectRollback()
+			},
+			use: func(r *LabelPostgres) error {
+				return r.Detach(context.Background(), 2, 5, 9)
+			},
+			expectedErr: todo.ErrLabelNotFound,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			db, mock := newMockDB(t)
+			testCase.mockBehavior(mock)
+
+			err := testCase.use(NewLabelPostgres(db))
+
+			assert.ErrorIs(t, err, testCase.expectedErr)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestTodoItemPostgres_GetAll_tags(t *testing.T) {
+	testTable := []struct {
+		name          string
+		match         string
+		expectedCount string
+	}{
+		{
+			name:          "Any",
+			match:         todo.TagMatchAny,
+			expectedCount: ") > 0",
+		},
+		{
+			name:          "All",
+			match:         todo.TagMatchAll,
+			expectedCount: ") = 2",
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			db, mock := newMockDB(t)
+
+			// only the labels of the requesting user are matched
+			mock.ExpectQuery(regexp.QuoteMeta("WHERE il.item_id = ti.id AND l.user_id = $1 AND lower(l.name) = ANY($3)"+testCase.expectedCount)).
+				WithArgs(1, 3, pq.Array([]string{"home", "work"}), todo.DefaultItemsLimit+1).
+				WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+			filter := todo.ItemFilter{Tags: []string{"home", "work"}, TagMatch: testCase.match, Sort: "position", Order: todo.OrderAsc, Limit: todo.DefaultItemsLimit}
+			_, _, err := NewTodoItemPostgres(db).GetAll(context.Background(), 1, 3, filter)
+
+			assert.NoError(t, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestLabelPostgres_version(t *testing.T) {
+	testTable := []struct {
+		name         string
+		mockBehavior func(mock sqlmock.Sqlmock)
+		change       func(r *LabelPostgres) error
+		expectedErr  error
+	}{
+		{
+			name: "Attach",
+			mockBehavior: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				expectItemRole(mock, 1, 5, 3, todo.RoleViewer)
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT id FROM labels WHERE id = $1 AND user_id = $2")).
+					WithArgs(9, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(9))
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO items_labels")).
+					WithArgs(5, 9).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				expectItemVersion(mock, 5)
+				mock.ExpectCommit()
+			},
+			change: func(r *LabelPostgres) error {
+				return r.Attach(context.Background(), 1, 5, 9)
+			},
+		},
+		{
+			name: "Attach Again",
+			mockBehavior: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				expectItemRole(mock, 1, 5, 3, todo.RoleViewer)
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT id FROM labels WHERE id = $1 AND user_id = $2")).
+					WithArgs(9, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(9))
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO items_labels")).
+					WithArgs(5, 9).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectCommit()
+			},
+			change: func(r *LabelPostgres) error {
+				return r.Attach(context.Background(), 1, 5, 9)
+			},
+		},
+		{
+			name: "Detach",
+			mockBehavior: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta("DELETE FROM items_labels il USING labels l")).
+					WithArgs(1, 5, 9).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				expectItemVersion(mock, 5)
+				mock.ExpectCommit()
+			},
+			change: func(r *LabelPostgres) error {
+				return r.Detach(context.Background(), 1, 5, 9)
+			},
+		},
+		{
+			name: "Detach Missing",
+			mockBehavior: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta("DELETE FROM items_labels il USING labels l")).
+					WithArgs(1, 5, 9).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				expectItemRole(mock, 1, 5, 3, todo.RoleViewer)
+				mock.ExpectRollback()
+			},
+			change: func(r *LabelPostgres) error {
+				return r.Detach(context.Background(), 1, 5, 9)
+			},
+			expectedErr: todo.ErrLabelNotFound,
+		},
+		{
+			name: "Rename",
+			mockBehavior: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE labels SET name=$1 WHERE id = $2 AND user_id = $3")).
+					WithArgs("home", 9, 1).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE todo_items SET version = version + 1 WHERE id IN")).
+					WithArgs(9).
+					WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectCommit()
+			},
+			change: func(r *LabelPostgres) error {
+				name := "home"
+				return r.Update(context.Background(), 1, 9, todo.UpdateLabelInput{Name: &name})
+			},
+		},
+		{
+			name: "Delete",
+			mockBehavior: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT id FROM labels WHERE id = $1 AND user_id = $2 FOR UPDATE")).
+					WithArgs(9, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(9))
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE todo_items SET version = version + 1 WHERE id IN")).
+					WithArgs(9).
+					WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectExec(regexp.QuoteMeta("DELETE FROM labels WHERE id = $1")).
+					WithArgs(9).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			change: func(r *LabelPostgres) error {
+				return r.Delete(context.Background(), 1, 9)
+			},
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			db, mock := newMockDB(t)
+			testCase.mockBehavior(mock)
+
+			err := testCase.change(NewLabelPostgres(db))
+
+			assert.ErrorIs(t, err, testCase.expectedErr)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,244 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lypolix/todo-app"
+)
+
+// Roles allowed to change list contents and to delete, for use in WHERE clauses.
+const (
+	editRoles   = "'owner', 'editor'"
+	deleteRoles = "'owner'"
+)
+
+type ListMemberPostgres struct {
+	db *sqlx.DB
+}
+
+func NewListMemberPostgres(db *sqlx.DB) *ListMemberPostgres {
+	return &ListMemberPostgres{db: db}
+}
+
+func (r *ListMemberPostgres) Add(ctx context.Context, userId, listId int, input todo.AddMemberInput) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if err := requireListRole(ctx, tx, userId, listId, todo.RoleOwner); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	var memberId int
+	userQuery := fmt.Sprintf("SELECT id FROM %s WHERE username=$1", usersTable)
+	if err := tx.GetContext(ctx, &memberId, userQuery, input.Username); err != nil {
+		tx.Rollback()
+		return notFound(err, todo.ErrUserNotFound)
+	}
+
+	addQuery := fmt.Sprintf("INSERT INTO %s (user_id, list_id, role) VALUES ($1, $2, $3)", usersListsTable)
+	if _, err := tx.ExecContext(ctx, addQuery, memberId, listId, input.Role); err != nil {
+		tx.Rollback()
+		if isUniqueViolation(err) {
+			return todo.ErrAlreadyMember
+		}
+		return err
+	}
+
+	if err := bumpListVersion(ctx, tx, listId); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *ListMemberPostgres) GetAll(ctx context.Context, userId, listId int) ([]todo.ListMember, error) {
+	if err := requireListRole(ctx, r.db, userId, listId, todo.RoleOwner, todo.RoleEditor, todo.RoleViewer); err != nil {
+		return nil, err
+	}
+
+	var members []todo.ListMember
+	query := fmt.Sprintf(`SELECT u.id AS user_id, u.name, u.username, ul.role FROM %s ul
+							INNER JOIN %s u on u.id = ul.user_id WHERE ul.list_id = $1 ORDER BY ul.id`,
+		usersListsTable, usersTable)
+	err := r.db.SelectContext(ctx, &members, query, listId)
+
+	return members, err
+}
+
+func (r *ListMemberPostgres) UpdateRole(ctx context.Context, userId, listId, memberId int, input todo.UpdateMemberInput) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if err := requireListRole(ctx, tx, userId, listId, todo.RoleOwner); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	query := fmt.Sprintf("UPDATE %s SET role=$1 WHERE list_id=$2 AND user_id=$3 AND role <> $4", usersListsTable)
+	res, err := tx.ExecContext(ctx, query, input.Role, listId, memberId, todo.RoleOwner)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := checkAffected(res, func() error {
+		return requireMemberRole(ctx, tx, memberId, listId, todo.RoleEditor, todo.RoleViewer)
+	}); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := bumpListVersion(ctx, tx, listId); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Delete removes a member from the list. The owner can remove any other
+// member, everybody else can only leave the list.
+func (r *ListMemberPostgres) Delete(ctx context.Context, userId, listId, memberId int) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	roles := []string{todo.RoleOwner}
+	if userId == memberId {
+		roles = []string{todo.RoleEditor, todo.RoleViewer}
+	}
+	if err := requireListRole(ctx, tx, userId, listId, roles...); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	query := fmt.Sprintf("DELETE FROM %s WHERE list_id=$1 AND user_id=$2 AND role <> $3", usersListsTable)
+	res, err := tx.ExecContext(ctx, query, listId, memberId, todo.RoleOwner)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := checkAffected(res, func() error {
+		return requireMemberRole(ctx, tx, memberId, listId, todo.RoleEditor, todo.RoleViewer)
+	}); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := bumpListVersion(ctx, tx, listId); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// TransferOwnership makes an existing member the owner; the previous owner stays as an editor.
+func (r *ListMemberPostgres) TransferOwnership(ctx context.Context, userId, listId, newOwnerId int) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if err := requireListRole(ctx, tx, userId, listId, todo.RoleOwner); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	query := fmt.Sprintf("UPDATE %s SET role=$1 WHERE list_id=$2 AND user_id=$3", usersListsTable)
+
+	res, err := tx.ExecContext(ctx, query, todo.RoleOwner, listId, newOwnerId)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := checkAffected(res, func() error { return todo.ErrMemberNotFound }); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if userId != newOwnerId {
+		if _, err := tx.ExecContext(ctx, query, todo.RoleEditor, listId, userId); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	if err := bumpListVersion(ctx, tx, listId); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// requireListRole returns todo.ErrListNotFound when the user is not a member of
+// the list or the list is in the trash and todo.ErrListForbidden when the
+// member's role is not one of roles.
+func requireListRole(ctx context.Context, q sqlx.QueryerContext, userId, listId int, roles ...string) error {
+	var role string
+	query := fmt.Sprintf(`SELECT ul.role FROM %s ul INNER JOIN %s tl on tl.id = ul.list_id
+							WHERE ul.user_id=$1 AND ul.list_id=$2 AND tl.deleted_at IS NULL`, usersListsTable, todoListsTable)
+	if err := sqlx.GetContext(ctx, q, &role, query, userId, listId); err != nil {
+		return notFound(err, todo.ErrListNotFound)
+	}
+
+	for _, allowed := range roles {
+		if role == allowed {
+			return nil
+		}
+	}
+
+	return todo.ErrListForbidden
+}
+
+// requireMemberRole is requireListRole for another member of the list, so a
+// missing membership means the member rather than the list is not found.
+func requireMemberRole(ctx context.Context, q sqlx.QueryerContext, memberId, listId int, roles ...string) error {
+	if err := requireListRole(ctx, q, memberId, listId, roles...); !errors.Is(err, todo.ErrListNotFound) {
+		return err
+	}
+
+	return todo.ErrMemberNotFound
+}
+
+// requireItemRole is requireListRole for the list the item belongs to. Items
+// in the trash are not found.
+func requireItemRole(ctx context.Context, q sqlx.QueryerContext, userId, itemId int, roles ...string) error {
+	var listId int
+	query := fmt.Sprintf(`SELECT li.list_id FROM %s li INNER JOIN %s ul on ul.list_id = li.list_id
+							INNER JOIN %s ti on ti.id = li.item_id WHERE li.item_id = $1 AND ul.user_id = $2 AND ti.deleted_at IS NULL`,
+		listsItemsTable, usersListsTable, todoItemsTable)
+	if err := sqlx.GetContext(ctx, q, &listId, query, itemId, userId); err != nil {
+		return notFound(err, todo.ErrItemNotFound)
+	}
+
+	return requireListRole(ctx, q, userId, listId, roles...)
+}
+
+// checkAffected turns a statement that matched no rows into the error returned by explain.
+func checkAffected(res sql.Result, explain func() error) error {
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		if err := explain(); err != nil {
+			return err
+		}
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
@@ -0,0 +1,133 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lypolix/todo-app"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListMemberPostgres_version(t *testing.T) {
+	testTable := []struct {
+		name         string
+		mockBehavior func(mock sqlmock.Sqlmock)
+		change       func(r *ListMemberPostgres) error
+		expectedErr  error
+	}{
+		{
+			name: "Add",
+			mockBehavior: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				expectListRole(mock, 1, 3, todo.RoleOwner)
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT id FROM users WHERE username=$1")).
+					WithArgs("bob").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO users_lists")).
+					WithArgs(2, 3, todo.RoleEditor).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				expectListVersion(mock, 3)
+				mock.ExpectCommit()
+			},
+			change: func(r *ListMemberPostgres) error {
+				return r.Add(context.Background(), 1, 3, todo.AddMemberInput{Username: "bob", Role: todo.RoleEditor})
+			},
+		},
+		{
+			name: "Update Role",
+			mockBehavior: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				expectListRole(mock, 1, 3, todo.RoleOwner)
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE users_lists SET role=$1")).
+					WithArgs(todo.RoleViewer, 3, 2, todo.RoleOwner).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				expectListVersion(mock, 3)
+				mock.ExpectCommit()
+			},
+			change: func(r *ListMemberPostgres) error {
+				return r.UpdateRole(context.Background(), 1, 3, 2, todo.UpdateMemberInput{Role: todo.RoleViewer})
+			},
+		},
+		{
+			name: "Update Role Of Stranger",
+			mockBehavior: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				expectListRole(mock, 1, 3, todo.RoleOwner)
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE users_lists SET role=$1")).
+					WithArgs(todo.RoleViewer, 3, 2, todo.RoleOwner).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT ul.role FROM users_lists ul")).
+					WithArgs(2, 3).
+					WillReturnError(sql.ErrNoRows)
+				mock.ExpectRollback()
+			},
+			change: func(r *ListMemberPostgres) error {
+				return r.UpdateRole(context.Background(), 1, 3, 2, todo.UpdateMemberInput{Role: todo.RoleViewer})
+			},
+			expectedErr: todo.ErrMemberNotFound,
+		},
+		{
+			name: "Remove",
+			mockBehavior: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				expectListRole(mock, 1, 3, todo.RoleOwner)
+				mock.ExpectExec(regexp.QuoteMeta("DELETE FROM users_lists")).
+					WithArgs(3, 2, todo.RoleOwner).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				expectListVersion(mock, 3)
+				mock.ExpectCommit()
+			},
+			change: func(r *ListMemberPostgres) error {
+				return r.Delete(context.Background(), 1, 3, 2)
+			},
+		},
+		{
+			name: "Leave",
+			mockBehavior: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				expectListRole(mock, 2, 3, todo.RoleViewer)
+				mock.ExpectExec(regexp.QuoteMeta("DELETE FROM users_lists")).
+					WithArgs(3, 2, todo.RoleOwner).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				expectListVersion(mock, 3)
+				mock.ExpectCommit()
+			},
+			change: func(r *ListMemberPostgres) error {
+				return r.Delete(context.Background(), 2, 3, 2)
+			},
+		},
+		{
+			name: "Transfer",
+			mockBehavior: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				expectListRole(mock, 1, 3, todo.RoleOwner)
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE users_lists SET role=$1")).
+					WithArgs(todo.RoleOwner, 3, 2).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE users_lists SET role=$1")).
+					WithArgs(todo.RoleEditor, 3, 1).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				expectListVersion(mock, 3)
+				mock.ExpectCommit()
+			},
+			change: func(r *ListMemberPostgres) error {
+				return r.TransferOwnership(context.Background(), 1, 3, 2)
+			},
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			db, mock := newMockDB(t)
+			testCase.mockBehavior(mock)
+
+			err := testCase.change(NewListMemberPostgres(db))
+
+			assert.ErrorIs(t, err, testCase.expectedErr)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,1367 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: repository.go
+
+// Package mock_repository is a generated GoMock package.
+package mock_repository
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	todo "github.com/lypolix/todo-app"
+)
+
+// MockAuthorization is a mock of Authorization interface.
+type MockAuthorization struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuthorizationMockRecorder
+}
+
+// MockAuthorizationMockRecorder is the mock recorder for MockAuthorization.
+type MockAuthorizationMockRecorder struct {
+	mock *MockAuthorization
+}
+
+// NewMockAuthorization creates a new mock instance.
+func NewMockAuthorization(ctrl *gomock.Controller) *MockAuthorization {
+	mock := &MockAuthorization{ctrl: ctrl}
+	mock.recorder = &MockAuthorizationMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAuthorization) EXPECT() *MockAuthorizationMockRecorder {
+	return m.recorder
+}
+
+// CreateRefreshToken mocks base method.
+func (m *MockAuthorization) CreateRefreshToken(ctx context.Context, token todo.RefreshToken) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRefreshToken", ctx, token)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateRefreshToken indicates an expected call of CreateRefreshToken.
+func (mr *MockAuthorizationMockRecorder) CreateRefreshToken(ctx, token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRefreshToken", reflect.TypeOf((*MockAuthorization)(nil).CreateRefreshToken), ctx, token)
+}
+
+// CreateUser mocks base method.
+func (m *MockAuthorization) CreateUser(ctx context.Context, user todo.User) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUser", ctx, user)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateUser indicates an expected call of CreateUser.
+func (mr *MockAuthorizationMockRecorder) CreateUser(ctx, user interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockAuthorization)(nil).CreateUser), ctx, user)
+}
+
+// GetRefreshToken mocks base method.
+func (m *MockAuthorization) GetRefreshToken(ctx context.Context, tokenHash string) (todo.RefreshToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRefreshToken", ctx, tokenHash)
+	ret0, _ := ret[0].(todo.RefreshToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRefreshToken indicates an expected call of GetRefreshToken.
+func (mr *MockAuthorizationMockRecorder) GetRefreshToken(ctx, tokenHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRefreshToken", reflect.TypeOf((*MockAuthorization)(nil).GetRefreshToken), ctx, tokenHash)
+}
+
+// GetUser mocks base method.
+func (m *MockAuthorization) GetUser(ctx context.Context, username string) (todo.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUser", ctx, username)
+	ret0, _ := ret[0].(todo.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUser indicates an expected call of GetUser.
+func (mr *MockAuthorizationMockRecorder) GetUser(ctx, username interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUser", reflect.TypeOf((*MockAuthorization)(nil).GetUser), ctx, username)
+}
+
+// IsTokenRevoked mocks base method.
+func (m *MockAuthorization) IsTokenRevoked(ctx context.Context, userId int, jti string, issuedAt time.Time) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsTokenRevoked", ctx, userId, jti, issuedAt)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsTokenRevoked indicates an expected call of IsTokenRevoked.
+func (mr *MockAuthorizationMockRecorder) IsTokenRevoked(ctx, userId, jti, issuedAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsTokenRevoked", reflect.TypeOf((*MockAuthorization)(nil).IsTokenRevoked), ctx, userId, jti, issuedAt)
+}
+
+// RevokeAccessToken mocks base method.
+func (m *MockAuthorization) RevokeAccessToken(ctx context.Context, jti string, expiresAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeAccessToken", ctx, jti, expiresAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeAccessToken indicates an expected call of RevokeAccessToken.
+func (mr *MockAuthorizationMockRecorder) RevokeAccessToken(ctx, jti, expiresAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeAccessToken", reflect.TypeOf((*MockAuthorization)(nil).RevokeAccessToken), ctx, jti, expiresAt)
+}
+
+// RevokeAllTokens mocks base method.
+func (m *MockAuthorization) RevokeAllTokens(ctx context.Context, userId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeAllTokens", ctx, userId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeAllTokens indicates an expected call of RevokeAllTokens.
+func (mr *MockAuthorizationMockRecorder) RevokeAllTokens(ctx, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeAllTokens", reflect.TypeOf((*MockAuthorization)(nil).RevokeAllTokens), ctx, userId)
+}
+
+// RevokeRefreshToken mocks base method.
+func (m *MockAuthorization) RevokeRefreshToken(ctx context.Context, userId int, tokenHash string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeRefreshToken", ctx, userId, tokenHash)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeRefreshToken indicates an expected call of RevokeRefreshToken.
+func (mr *MockAuthorizationMockRecorder) RevokeRefreshToken(ctx, userId, tokenHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeRefreshToken", reflect.TypeOf((*MockAuthorization)(nil).RevokeRefreshToken), ctx, userId, tokenHash)
+}
+
+// RotateRefreshToken mocks base method.
+func (m *MockAuthorization) RotateRefreshToken(ctx context.Context, tokenId int, next todo.RefreshToken) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RotateRefreshToken", ctx, tokenId, next)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RotateRefreshToken indicates an expected call of RotateRefreshToken.
+func (mr *MockAuthorizationMockRecorder) RotateRefreshToken(ctx, tokenId, next interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RotateRefreshToken", reflect.TypeOf((*MockAuthorization)(nil).RotateRefreshToken), ctx, tokenId, next)
+}
+
+// UpdatePasswordHash mocks base method.
+func (m *MockAuthorization) UpdatePasswordHash(ctx context.Context, userId int, passwordHash string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePasswordHash", ctx, userId, passwordHash)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdatePasswordHash indicates an expected call of UpdatePasswordHash.
+func (mr *MockAuthorizationMockRecorder) UpdatePasswordHash(ctx, userId, passwordHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePasswordHash", reflect.TypeOf((*MockAuthorization)(nil).UpdatePasswordHash), ctx, userId, passwordHash)
+}
+
+// MockTodoList is a mock of TodoList interface.
+type MockTodoList struct {
+	ctrl     *gomock.Controller
+	recorder *MockTodoListMockRecorder
+}
+
+// MockTodoListMockRecorder is the mock recorder for MockTodoList.
+type MockTodoListMockRecorder struct {
+	mock *MockTodoList
+}
+
+// NewMockTodoList creates a new mock instance.
+func NewMockTodoList(ctrl *gomock.Controller) *MockTodoList {
+	mock := &MockTodoList{ctrl: ctrl}
+	mock.recorder = &MockTodoListMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTodoList) EXPECT() *MockTodoListMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockTodoList) Create(ctx context.Context, userId int, list todo.TodoList) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, userId, list)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockTodoListMockRecorder) Create(ctx, userId, list interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockTodoList)(nil).Create), ctx, userId, list)
+}
+
+// Delete mocks base method.
+func (m *MockTodoList) Delete(ctx context.Context, userId, listId int, version *int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, userId, listId, version)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockTodoListMockRecorder) Delete(ctx, userId, listId, version interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockTodoList)(nil).Delete), ctx, userId, listId, version)
+}
+
+// GetAll mocks base method.
+func (m *MockTodoList) GetAll(ctx context.Context, userId int) ([]todo.TodoList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", ctx, userId)
+	ret0, _ := ret[0].([]todo.TodoList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockTodoListMockRecorder) GetAll(ctx, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockTodoList)(nil).GetAll), ctx, userId)
+}
+
+// GetById mocks base method.
+func (m *MockTodoList) GetById(ctx context.Context, userId, listId int) (todo.TodoList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetById", ctx, userId, listId)
+	ret0, _ := ret[0].(todo.TodoList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetById indicates an expected call of GetById.
+func (mr *MockTodoListMockRecorder) GetById(ctx, userId, listId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetById", reflect.TypeOf((*MockTodoList)(nil).GetById), ctx, userId, listId)
+}
+
+// Import mocks base method.
+func (m *MockTodoList) Import(ctx context.Context, userId int, list todo.TodoList, items []todo.TodoItem, dryRun bool) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Import", ctx, userId, list, items, dryRun)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Import indicates an expected call of Import.
+func (mr *MockTodoListMockRecorder) Import(ctx, userId, list, items, dryRun interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Import", reflect.TypeOf((*MockTodoList)(nil).Import), ctx, userId, list, items, dryRun)
+}
+
+// Update mocks base method.
+func (m *MockTodoList) Update(ctx context.Context, userId, listId int, input todo.UpdateListInput) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, userId, listId, input)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockTodoListMockRecorder) Update(ctx, userId, listId, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockTodoList)(nil).Update), ctx, userId, listId, input)
+}
+
+// MockTodoItem is a mock of TodoItem interface.
+type MockTodoItem struct {
+	ctrl     *gomock.Controller
+	recorder *MockTodoItemMockRecorder
+}
+
+// MockTodoItemMockRecorder is the mock recorder for MockTodoItem.
+type MockTodoItemMockRecorder struct {
+	mock *MockTodoItem
+}
+
+// NewMockTodoItem creates a new mock instance.
+func NewMockTodoItem(ctrl *gomock.Controller) *MockTodoItem {
+	mock := &MockTodoItem{ctrl: ctrl}
+	mock.recorder = &MockTodoItemMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTodoItem) EXPECT() *MockTodoItemMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockTodoItem) Create(ctx context.Context, userId, listId int, item todo.TodoItem) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, userId, listId, item)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockTodoItemMockRecorder) Create(ctx, userId, listId, item interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockTodoItem)(nil).Create), ctx, userId, listId, item)
+}
+
+// CreateAll mocks base method.
+func (m *MockTodoItem) CreateAll(ctx context.Context, userId, listId int, items []todo.TodoItem) ([]int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAll", ctx, userId, listId, items)
+	ret0, _ := ret[0].([]int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAll indicates an expected call of CreateAll.
+func (mr *MockTodoItemMockRecorder) CreateAll(ctx, userId, listId, items interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAll", reflect.TypeOf((*MockTodoItem)(nil).CreateAll), ctx, userId, listId, items)
+}
+
+// CreateOccurrence mocks base method.
+func (m *MockTodoItem) CreateOccurrence(ctx context.Context, userId, itemId int, recurrenceId time.Time) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateOccurrence", ctx, userId, itemId, recurrenceId)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateOccurrence indicates an expected call of CreateOccurrence.
+func (mr *MockTodoItemMockRecorder) CreateOccurrence(ctx, userId, itemId, recurrenceId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOccurrence", reflect.TypeOf((*MockTodoItem)(nil).CreateOccurrence), ctx, userId, itemId, recurrenceId)
+}
+
+// Delete mocks base method.
+func (m *MockTodoItem) Delete(ctx context.Context, userId, itemId int, version *int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, userId, itemId, version)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockTodoItemMockRecorder) Delete(ctx, userId, itemId, version interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockTodoItem)(nil).Delete), ctx, userId, itemId, version)
+}
+
+// Find mocks base method.
+func (m *MockTodoItem) Find(ctx context.Context, userId int, filter todo.ItemFilter) ([]todo.TodoItem, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Find", ctx, userId, filter)
+	ret0, _ := ret[0].([]todo.TodoItem)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Find indicates an expected call of Find.
+func (mr *MockTodoItemMockRecorder) Find(ctx, userId, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Find", reflect.TypeOf((*MockTodoItem)(nil).Find), ctx, userId, filter)
+}
+
+// GetAgenda mocks base method.
+func (m *MockTodoItem) GetAgenda(ctx context.Context, userId int, dueBefore time.Time, undatedPriority todo.Priority) ([]todo.TodoItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAgenda", ctx, userId, dueBefore, undatedPriority)
+	ret0, _ := ret[0].([]todo.TodoItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAgenda indicates an expected call of GetAgenda.
+func (mr *MockTodoItemMockRecorder) GetAgenda(ctx, userId, dueBefore, undatedPriority interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAgenda", reflect.TypeOf((*MockTodoItem)(nil).GetAgenda), ctx, userId, dueBefore, undatedPriority)
+}
+
+// GetAll mocks base method.
+func (m *MockTodoItem) GetAll(ctx context.Context, userId, listId int, filter todo.ItemFilter) ([]todo.TodoItem, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", ctx, userId, listId, filter)
+	ret0, _ := ret[0].([]todo.TodoItem)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockTodoItemMockRecorder) GetAll(ctx, userId, listId, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockTodoItem)(nil).GetAll), ctx, userId, listId, filter)
+}
+
+// GetById mocks base method.
+func (m *MockTodoItem) GetById(ctx context.Context, userId, itemId int) (todo.TodoItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetById", ctx, userId, itemId)
+	ret0, _ := ret[0].(todo.TodoItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetById indicates an expected call of GetById.
+func (mr *MockTodoItemMockRecorder) GetById(ctx, userId, itemId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetById", reflect.TypeOf((*MockTodoItem)(nil).GetById), ctx, userId, itemId)
+}
+
+// GetDueBetween mocks base method.
+func (m *MockTodoItem) GetDueBetween(ctx context.Context, from, to time.Time) ([]todo.UserItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDueBetween", ctx, from, to)
+	ret0, _ := ret[0].([]todo.UserItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDueBetween indicates an expected call of GetDueBetween.
+func (mr *MockTodoItemMockRecorder) GetDueBetween(ctx, from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDueBetween", reflect.TypeOf((*MockTodoItem)(nil).GetDueBetween), ctx, from, to)
+}
+
+// GetSeries mocks base method.
+func (m *MockTodoItem) GetSeries(ctx context.Context, userId, seriesId int) (todo.ItemSeries, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSeries", ctx, userId, seriesId)
+	ret0, _ := ret[0].(todo.ItemSeries)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSeries indicates an expected call of GetSeries.
+func (mr *MockTodoItemMockRecorder) GetSeries(ctx, userId, seriesId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSeries", reflect.TypeOf((*MockTodoItem)(nil).GetSeries), ctx, userId, seriesId)
+}
+
+// Move mocks base method.
+func (m *MockTodoItem) Move(ctx context.Context, userId, itemId int, input todo.MoveItemInput) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Move", ctx, userId, itemId, input)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Move indicates an expected call of Move.
+func (mr *MockTodoItemMockRecorder) Move(ctx, userId, itemId, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Move", reflect.TypeOf((*MockTodoItem)(nil).Move), ctx, userId, itemId, input)
+}
+
+// Update mocks base method.
+func (m *MockTodoItem) Update(ctx context.Context, userId, itemId int, input todo.UpdateItemInput) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, userId, itemId, input)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockTodoItemMockRecorder) Update(ctx, userId, itemId, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockTodoItem)(nil).Update), ctx, userId, itemId, input)
+}
+
+// MockSubtask is a mock of Subtask interface.
+type MockSubtask struct {
+	ctrl     *gomock.Controller
+	recorder *MockSubtaskMockRecorder
+}
+
+// MockSubtaskMockRecorder is the mock recorder for MockSubtask.
+type MockSubtaskMockRecorder struct {
+	mock *MockSubtask
+}
+
+// NewMockSubtask creates a new mock instance.
+func NewMockSubtask(ctrl *gomock.Controller) *MockSubtask {
+	mock := &MockSubtask{ctrl: ctrl}
+	mock.recorder = &MockSubtaskMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSubtask) EXPECT() *MockSubtaskMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockSubtask) Create(ctx context.Context, userId, itemId int, subtask todo.Subtask) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, userId, itemId, subtask)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockSubtaskMockRecorder) Create(ctx, userId, itemId, subtask interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockSubtask)(nil).Create), ctx, userId, itemId, subtask)
+}
+
+// Delete mocks base method.
+func (m *MockSubtask) Delete(ctx context.Context, userId, itemId, subtaskId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, userId, itemId, subtaskId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockSubtaskMockRecorder) Delete(ctx, userId, itemId, subtaskId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockSubtask)(nil).Delete), ctx, userId, itemId, subtaskId)
+}
+
+// GetAll mocks base method.
+func (m *MockSubtask) GetAll(ctx context.Context, userId, itemId int) ([]todo.Subtask, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", ctx, userId, itemId)
+	ret0, _ := ret[0].([]todo.Subtask)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockSubtaskMockRecorder) GetAll(ctx, userId, itemId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockSubtask)(nil).GetAll), ctx, userId, itemId)
+}
+
+// Update mocks base method.
+func (m *MockSubtask) Update(ctx context.Context, userId, itemId, subtaskId int, input todo.UpdateSubtaskInput) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, userId, itemId, subtaskId, input)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockSubtaskMockRecorder) Update(ctx, userId, itemId, subtaskId, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockSubtask)(nil).Update), ctx, userId, itemId, subtaskId, input)
+}
+
+// MockComment is a mock of Comment interface.
+type MockComment struct {
+	ctrl     *gomock.Controller
+	recorder *MockCommentMockRecorder
+}
+
+// MockCommentMockRecorder is the mock recorder for MockComment.
+type MockCommentMockRecorder struct {
+	mock *MockComment
+}
+
+// NewMockComment creates a new mock instance.
+func NewMockComment(ctrl *gomock.Controller) *MockComment {
+	mock := &MockComment{ctrl: ctrl}
+	mock.recorder = &MockCommentMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockComment) EXPECT() *MockCommentMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockComment) Create(ctx context.Context, userId, itemId int, comment todo.Comment) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, userId, itemId, comment)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockCommentMockRecorder) Create(ctx, userId, itemId, comment interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockComment)(nil).Create), ctx, userId, itemId, comment)
+}
+
+// Delete mocks base method.
+func (m *MockComment) Delete(ctx context.Context, userId, itemId, commentId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, userId, itemId, commentId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockCommentMockRecorder) Delete(ctx, userId, itemId, commentId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockComment)(nil).Delete), ctx, userId, itemId, commentId)
+}
+
+// GetAll mocks base method.
+func (m *MockComment) GetAll(ctx context.Context, userId, itemId int) ([]todo.Comment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", ctx, userId, itemId)
+	ret0, _ := ret[0].([]todo.Comment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockCommentMockRecorder) GetAll(ctx, userId, itemId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockComment)(nil).GetAll), ctx, userId, itemId)
+}
+
+// GetById mocks base method.
+func (m *MockComment) GetById(ctx context.Context, userId, itemId, commentId int) (todo.Comment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetById", ctx, userId, itemId, commentId)
+	ret0, _ := ret[0].(todo.Comment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetById indicates an expected call of GetById.
+func (mr *MockCommentMockRecorder) GetById(ctx, userId, itemId, commentId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetById", reflect.TypeOf((*MockComment)(nil).GetById), ctx, userId, itemId, commentId)
+}
+
+// GetItemUsers mocks base method.
+func (m *MockComment) GetItemUsers(ctx context.Context, itemId int) ([]int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetItemUsers", ctx, itemId)
+	ret0, _ := ret[0].([]int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetItemUsers indicates an expected call of GetItemUsers.
+func (mr *MockCommentMockRecorder) GetItemUsers(ctx, itemId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetItemUsers", reflect.TypeOf((*MockComment)(nil).GetItemUsers), ctx, itemId)
+}
+
+// Update mocks base method.
+func (m *MockComment) Update(ctx context.Context, userId, itemId, commentId int, input todo.UpdateCommentInput) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, userId, itemId, commentId, input)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockCommentMockRecorder) Update(ctx, userId, itemId, commentId, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockComment)(nil).Update), ctx, userId, itemId, commentId, input)
+}
+
+// MockAttachment is a mock of Attachment interface.
+type MockAttachment struct {
+	ctrl     *gomock.Controller
+	recorder *MockAttachmentMockRecorder
+}
+
+// MockAttachmentMockRecorder is the mock recorder for MockAttachment.
+type MockAttachmentMockRecorder struct {
+	mock *MockAttachment
+}
+
+// NewMockAttachment creates a new mock instance.
+func NewMockAttachment(ctrl *gomock.Controller) *MockAttachment {
+	mock := &MockAttachment{ctrl: ctrl}
+	mock.recorder = &MockAttachmentMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAttachment) EXPECT() *MockAttachmentMockRecorder {
+	return m.recorder
+}
+
+// CheckUpload mocks base method.
+func (m *MockAttachment) CheckUpload(ctx context.Context, userId, itemId int, size, quota int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckUpload", ctx, userId, itemId, size, quota)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CheckUpload indicates an expected call of CheckUpload.
+func (mr *MockAttachmentMockRecorder) CheckUpload(ctx, userId, itemId, size, quota interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckUpload", reflect.TypeOf((*MockAttachment)(nil).CheckUpload), ctx, userId, itemId, size, quota)
+}
+
+// Create mocks base method.
+func (m *MockAttachment) Create(ctx context.Context, userId, itemId int, attachment todo.Attachment, quota int64) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, userId, itemId, attachment, quota)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockAttachmentMockRecorder) Create(ctx, userId, itemId, attachment, quota interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockAttachment)(nil).Create), ctx, userId, itemId, attachment, quota)
+}
+
+// Delete mocks base method.
+func (m *MockAttachment) Delete(ctx context.Context, userId, itemId, attachmentId int) (todo.Attachment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, userId, itemId, attachmentId)
+	ret0, _ := ret[0].(todo.Attachment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockAttachmentMockRecorder) Delete(ctx, userId, itemId, attachmentId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockAttachment)(nil).Delete), ctx, userId, itemId, attachmentId)
+}
+
+// DeleteDetached mocks base method.
+func (m *MockAttachment) DeleteDetached(ctx context.Context, storageKey string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteDetached", ctx, storageKey)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteDetached indicates an expected call of DeleteDetached.
+func (mr *MockAttachmentMockRecorder) DeleteDetached(ctx, storageKey interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteDetached", reflect.TypeOf((*MockAttachment)(nil).DeleteDetached), ctx, storageKey)
+}
+
+// GetAll mocks base method.
+func (m *MockAttachment) GetAll(ctx context.Context, userId, itemId int) ([]todo.Attachment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", ctx, userId, itemId)
+	ret0, _ := ret[0].([]todo.Attachment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockAttachmentMockRecorder) GetAll(ctx, userId, itemId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockAttachment)(nil).GetAll), ctx, userId, itemId)
+}
+
+// GetById mocks base method.
+func (m *MockAttachment) GetById(ctx context.Context, userId, itemId, attachmentId int) (todo.Attachment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetById", ctx, userId, itemId, attachmentId)
+	ret0, _ := ret[0].(todo.Attachment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetById indicates an expected call of GetById.
+func (mr *MockAttachmentMockRecorder) GetById(ctx, userId, itemId, attachmentId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetById", reflect.TypeOf((*MockAttachment)(nil).GetById), ctx, userId, itemId, attachmentId)
+}
+
+// GetDetached mocks base method.
+func (m *MockAttachment) GetDetached(ctx context.Context) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDetached", ctx)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDetached indicates an expected call of GetDetached.
+func (mr *MockAttachmentMockRecorder) GetDetached(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDetached", reflect.TypeOf((*MockAttachment)(nil).GetDetached), ctx)
+}
+
+// MockSearch is a mock of Search interface.
+type MockSearch struct {
+	ctrl     *gomock.Controller
+	recorder *MockSearchMockRecorder
+}
+
+// MockSearchMockRecorder is the mock recorder for MockSearch.
+type MockSearchMockRecorder struct {
+	mock *MockSearch
+}
+
+// NewMockSearch creates a new mock instance.
+func NewMockSearch(ctrl *gomock.Controller) *MockSearch {
+	mock := &MockSearch{ctrl: ctrl}
+	mock.recorder = &MockSearchMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSearch) EXPECT() *MockSearchMockRecorder {
+	return m.recorder
+}
+
+// Search mocks base method.
+func (m *MockSearch) Search(ctx context.Context, userId int, tsquery string, limit int) ([]todo.SearchResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Search", ctx, userId, tsquery, limit)
+	ret0, _ := ret[0].([]todo.SearchResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Search indicates an expected call of Search.
+func (mr *MockSearchMockRecorder) Search(ctx, userId, tsquery, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockSearch)(nil).Search), ctx, userId, tsquery, limit)
+}
+
+// MockCalendar is a mock of Calendar interface.
+type MockCalendar struct {
+	ctrl     *gomock.Controller
+	recorder *MockCalendarMockRecorder
+}
+
+// MockCalendarMockRecorder is the mock recorder for MockCalendar.
+type MockCalendarMockRecorder struct {
+	mock *MockCalendar
+}
+
+// NewMockCalendar creates a new mock instance.
+func NewMockCalendar(ctrl *gomock.Controller) *MockCalendar {
+	mock := &MockCalendar{ctrl: ctrl}
+	mock.recorder = &MockCalendarMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCalendar) EXPECT() *MockCalendarMockRecorder {
+	return m.recorder
+}
+
+// DeleteToken mocks base method.
+func (m *MockCalendar) DeleteToken(ctx context.Context, userId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteToken", ctx, userId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteToken indicates an expected call of DeleteToken.
+func (mr *MockCalendarMockRecorder) DeleteToken(ctx, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteToken", reflect.TypeOf((*MockCalendar)(nil).DeleteToken), ctx, userId)
+}
+
+// GetItems mocks base method.
+func (m *MockCalendar) GetItems(ctx context.Context, userId int) ([]todo.TodoItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetItems", ctx, userId)
+	ret0, _ := ret[0].([]todo.TodoItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetItems indicates an expected call of GetItems.
+func (mr *MockCalendarMockRecorder) GetItems(ctx, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetItems", reflect.TypeOf((*MockCalendar)(nil).GetItems), ctx, userId)
+}
+
+// GetUserId mocks base method.
+func (m *MockCalendar) GetUserId(ctx context.Context, tokenHash string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserId", ctx, tokenHash)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserId indicates an expected call of GetUserId.
+func (mr *MockCalendarMockRecorder) GetUserId(ctx, tokenHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserId", reflect.TypeOf((*MockCalendar)(nil).GetUserId), ctx, tokenHash)
+}
+
+// SetToken mocks base method.
+func (m *MockCalendar) SetToken(ctx context.Context, userId int, tokenHash string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetToken", ctx, userId, tokenHash)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetToken indicates an expected call of SetToken.
+func (mr *MockCalendarMockRecorder) SetToken(ctx, userId, tokenHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetToken", reflect.TypeOf((*MockCalendar)(nil).SetToken), ctx, userId, tokenHash)
+}
+
+// MockCalDAV is a mock of CalDAV interface.
+type MockCalDAV struct {
+	ctrl     *gomock.Controller
+	recorder *MockCalDAVMockRecorder
+}
+
+// MockCalDAVMockRecorder is the mock recorder for MockCalDAV.
+type MockCalDAVMockRecorder struct {
+	mock *MockCalDAV
+}
+
+// NewMockCalDAV creates a new mock instance.
+func NewMockCalDAV(ctrl *gomock.Controller) *MockCalDAV {
+	mock := &MockCalDAV{ctrl: ctrl}
+	mock.recorder = &MockCalDAVMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCalDAV) EXPECT() *MockCalDAVMockRecorder {
+	return m.recorder
+}
+
+// CreateObject mocks base method.
+func (m *MockCalDAV) CreateObject(ctx context.Context, userId, listId int, object todo.CalendarObject) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateObject", ctx, userId, listId, object)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateObject indicates an expected call of CreateObject.
+func (mr *MockCalDAVMockRecorder) CreateObject(ctx, userId, listId, object interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateObject", reflect.TypeOf((*MockCalDAV)(nil).CreateObject), ctx, userId, listId, object)
+}
+
+// GetCalendar mocks base method.
+func (m *MockCalDAV) GetCalendar(ctx context.Context, userId, listId int) (todo.Calendar, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCalendar", ctx, userId, listId)
+	ret0, _ := ret[0].(todo.Calendar)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCalendar indicates an expected call of GetCalendar.
+func (mr *MockCalDAVMockRecorder) GetCalendar(ctx, userId, listId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCalendar", reflect.TypeOf((*MockCalDAV)(nil).GetCalendar), ctx, userId, listId)
+}
+
+// GetCalendars mocks base method.
+func (m *MockCalDAV) GetCalendars(ctx context.Context, userId int) ([]todo.Calendar, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCalendars", ctx, userId)
+	ret0, _ := ret[0].([]todo.Calendar)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCalendars indicates an expected call of GetCalendars.
+func (mr *MockCalDAVMockRecorder) GetCalendars(ctx, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCalendars", reflect.TypeOf((*MockCalDAV)(nil).GetCalendars), ctx, userId)
+}
+
+// GetObject mocks base method.
+func (m *MockCalDAV) GetObject(ctx context.Context, userId, listId int, name string) (todo.CalendarObject, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetObject", ctx, userId, listId, name)
+	ret0, _ := ret[0].(todo.CalendarObject)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetObject indicates an expected call of GetObject.
+func (mr *MockCalDAVMockRecorder) GetObject(ctx, userId, listId, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetObject", reflect.TypeOf((*MockCalDAV)(nil).GetObject), ctx, userId, listId, name)
+}
+
+// GetObjects mocks base method.
+func (m *MockCalDAV) GetObjects(ctx context.Context, userId, listId int) ([]todo.CalendarObject, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetObjects", ctx, userId, listId)
+	ret0, _ := ret[0].([]todo.CalendarObject)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetObjects indicates an expected call of GetObjects.
+func (mr *MockCalDAVMockRecorder) GetObjects(ctx, userId, listId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetObjects", reflect.TypeOf((*MockCalDAV)(nil).GetObjects), ctx, userId, listId)
+}
+
+// MockLabel is a mock of Label interface.
+type MockLabel struct {
+	ctrl     *gomock.Controller
+	recorder *MockLabelMockRecorder
+}
+
+// MockLabelMockRecorder is the mock recorder for MockLabel.
+type MockLabelMockRecorder struct {
+	mock *MockLabel
+}
+
+// NewMockLabel creates a new mock instance.
+func NewMockLabel(ctrl *gomock.Controller) *MockLabel {
+	mock := &MockLabel{ctrl: ctrl}
+	mock.recorder = &MockLabelMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLabel) EXPECT() *MockLabelMockRecorder {
+	return m.recorder
+}
+
+// Attach mocks base method.
+func (m *MockLabel) Attach(ctx context.Context, userId, itemId, labelId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Attach", ctx, userId, itemId, labelId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Attach indicates an expected call of Attach.
+func (mr *MockLabelMockRecorder) Attach(ctx, userId, itemId, labelId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Attach", reflect.TypeOf((*MockLabel)(nil).Attach), ctx, userId, itemId, labelId)
+}
+
+// Create mocks base method.
+func (m *MockLabel) Create(ctx context.Context, userId int, label todo.Label) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, userId, label)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockLabelMockRecorder) Create(ctx, userId, label interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockLabel)(nil).Create), ctx, userId, label)
+}
+
+// Delete mocks base method.
+func (m *MockLabel) Delete(ctx context.Context, userId, labelId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, userId, labelId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockLabelMockRecorder) Delete(ctx, userId, labelId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockLabel)(nil).Delete), ctx, userId, labelId)
+}
+
+// Detach mocks base method.
+func (m *MockLabel) Detach(ctx context.Context, userId, itemId, labelId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Detach", ctx, userId, itemId, labelId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Detach indicates an expected call of Detach.
+func (mr *MockLabelMockRecorder) Detach(ctx, userId, itemId, labelId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Detach", reflect.TypeOf((*MockLabel)(nil).Detach), ctx, userId, itemId, labelId)
+}
+
+// GetAll mocks base method.
+func (m *MockLabel) GetAll(ctx context.Context, userId int) ([]todo.Label, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", ctx, userId)
+	ret0, _ := ret[0].([]todo.Label)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockLabelMockRecorder) GetAll(ctx, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockLabel)(nil).GetAll), ctx, userId)
+}
+
+// Update mocks base method.
+func (m *MockLabel) Update(ctx context.Context, userId, labelId int, input todo.UpdateLabelInput) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, userId, labelId, input)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockLabelMockRecorder) Update(ctx, userId, labelId, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockLabel)(nil).Update), ctx, userId, labelId, input)
+}
+
+// MockTrash is a mock of Trash interface.
+type MockTrash struct {
+	ctrl     *gomock.Controller
+	recorder *MockTrashMockRecorder
+}
+
+// MockTrashMockRecorder is the mock recorder for MockTrash.
+type MockTrashMockRecorder struct {
+	mock *MockTrash
+}
+
+// NewMockTrash creates a new mock instance.
+func NewMockTrash(ctrl *gomock.Controller) *MockTrash {
+	mock := &MockTrash{ctrl: ctrl}
+	mock.recorder = &MockTrashMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTrash) EXPECT() *MockTrashMockRecorder {
+	return m.recorder
+}
+
+// GetAll mocks base method.
+func (m *MockTrash) GetAll(ctx context.Context, userId int) (todo.Trash, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", ctx, userId)
+	ret0, _ := ret[0].(todo.Trash)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockTrashMockRecorder) GetAll(ctx, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockTrash)(nil).GetAll), ctx, userId)
+}
+
+// Purge mocks base method.
+func (m *MockTrash) Purge(ctx context.Context, before time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Purge", ctx, before)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Purge indicates an expected call of Purge.
+func (mr *MockTrashMockRecorder) Purge(ctx, before interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Purge", reflect.TypeOf((*MockTrash)(nil).Purge), ctx, before)
+}
+
+// RestoreItem mocks base method.
+func (m *MockTrash) RestoreItem(ctx context.Context, userId, itemId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreItem", ctx, userId, itemId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestoreItem indicates an expected call of RestoreItem.
+func (mr *MockTrashMockRecorder) RestoreItem(ctx, userId, itemId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreItem", reflect.TypeOf((*MockTrash)(nil).RestoreItem), ctx, userId, itemId)
+}
+
+// RestoreList mocks base method.
+func (m *MockTrash) RestoreList(ctx context.Context, userId, listId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreList", ctx, userId, listId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestoreList indicates an expected call of RestoreList.
+func (mr *MockTrashMockRecorder) RestoreList(ctx, userId, listId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreList", reflect.TypeOf((*MockTrash)(nil).RestoreList), ctx, userId, listId)
+}
+
+// MockActivity is a mock of Activity interface.
+type MockActivity struct {
+	ctrl     *gomock.Controller
+	recorder *MockActivityMockRecorder
+}
+
+// MockActivityMockRecorder is the mock recorder for MockActivity.
+type MockActivityMockRecorder struct {
+	mock *MockActivity
+}
+
+// NewMockActivity creates a new mock instance.
+func NewMockActivity(ctrl *gomock.Controller) *MockActivity {
+	mock := &MockActivity{ctrl: ctrl}
+	mock.recorder = &MockActivityMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockActivity) EXPECT() *MockActivityMockRecorder {
+	return m.recorder
+}
+
+// GetByItem mocks base method.
+func (m *MockActivity) GetByItem(ctx context.Context, userId, itemId int, page todo.Page) ([]todo.Activity, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByItem", ctx, userId, itemId, page)
+	ret0, _ := ret[0].([]todo.Activity)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetByItem indicates an expected call of GetByItem.
+func (mr *MockActivityMockRecorder) GetByItem(ctx, userId, itemId, page interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByItem", reflect.TypeOf((*MockActivity)(nil).GetByItem), ctx, userId, itemId, page)
+}
+
+// GetByList mocks base method.
+func (m *MockActivity) GetByList(ctx context.Context, userId, listId int, page todo.Page) ([]todo.Activity, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByList", ctx, userId, listId, page)
+	ret0, _ := ret[0].([]todo.Activity)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetByList indicates an expected call of GetByList.
+func (mr *MockActivityMockRecorder) GetByList(ctx, userId, listId, page interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByList", reflect.TypeOf((*MockActivity)(nil).GetByList), ctx, userId, listId, page)
+}
+
+// MockListMember is a mock of ListMember interface.
+type MockListMember struct {
+	ctrl     *gomock.Controller
+	recorder *MockListMemberMockRecorder
+}
+
+// MockListMemberMockRecorder is the mock recorder for MockListMember.
+type MockListMemberMockRecorder struct {
+	mock *MockListMember
+}
+
+// NewMockListMember creates a new mock instance.
+func NewMockListMember(ctrl *gomock.Controller) *MockListMember {
+	mock := &MockListMember{ctrl: ctrl}
+	mock.recorder = &MockListMemberMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockListMember) EXPECT() *MockListMemberMockRecorder {
+	return m.recorder
+}
+
+// Add mocks base method.
+func (m *MockListMember) Add(ctx context.Context, userId, listId int, input todo.AddMemberInput) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Add", ctx, userId, listId, input)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Add indicates an expected call of Add.
+func (mr *MockListMemberMockRecorder) Add(ctx, userId, listId, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Add", reflect.TypeOf((*MockListMember)(nil).Add), ctx, userId, listId, input)
+}
+
+// Delete mocks base method.
+func (m *MockListMember) Delete(ctx context.Context, userId, listId, memberId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, userId, listId, memberId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockListMemberMockRecorder) Delete(ctx, userId, listId, memberId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockListMember)(nil).Delete), ctx, userId, listId, memberId)
+}
+
+// GetAll mocks base method.
+func (m *MockListMember) GetAll(ctx context.Context, userId, listId int) ([]todo.ListMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", ctx, userId, listId)
+	ret0, _ := ret[0].([]todo.ListMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockListMemberMockRecorder) GetAll(ctx, userId, listId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockListMember)(nil).GetAll), ctx, userId, listId)
+}
+
+// TransferOwnership mocks base method.
+func (m *MockListMember) TransferOwnership(ctx context.Context, userId, listId, newOwnerId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TransferOwnership", ctx, userId, listId, newOwnerId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// TransferOwnership indicates an expected call of TransferOwnership.
+func (mr *MockListMemberMockRecorder) TransferOwnership(ctx, userId, listId, newOwnerId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TransferOwnership", reflect.TypeOf((*MockListMember)(nil).TransferOwnership), ctx, userId, listId, newOwnerId)
+}
+
+// UpdateRole mocks base method.
+func (m *MockListMember) UpdateRole(ctx context.Context, userId, listId, memberId int, input todo.UpdateMemberInput) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateRole", ctx, userId, listId, memberId, input)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateRole indicates an expected call of UpdateRole.
+func (mr *MockListMemberMockRecorder) UpdateRole(ctx, userId, listId, memberId, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateRole", reflect.TypeOf((*MockListMember)(nil).UpdateRole), ctx, userId, listId, memberId, input)
+}
@@ -1,9 +1,12 @@
 package repository
 
 import (
+	"database/sql"
+	"errors"
 	"fmt"
-	_ "github.com/lib/pq" 
+
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 )
 
 
@@ -13,6 +16,17 @@ const (
 	usersListsTable = "users_lists"
 	todoItemsTable = "todo_items"
 	listsItemsTable = "lists_items"
+	refreshTokensTable = "refresh_tokens"
+	revokedTokensTable = "revoked_tokens"
+	itemSeriesTable = "item_series"
+	subtasksTable = "item_subtasks"
+	labelsTable = "labels"
+	itemsLabelsTable = "items_labels"
+	activityTable = "activity"
+	commentsTable = "item_comments"
+	attachmentsTable = "item_attachments"
+	calendarTokensTable = "calendar_tokens"
+	caldavObjectsTable = "caldav_objects"
 )
 
 
@@ -40,4 +54,30 @@ func NewPostgresDB(cfg Config) (*sqlx.DB, error){
 	}
 
 	return  db, nil
-}
\ No newline at end of file
+}
+
+// uniqueViolation is the SQLSTATE Postgres reports for a broken unique constraint.
+const uniqueViolation = "23505"
+
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == uniqueViolation
+}
+
+// notUnique replaces a unique violation with the domain error of the duplicate.
+func notUnique(err, conflictErr error) error {
+	if isUniqueViolation(err) {
+		return conflictErr
+	}
+
+	return err
+}
+
+// notFound replaces sql.ErrNoRows with the domain error of the missing entity.
+func notFound(err, notFoundErr error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return notFoundErr
+	}
+
+	return err
+}
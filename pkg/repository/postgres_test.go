@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+)
+
+func newMockDB(t *testing.T) (*sqlx.DB, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create sqlmock: %s", err.Error())
+	}
+	t.Cleanup(func() { db.Close() })
+
+	return sqlx.NewDb(db, "postgres"), mock
+}
+
+// expectListRole expects requireListRole to find the user in the list with role.
+func expectListRole(mock sqlmock.Sqlmock, userId, listId int, role string) {
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT ul.role FROM users_lists ul")).
+		WithArgs(userId, listId).
+		WillReturnRows(sqlmock.NewRows([]string{"role"}).AddRow(role))
+}
+
+// expectItemRole expects requireItemRole to find the item in the list and the
+// user in it with role.
+func expectItemRole(mock sqlmock.Sqlmock, userId, itemId, listId int, role string) {
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT li.list_id FROM lists_items li")).
+		WithArgs(itemId, userId).
+		WillReturnRows(sqlmock.NewRows([]string{"list_id"}).AddRow(listId))
+	expectListRole(mock, userId, listId, role)
+}
+
+func expectItemVersion(mock sqlmock.Sqlmock, itemId int) {
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE todo_items SET version = version + 1 WHERE id = $1")).
+		WithArgs(itemId).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+}
+
+func expectListVersion(mock sqlmock.Sqlmock, listId int) {
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE todo_lists SET version = version + 1 WHERE id = $1")).
+		WithArgs(listId).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+}
@@ -0,0 +1,45 @@
+package repository
+
+import "strings"
+
+// Item positions are strings ordered byte by byte, the position column uses
+// the "C" collation for that. A new position fits between any two others, so
+// moving an item only ever updates the item itself.
+const rankDigits = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// rankBetween returns a position after a and before b. Empty a means the
+// start of the list, empty b its end. It never ends with the lowest digit,
+// so there is always room for another position before the one it returns.
+func rankBetween(a, b string) string {
+	var rank strings.Builder
+	upper := b != ""
+
+	for n := 0; ; n++ {
+		lo := 0
+		if n < len(a) {
+			lo = strings.IndexByte(rankDigits, a[n])
+		}
+
+		hi := len(rankDigits)
+		if upper && n < len(b) {
+			hi = strings.IndexByte(rankDigits, b[n])
+		}
+
+		if hi < lo {
+			// b is not after a, ignore it rather than loop forever
+			upper, hi = false, len(rankDigits)
+		}
+
+		if hi-lo > 1 {
+			rank.WriteByte(rankDigits[(lo+hi)/2])
+			return rank.String()
+		}
+
+		// a and b share this digit or it is the only one between them, the
+		// position goes after a from here on
+		if hi-lo == 1 {
+			upper = false
+		}
+		rank.WriteByte(rankDigits[lo])
+	}
+}
@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRankBetween(t *testing.T) {
+	testTable := []struct {
+		name string
+		a, b string
+	}{
+		{name: "empty list"},
+		{name: "end of list", a: "V"},
+		{name: "start of list", b: "V"},
+		{name: "before the first possible", b: "1"},
+		{name: "between", a: "V", b: "k"},
+		{name: "adjacent digits", a: "V", b: "W"},
+		{name: "shared prefix", a: "Vz", b: "W"},
+		{name: "prefix of the other", a: "V", b: "V1"},
+		{name: "migrated positions", a: "0000000f", b: "00000010"},
+		{name: "after the last digit", a: "zzz"},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			rank := rankBetween(testCase.a, testCase.b)
+
+			assert.Greater(t, rank, testCase.a)
+			if testCase.b != "" {
+				assert.Less(t, rank, testCase.b)
+			}
+			assert.NotEqual(t, byte('0'), rank[len(rank)-1])
+		})
+	}
+}
+
+func TestRankBetween_repeated(t *testing.T) {
+	// dropping items to the same spot again and again keeps the order
+	a, b := "V", "W"
+	for i := 0; i < 200; i++ {
+		rank := rankBetween(a, b)
+		assert.Greater(t, rank, a)
+		assert.Less(t, rank, b)
+		if i%2 == 0 {
+			b = rank
+		} else {
+			a = rank
+		}
+	}
+}
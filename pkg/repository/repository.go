@@ -1,36 +1,141 @@
 package repository
 
 import (
+	"context"
+	"time"
+
 	"github.com/jmoiron/sqlx"
 	"github.com/lypolix/todo-app"
 )
 
+//go:generate mockgen -source=repository.go -destination=mocks/mock.go
+
 
 type Authorization interface{
-	CreateUser(user todo.User) (int, error)
-	GetUser(username, password string) (todo.User, error)
+	CreateUser(ctx context.Context, user todo.User) (int, error)
+	GetUser(ctx context.Context, username string) (todo.User, error)
+	UpdatePasswordHash(ctx context.Context, userId int, passwordHash string) error
+	CreateRefreshToken(ctx context.Context, token todo.RefreshToken) error
+	GetRefreshToken(ctx context.Context, tokenHash string) (todo.RefreshToken, error)
+	RotateRefreshToken(ctx context.Context, tokenId int, next todo.RefreshToken) error
+	RevokeRefreshToken(ctx context.Context, userId int, tokenHash string) error
+	RevokeAccessToken(ctx context.Context, jti string, expiresAt time.Time) error
+	RevokeAllTokens(ctx context.Context, userId int) error
+	IsTokenRevoked(ctx context.Context, userId int, jti string, issuedAt time.Time) (bool, error)
 }
 
 type TodoList interface{
-	Create(userId int, list todo.TodoList) (int, error)
-	GetAll(userId int) ([]todo.TodoList, error)
-	GetById(userId, listId int) (todo.TodoList, error)
-	Delete(userId, listId int) error 
-	Update(userId, listId int, input todo.UpdateListInput) error
+	Create(ctx context.Context, userId int, list todo.TodoList) (int, error)
+	GetAll(ctx context.Context, userId int) ([]todo.TodoList, error)
+	GetById(ctx context.Context, userId, listId int) (todo.TodoList, error)
+	Delete(ctx context.Context, userId, listId int, version *int) error 
+	Update(ctx context.Context, userId, listId int, input todo.UpdateListInput) error
+	Import(ctx context.Context, userId int, list todo.TodoList, items []todo.TodoItem, dryRun bool) (int, error)
 }
 
 type TodoItem interface {
-	Create(userId, listId int, item todo.TodoItem) (int, error)
-	GetAll(userId, listId int)([]todo.TodoItem, error)
-	GetById(userId, itemId int) (todo.TodoItem, error) 
-	Delete(userId, itemId int) error
-	Update(userId, itemId int, input todo.UpdateItemInput) error 
+	Create(ctx context.Context, userId, listId int, item todo.TodoItem) (int, error)
+	CreateAll(ctx context.Context, userId, listId int, items []todo.TodoItem) ([]int, error)
+	GetAll(ctx context.Context, userId, listId int, filter todo.ItemFilter) ([]todo.TodoItem, string, error)
+	Find(ctx context.Context, userId int, filter todo.ItemFilter) ([]todo.TodoItem, string, error)
+	Move(ctx context.Context, userId, itemId int, input todo.MoveItemInput) error
+	GetAgenda(ctx context.Context, userId int, dueBefore time.Time, undatedPriority todo.Priority) ([]todo.TodoItem, error)
+	GetById(ctx context.Context, userId, itemId int) (todo.TodoItem, error) 
+	Delete(ctx context.Context, userId, itemId int, version *int) error
+	Update(ctx context.Context, userId, itemId int, input todo.UpdateItemInput) error 
+	GetDueBetween(ctx context.Context, from, to time.Time) ([]todo.UserItem, error)
+	GetSeries(ctx context.Context, userId, seriesId int) (todo.ItemSeries, error)
+	CreateOccurrence(ctx context.Context, userId, itemId int, recurrenceId time.Time) (int, error)
+}
+
+type Subtask interface {
+	Create(ctx context.Context, userId, itemId int, subtask todo.Subtask) (int, error)
+	GetAll(ctx context.Context, userId, itemId int) ([]todo.Subtask, error)
+	Update(ctx context.Context, userId, itemId, subtaskId int, input todo.UpdateSubtaskInput) error
+	Delete(ctx context.Context, userId, itemId, subtaskId int) error
+}
+
+type Comment interface {
+	Create(ctx context.Context, userId, itemId int, comment todo.Comment) (int, error)
+	GetAll(ctx context.Context, userId, itemId int) ([]todo.Comment, error)
+	GetById(ctx context.Context, userId, itemId, commentId int) (todo.Comment, error)
+	Update(ctx context.Context, userId, itemId, commentId int, input todo.UpdateCommentInput) error
+	Delete(ctx context.Context, userId, itemId, commentId int) error
+	GetItemUsers(ctx context.Context, itemId int) ([]int, error)
+}
+
+type Attachment interface {
+	CheckUpload(ctx context.Context, userId, itemId int, size, quota int64) error
+	Create(ctx context.Context, userId, itemId int, attachment todo.Attachment, quota int64) (int, error)
+	GetAll(ctx context.Context, userId, itemId int) ([]todo.Attachment, error)
+	GetById(ctx context.Context, userId, itemId, attachmentId int) (todo.Attachment, error)
+	Delete(ctx context.Context, userId, itemId, attachmentId int) (todo.Attachment, error)
+	GetDetached(ctx context.Context) ([]string, error)
+	DeleteDetached(ctx context.Context, storageKey string) error
+}
+
+type Search interface {
+	Search(ctx context.Context, userId int, tsquery string, limit int) ([]todo.SearchResult, error)
+}
+
+type Calendar interface {
+	SetToken(ctx context.Context, userId int, tokenHash string) error
+	DeleteToken(ctx context.Context, userId int) error
+	GetUserId(ctx context.Context, tokenHash string) (int, error)
+	GetItems(ctx context.Context, userId int) ([]todo.TodoItem, error)
+}
+
+type CalDAV interface {
+	GetCalendars(ctx context.Context, userId int) ([]todo.Calendar, error)
+	GetCalendar(ctx context.Context, userId, listId int) (todo.Calendar, error)
+	GetObjects(ctx context.Context, userId, listId int) ([]todo.CalendarObject, error)
+	GetObject(ctx context.Context, userId, listId int, name string) (todo.CalendarObject, error)
+	CreateObject(ctx context.Context, userId, listId int, object todo.CalendarObject) (int, error)
+}
+
+type Label interface {
+	Create(ctx context.Context, userId int, label todo.Label) (int, error)
+	GetAll(ctx context.Context, userId int) ([]todo.Label, error)
+	Update(ctx context.Context, userId, labelId int, input todo.UpdateLabelInput) error
+	Delete(ctx context.Context, userId, labelId int) error
+	Attach(ctx context.Context, userId, itemId, labelId int) error
+	Detach(ctx context.Context, userId, itemId, labelId int) error
+}
+
+type Trash interface {
+	GetAll(ctx context.Context, userId int) (todo.Trash, error)
+	RestoreList(ctx context.Context, userId, listId int) error
+	RestoreItem(ctx context.Context, userId, itemId int) error
+	Purge(ctx context.Context, before time.Time) error
+}
+
+type Activity interface {
+	GetByList(ctx context.Context, userId, listId int, page todo.Page) ([]todo.Activity, string, error)
+	GetByItem(ctx context.Context, userId, itemId int, page todo.Page) ([]todo.Activity, string, error)
+}
+
+type ListMember interface {
+	Add(ctx context.Context, userId, listId int, input todo.AddMemberInput) error
+	GetAll(ctx context.Context, userId, listId int) ([]todo.ListMember, error)
+	UpdateRole(ctx context.Context, userId, listId, memberId int, input todo.UpdateMemberInput) error
+	Delete(ctx context.Context, userId, listId, memberId int) error
+	TransferOwnership(ctx context.Context, userId, listId, newOwnerId int) error
 }
 
 type Repository struct {
 	Authorization
 	TodoList
 	TodoItem
+	ListMember
+	Subtask
+	Comment
+	Attachment
+	Label
+	Trash
+	Activity
+	Search
+	Calendar
+	CalDAV
 }
 
 func NewRepository(db *sqlx.DB) *Repository {
@@ -38,6 +143,16 @@ func NewRepository(db *sqlx.DB) *Repository {
 		Authorization: NewAuthPostgres(db),
 		TodoList: NewTodoListPostgres(db),
 		TodoItem: NewTodoItemPostgres(db),
+		ListMember: NewListMemberPostgres(db),
+		Subtask: NewSubtaskPostgres(db),
+		Comment: NewCommentPostgres(db),
+		Attachment: NewAttachmentPostgres(db),
+		Label: NewLabelPostgres(db),
+		Trash: NewTrashPostgres(db),
+		Activity: NewActivityPostgres(db),
+		Search: NewSearchPostgres(db),
+		Calendar: NewCalendarPostgres(db),
+		CalDAV: NewCalDAVPostgres(db),
 	}
 }
 
@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lypolix/todo-app"
+)
+
+// ts_headline wraps matches in these private use characters, which cannot
+// come from HTML escaping, and highlightSnippet turns them into <mark> tags.
+const (
+	highlightStart = "\ue000"
+	highlightStop  = "\ue001"
+)
+
+var headlineOptions = fmt.Sprintf(`StartSel=%s, StopSel=%s, MaxWords=25, MinWords=10, MaxFragments=2, FragmentDelimiter=" … "`,
+	highlightStart, highlightStop)
+
+type SearchPostgres struct {
+	db *sqlx.DB
+}
+
+func NewSearchPostgres(db *sqlx.DB) *SearchPostgres {
+	return &SearchPostgres{db: db}
+}
+
+// Search ranks the lists, items and comments of the user's lists that match
+// tsquery and returns the best limit of them. Snippets are only built for
+// the returned rows, ts_headline is expensive.
+func (r *SearchPostgres) Search(ctx context.Context, userId int, tsquery string, limit int) ([]todo.SearchResult, error) {
+	results := make([]todo.SearchResult, 0)
+	query := fmt.Sprintf(`WITH q AS (SELECT to_tsquery('simple', $2) AS query),
+							lists AS (SELECT ul.list_id FROM %s ul INNER JOIN %s tl on tl.id = ul.list_id
+								WHERE ul.user_id = $1 AND tl.deleted_at IS NULL)
+						SELECT hits.type, hits.id, hits.list_id, hits.item_id, hits.title, hits.rank,
+							ts_headline('simple', hits.content, q.query, $4) AS snippet
+						FROM (
+							SELECT '%s' AS type, tl.id, tl.id AS list_id, NULL::int AS item_id, tl.title,
+								concat_ws(' ', tl.title, tl.description) AS content, ts_rank(tl.search, q.query) AS rank
+							FROM %s tl, q WHERE tl.id IN (SELECT list_id FROM lists) AND tl.search @@ q.query
+							UNION ALL
+							SELECT '%s', ti.id, li.list_id, ti.id, ti.title,
+								concat_ws(' ', ti.title, ti.description), ts_rank(ti.search, q.query)
+							FROM %s ti INNER JOIN %s li on li.item_id = ti.id, q
+							WHERE li.list_id IN (SELECT list_id FROM lists) AND ti.deleted_at IS NULL AND ti.search @@ q.query
+							UNION ALL
+							SELECT '%s', c.id, li.list_id, ti.id, ti.title, c.body, ts_rank(c.search, q.query)
+							FROM %s c INNER JOIN %s ti on ti.id = c.item_id INNER JOIN %s li on li.item_id = ti.id, q
+							WHERE li.list_id IN (SELECT list_id FROM lists) AND ti.deleted_at IS NULL AND c.search @@ q.query
+							ORDER BY rank DESC, type, id LIMIT $3
+						) hits, q ORDER BY hits.rank DESC, hits.type, hits.id`,
+		usersListsTable, todoListsTable,
+		todo.SearchList, todoListsTable,
+		todo.SearchItem, todoItemsTable, listsItemsTable,
+		todo.SearchComment, commentsTable, todoItemsTable, listsItemsTable)
+	if err := r.db.SelectContext(ctx, &results, query, userId, tsquery, limit, headlineOptions); err != nil {
+		return nil, err
+	}
+
+	for i := range results {
+		results[i].Snippet = highlightSnippet(results[i].Snippet)
+	}
+
+	return results, nil
+}
+
+// highlightSnippet escapes the snippet for HTML and marks the matches.
+func highlightSnippet(snippet string) string {
+	snippet = html.EscapeString(snippet)
+	snippet = strings.ReplaceAll(snippet, highlightStart, "<mark>")
+	return strings.ReplaceAll(snippet, highlightStop, "</mark>")
+}
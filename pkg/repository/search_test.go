@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHighlightSnippet(t *testing.T) {
+	testTable := []struct {
+		name     string
+		snippet  string
+		expected string
+	}{
+		{
+			name:     "match",
+			snippet:  "send the " + highlightStart + "report" + highlightStop + " on friday",
+			expected: "send the <mark>report</mark> on friday",
+		},
+		{
+			name:     "markup in text",
+			snippet:  `<script>alert("x")</script> ` + highlightStart + "fix" + highlightStop,
+			expected: "&lt;script&gt;alert(&#34;x&#34;)&lt;/script&gt; <mark>fix</mark>",
+		},
+		{
+			name:     "no match",
+			snippet:  "buy milk & bread",
+			expected: "buy milk &amp; bread",
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			assert.Equal(t, testCase.expected, highlightSnippet(testCase.snippet))
+		})
+	}
+}
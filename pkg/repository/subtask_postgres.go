@@ -0,0 +1,165 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lypolix/todo-app"
+)
+
+type SubtaskPostgres struct {
+	db *sqlx.DB
+}
+
+func NewSubtaskPostgres(db *sqlx.DB) *SubtaskPostgres {
+	return &SubtaskPostgres{db: db}
+}
+
+// Create appends the subtask to the end of the item's checklist.
+func (r *SubtaskPostgres) Create(ctx context.Context, userId, itemId int, subtask todo.Subtask) (int, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := lockChecklist(ctx, tx, userId, itemId); err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	var id int
+	query := fmt.Sprintf(`INSERT INTO %s (item_id, title, done, position)
+							SELECT $1, $2, $3, COALESCE(MAX(position) + 1, 0) FROM %s WHERE item_id = $1 RETURNING id`,
+		subtasksTable, subtasksTable)
+	if err := tx.QueryRowContext(ctx, query, itemId, subtask.Title, subtask.Done).Scan(&id); err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	return id, tx.Commit()
+}
+
+func (r *SubtaskPostgres) GetAll(ctx context.Context, userId, itemId int) ([]todo.Subtask, error) {
+	if err := requireItemRole(ctx, r.db, userId, itemId, todo.RoleOwner, todo.RoleEditor, todo.RoleViewer); err != nil {
+		return nil, err
+	}
+
+	subtasks := make([]todo.Subtask, 0)
+	query := fmt.Sprintf("SELECT id, item_id, title, done, position FROM %s WHERE item_id = $1 ORDER BY position, id", subtasksTable)
+	if err := r.db.SelectContext(ctx, &subtasks, query, itemId); err != nil {
+		return nil, err
+	}
+
+	return subtasks, nil
+}
+
+// Update changes the subtask and, when it gets a new position, shifts the
+// subtasks in between. Positions past the end move the subtask to the end.
+func (r *SubtaskPostgres) Update(ctx context.Context, userId, itemId, subtaskId int, input todo.UpdateSubtaskInput) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if err := lockChecklist(ctx, tx, userId, itemId); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	var current struct {
+		Position int `db:"position"`
+		Count    int `db:"count"`
+	}
+	positionQuery := fmt.Sprintf(`SELECT st.position, (SELECT count(*) FROM %s WHERE item_id = st.item_id) AS count
+							FROM %s st WHERE st.id = $1 AND st.item_id = $2`, subtasksTable, subtasksTable)
+	if err := tx.GetContext(ctx, &current, positionQuery, subtaskId, itemId); err != nil {
+		tx.Rollback()
+		return notFound(err, todo.ErrSubtaskNotFound)
+	}
+
+	setValues := make([]string, 0)
+	args := make([]interface{}, 0)
+	argId := 1
+
+	if input.Title != nil {
+		setValues = append(setValues, fmt.Sprintf("title=$%d", argId))
+		args = append(args, *input.Title)
+		argId++
+	}
+
+	if input.Done != nil {
+		setValues = append(setValues, fmt.Sprintf("done=$%d", argId))
+		args = append(args, *input.Done)
+		argId++
+	}
+
+	if input.Position != nil {
+		position := *input.Position
+		if position > current.Count-1 {
+			position = current.Count - 1
+		}
+
+		shiftQuery := fmt.Sprintf("UPDATE %s SET position = position - 1 WHERE item_id = $1 AND position > $2 AND position <= $3", subtasksTable)
+		if position < current.Position {
+			shiftQuery = fmt.Sprintf("UPDATE %s SET position = position + 1 WHERE item_id = $1 AND position >= $3 AND position < $2", subtasksTable)
+		}
+		if _, err := tx.ExecContext(ctx, shiftQuery, itemId, current.Position, position); err != nil {
+			tx.Rollback()
+			return err
+		}
+
+		setValues = append(setValues, fmt.Sprintf("position=$%d", argId))
+		args = append(args, position)
+		argId++
+	}
+
+	query := fmt.Sprintf("UPDATE %s SET %s WHERE id = $%d", subtasksTable, strings.Join(setValues, ", "), argId)
+	args = append(args, subtaskId)
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *SubtaskPostgres) Delete(ctx context.Context, userId, itemId, subtaskId int) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if err := lockChecklist(ctx, tx, userId, itemId); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	var position int
+	deleteQuery := fmt.Sprintf("DELETE FROM %s WHERE id = $1 AND item_id = $2 RETURNING position", subtasksTable)
+	if err := tx.GetContext(ctx, &position, deleteQuery, subtaskId, itemId); err != nil {
+		tx.Rollback()
+		return notFound(err, todo.ErrSubtaskNotFound)
+	}
+
+	shiftQuery := fmt.Sprintf("UPDATE %s SET position = position - 1 WHERE item_id = $1 AND position > $2", subtasksTable)
+	if _, err := tx.ExecContext(ctx, shiftQuery, itemId, position); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// lockChecklist checks that the user may edit the item and locks it, so
+// concurrent changes of its checklist keep the positions consecutive. Every
+// change of the checklist changes the item's progress, so the item gets a
+// new version too.
+func lockChecklist(ctx context.Context, tx *sqlx.Tx, userId, itemId int) error {
+	if err := requireItemRole(ctx, tx, userId, itemId, todo.RoleOwner, todo.RoleEditor); err != nil {
+		return err
+	}
+
+	return bumpItemVersion(ctx, tx, itemId)
+}
@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lypolix/todo-app"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSubtaskPostgres_version(t *testing.T) {
+	done := true
+
+	testTable := []struct {
+		name         string
+		mockBehavior func(mock sqlmock.Sqlmock)
+		change       func(r *SubtaskPostgres) error
+		expectedErr  error
+	}{
+		{
+			name: "Create",
+			mockBehavior: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				expectItemRole(mock, 1, 5, 3, todo.RoleEditor)
+				expectItemVersion(mock, 5)
+				mock.ExpectQuery(regexp.QuoteMeta("INSERT INTO item_subtasks")).
+					WithArgs(5, "Eggs", false).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+				mock.ExpectCommit()
+			},
+			change: func(r *SubtaskPostgres) error {
+				_, err := r.Create(context.Background(), 1, 5, todo.Subtask{Title: "Eggs"})
+				return err
+			},
+		},
+		{
+			name: "Update",
+			mockBehavior: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				expectItemRole(mock, 1, 5, 3, todo.RoleOwner)
+				expectItemVersion(mock, 5)
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT st.position")).
+					WithArgs(7, 5).
+					WillReturnRows(sqlmock.NewRows([]string{"position", "count"}).AddRow(0, 2))
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE item_subtasks SET done=$1 WHERE id = $2")).
+					WithArgs(true, 7).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			change: func(r *SubtaskPostgres) error {
+				return r.Update(context.Background(), 1, 5, 7, todo.UpdateSubtaskInput{Done: &done})
+			},
+		},
+		{
+			name: "Delete",
+			mockBehavior: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				expectItemRole(mock, 1, 5, 3, todo.RoleOwner)
+				expectItemVersion(mock, 5)
+				mock.ExpectQuery(regexp.QuoteMeta("DELETE FROM item_subtasks")).
+					WithArgs(7, 5).
+					WillReturnRows(sqlmock.NewRows([]string{"position"}).AddRow(0))
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE item_subtasks SET position = position - 1")).
+					WithArgs(5, 0).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			change: func(r *SubtaskPostgres) error {
+				return r.Delete(context.Background(), 1, 5, 7)
+			},
+		},
+		{
+			name: "Viewer",
+			mockBehavior: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				expectItemRole(mock, 1, 5, 3, todo.RoleViewer)
+				mock.ExpectRollback()
+			},
+			change: func(r *SubtaskPostgres) error {
+				return r.Delete(context.Background(), 1, 5, 7)
+			},
+			expectedErr: todo.ErrListForbidden,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			db, mock := newMockDB(t)
+			testCase.mockBehavior(mock)
+
+			err := testCase.change(NewSubtaskPostgres(db))
+
+			assert.ErrorIs(t, err, testCase.expectedErr)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
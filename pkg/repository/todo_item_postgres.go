@@ -1,10 +1,15 @@
 package repository
 
 import (
+	"context"
 	"fmt"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	"github.com/lypolix/todo-app"
+	"math"
+	"strconv"
 	"strings"
+	"time"
 )
 
 type TodoItemPostgres struct {
@@ -15,66 +20,414 @@ func NewTodoItemPostgres(db *sqlx.DB) *TodoItemPostgres {
 	return &TodoItemPostgres{db: db}
 }
 
-func (r *TodoItemPostgres) Create(userId, listId int, item todo.TodoItem) (int, error) {
-	tx, err := r.db.Begin()
+func (r *TodoItemPostgres) Create(ctx context.Context, userId, listId int, item todo.TodoItem) (int, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
 	if err != nil {
 		return 0, err
 	}
 
-	var itemId int
-	createItemQuery := fmt.Sprintf("INSERT INTO %s (title, description) values ($1, $2) RETURNING id", todoItemsTable)
+	if err := requireListRole(ctx, tx, userId, listId, todo.RoleOwner, todo.RoleEditor); err != nil {
+		tx.Rollback()
+		return 0, err
+	}
 
-	row := tx.QueryRow(createItemQuery, item.Title, item.Description)
-	err = row.Scan(&itemId)
+	itemId, err := createItem(ctx, tx, userId, listId, item, false)
 	if err != nil {
 		tx.Rollback()
-		return  0, err
+		return 0, err
 	}
 
-	createListItemsQuery := fmt.Sprintf("INSERT INTO %s (list_id, item_id) values ($1, $2)", listsItemsTable)
-	_, err = tx.Exec(createListItemsQuery, listId, itemId)
+	return itemId, tx.Commit()
+}
+
+// CreateAll appends the items to the list in one transaction, either all of
+// them are created or none. Unlike Create it keeps whether the imported items
+// are done.
+func (r *TodoItemPostgres) CreateAll(ctx context.Context, userId, listId int, items []todo.TodoItem) ([]int, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
 	if err != nil {
+		return nil, err
+	}
+
+	if err := requireListRole(ctx, tx, userId, listId, todo.RoleOwner, todo.RoleEditor); err != nil {
 		tx.Rollback()
+		return nil, err
+	}
+
+	ids := make([]int, len(items))
+	for i, item := range items {
+		if ids[i], err = createItem(ctx, tx, userId, listId, item, item.Done); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+
+	return ids, tx.Commit()
+}
+
+// createItem appends the item to the list within tx, the caller checks that
+// the user may do so. Only imports create items that are already done, so
+// done is passed explicitly instead of taken from item.
+func createItem(ctx context.Context, tx *sqlx.Tx, userId, listId int, item todo.TodoItem, done bool) (int, error) {
+	// a new item only belongs to the series its own rule starts
+	item.SeriesId, item.RecurrenceId = nil, nil
+	item.Done = done
+	if item.RRule != "" {
+		seriesId, err := createSeries(ctx, tx, todo.ItemSeries{
+			RRule: item.RRule,
+			Title: item.Title,
+			Description: item.Description,
+			DtStart: *item.Deadline,
+			ListId: listId,
+		})
+		if err != nil {
+			return 0, err
+		}
+		item.SeriesId = &seriesId
+		item.RecurrenceId = item.Deadline
+	}
+
+	var itemId int
+	createItemQuery := fmt.Sprintf(`INSERT INTO %s (title, description, done, deadline, series_id, recurrence_id, auto_complete, priority)
+							values ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id`, todoItemsTable)
+
+	row := tx.QueryRowContext(ctx, createItemQuery, item.Title, item.Description, item.Done, item.Deadline, item.SeriesId, item.RecurrenceId, item.AutoComplete, item.Priority)
+	if err := row.Scan(&itemId); err != nil {
 		return 0, err
 	}
 
-	return itemId, tx.Commit()
+	position, err := appendPosition(ctx, tx, listId)
+	if err != nil {
+		return 0, err
+	}
+
+	createListItemsQuery := fmt.Sprintf("INSERT INTO %s (list_id, item_id, position) values ($1, $2, $3)", listsItemsTable)
+	if _, err := tx.ExecContext(ctx, createListItemsQuery, listId, itemId, position); err != nil {
+		return 0, err
+	}
+
+	activity := todo.Activity{ActorId: &userId, ListId: listId, ItemId: &itemId, Action: todo.ActionCreate, Changes: itemChanges(todo.TodoItem{}, item)}
+	if err := logActivity(ctx, tx, activity); err != nil {
+		return 0, err
+	}
+
+	return itemId, nil
+}
+
+// bumpItemVersion marks a change of the item that is stored outside of its
+// row, like its position, checklist or labels.
+func bumpItemVersion(ctx context.Context, tx *sqlx.Tx, itemId int) error {
+	query := fmt.Sprintf("UPDATE %s SET version = version + 1 WHERE id = $1", todoItemsTable)
+	_, err := tx.ExecContext(ctx, query, itemId)
+
+	return err
 }
 
-func (r *TodoItemPostgres) GetAll(userId, listId int) ([]todo.TodoItem, error) {
+// itemChanges lists the fields that differ between two states of an item.
+func itemChanges(before, after todo.TodoItem) todo.Changes {
+	changes := todo.Changes{}
+	addChange(changes, "title", before.Title, after.Title)
+	addChange(changes, "description", before.Description, after.Description)
+	addChange(changes, "done", before.Done, after.Done)
+	addChange(changes, "deadline", before.Deadline, after.Deadline)
+	addChange(changes, "priority", before.Priority, after.Priority)
+	addChange(changes, "rrule", before.RRule, after.RRule)
+	addChange(changes, "auto_complete", before.AutoComplete, after.AutoComplete)
+
+	return changes
+}
+
+// itemColumns are the todo_items columns every item query returns, together
+// with the rule of the item's series and the progress of its subtasks.
+const itemColumns = `ti.id, ti.title, ti.description, ti.done, ti.deadline, ti.created_at, ti.series_id, ti.recurrence_id, ti.auto_complete, ti.priority, ti.version,
+	COALESCE((SELECT s.rrule FROM ` + itemSeriesTable + ` s WHERE s.id = ti.series_id), '') AS rrule,
+	(SELECT 100 * count(*) FILTER (WHERE st.done) / NULLIF(count(*), 0) FROM ` + subtasksTable + ` st WHERE st.item_id = ti.id) AS progress`
+
+// liveItem is the condition for items of the li list that are neither in
+// the trash nor in a trashed list.
+const liveItem = `ti.deleted_at IS NULL AND li.list_id IN (SELECT id FROM ` + todoListsTable + ` WHERE deleted_at IS NULL)`
+
+type itemSort struct {
+	// expr is what items are ordered by, cast is the type the cursor value is cast to
+	expr  string
+	cast  string
+	value func(item todo.TodoItem) string
+	// parse checks the value of a cursor, which clients can tamper with, so a
+	// malformed one never reaches the query
+	parse func(value string) (interface{}, error)
+}
+
+var itemSorts = map[string]itemSort{
+	todo.SortByPosition: {
+		expr: "li.position",
+		cast: "text",
+		value: func(item todo.TodoItem) string {
+			return item.Position
+		},
+		parse: parseTextCursor,
+	},
+	todo.SortByDeadline: {
+		expr: "COALESCE(ti.deadline, 'infinity')",
+		cast: "timestamptz",
+		value: func(item todo.TodoItem) string {
+			if item.Deadline == nil {
+				return "infinity"
+			}
+			return item.Deadline.Format(time.RFC3339Nano)
+		},
+		parse: func(value string) (interface{}, error) {
+			if value == "infinity" {
+				return value, nil
+			}
+			return parseTimeCursor(value)
+		},
+	},
+	todo.SortByCreatedAt: {
+		expr: "ti.created_at",
+		cast: "timestamptz",
+		value: func(item todo.TodoItem) string {
+			return item.CreatedAt.Format(time.RFC3339Nano)
+		},
+		parse: parseTimeCursor,
+	},
+	todo.SortByPriority: {
+		expr: "ti.priority",
+		cast: "smallint",
+		value: func(item todo.TodoItem) string {
+			return strconv.Itoa(int(item.Priority))
+		},
+		parse: func(value string) (interface{}, error) {
+			// priority is a smallint
+			priority, err := strconv.ParseInt(value, 10, 16)
+			return int(priority), err
+		},
+	},
+	todo.SortByTitle: {
+		expr: "ti.title",
+		cast: "text",
+		value: func(item todo.TodoItem) string {
+			return item.Title
+		},
+		parse: parseTextCursor,
+	},
+}
+
+func parseTimeCursor(value string) (interface{}, error) {
+	return time.Parse(time.RFC3339Nano, value)
+}
+
+// parseTextCursor rejects NUL, which Postgres does not allow in text.
+func parseTextCursor(value string) (interface{}, error) {
+	if strings.ContainsRune(value, 0) {
+		return nil, todo.ErrInvalidCursor
+	}
+	return value, nil
+}
+
+// GetAll returns one page of the list's items matching filter and the cursor
+// of the next page, which is empty on the last page.
+func (r *TodoItemPostgres) GetAll(ctx context.Context, userId, listId int, filter todo.ItemFilter) ([]todo.TodoItem, string, error) {
+	return r.selectItems(ctx, userId, &listId, filter)
+}
+
+// Find is GetAll across all of the user's lists.
+func (r *TodoItemPostgres) Find(ctx context.Context, userId int, filter todo.ItemFilter) ([]todo.TodoItem, string, error) {
+	return r.selectItems(ctx, userId, nil, filter)
+}
+
+func (r *TodoItemPostgres) selectItems(ctx context.Context, userId int, listId *int, filter todo.ItemFilter) ([]todo.TodoItem, string, error) {
+	sort := itemSorts[filter.Sort]
+	conditions := []string{"ul.user_id = $1", liveItem}
+	args := []interface{}{userId}
+	argId := 2
+
+	if listId != nil {
+		conditions = append(conditions, fmt.Sprintf("li.list_id = $%d", argId))
+		args = append(args, *listId)
+		argId++
+	}
+
+	if filter.Done != nil {
+		conditions = append(conditions, fmt.Sprintf("ti.done = $%d", argId))
+		args = append(args, *filter.Done)
+		argId++
+	}
+
+	if filter.DueBefore != nil {
+		conditions = append(conditions, fmt.Sprintf("ti.deadline < $%d", argId))
+		args = append(args, *filter.DueBefore)
+		argId++
+	}
+
+	if filter.DueAfter != nil {
+		conditions = append(conditions, fmt.Sprintf("ti.deadline >= $%d", argId))
+		args = append(args, *filter.DueAfter)
+		argId++
+	}
+
+	if filter.Query != "" {
+		conditions = append(conditions, fmt.Sprintf("(ti.title ILIKE $%d OR ti.description ILIKE $%d)", argId, argId))
+		args = append(args, "%"+escapeLike(filter.Query)+"%")
+		argId++
+	}
+
+	if len(filter.Tags) > 0 {
+		// labels are personal, so only the user's own labels are matched
+		labelsQuery := fmt.Sprintf(`SELECT count(DISTINCT lower(l.name)) FROM %s il INNER JOIN %s l on l.id = il.label_id
+							WHERE il.item_id = ti.id AND l.user_id = $1 AND lower(l.name) = ANY($%d)`, itemsLabelsTable, labelsTable, argId)
+		if filter.TagMatch == todo.TagMatchAll {
+			conditions = append(conditions, fmt.Sprintf("(%s) = %d", labelsQuery, len(filter.Tags)))
+		} else {
+			conditions = append(conditions, fmt.Sprintf("(%s) > 0", labelsQuery))
+		}
+		args = append(args, pq.Array(filter.Tags))
+		argId++
+	}
+
+	direction, comparison := "ASC", ">"
+	if filter.Order == todo.OrderDesc {
+		direction, comparison = "DESC", "<"
+	}
+
+	if filter.Cursor != "" {
+		after, err := decodeCursor(filter.Cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		if after.Sort != filter.Sort || after.Order != filter.Order || after.Id < 1 || after.Id > math.MaxInt32 {
+			return nil, "", todo.ErrInvalidCursor
+		}
+		value, err := sort.parse(after.Value)
+		if err != nil {
+			return nil, "", todo.ErrInvalidCursor
+		}
+
+		conditions = append(conditions, fmt.Sprintf("(%s, ti.id) %s ($%d::%s, $%d)", sort.expr, comparison, argId, sort.cast, argId+1))
+		args = append(args, value, after.Id)
+		argId += 2
+	}
+
+	// one extra row tells whether there is a next page
+	query := fmt.Sprintf(`SELECT %s, li.list_id, li.position FROM %s ti INNER JOIN %s li on li.item_id = ti.id
+							INNER JOIN %s ul on ul.list_id = li.list_id WHERE %s
+							ORDER BY %s %s, ti.id %s LIMIT $%d`,
+		itemColumns, todoItemsTable, listsItemsTable, usersListsTable, strings.Join(conditions, " AND "),
+		sort.expr, direction, direction, argId)
+	args = append(args, filter.Limit+1)
+
 	var items []todo.TodoItem
-	query := fmt.Sprintf(`SELECT ti.id, ti.title, ti.description, ti.done FROM %s ti INNER JOIN %s li on li.item_id = ti.id
-							INNER JOIN %s ul on ul.list_id = li.list_id WHERE li.list_id = $1 AND ul.user_id = $2`, 
-							todoItemsTable, listsItemsTable, usersListsTable)
-	if err := r.db.Select(&items, query, listId, userId); err != nil {
+	if err := r.db.SelectContext(ctx, &items, query, args...); err != nil {
+		return nil, "", err
+	}
+
+	var next string
+	if len(items) > filter.Limit {
+		items = items[:filter.Limit]
+		last := items[len(items)-1]
+		next = encodeCursor(cursor{Sort: filter.Sort, Order: filter.Order, Value: sort.value(last), Id: last.Id})
+	}
+
+	if err := loadLabels(ctx, r.db, userId, items); err != nil {
+		return nil, "", err
+	}
+
+	return items, next, nil
+}
+
+// GetAgenda returns the open items of all the user's lists that are due before
+// dueBefore or have no deadline and at least undatedPriority. Items are ordered
+// by deadline, undated ones last, and then by priority.
+func (r *TodoItemPostgres) GetAgenda(ctx context.Context, userId int, dueBefore time.Time, undatedPriority todo.Priority) ([]todo.TodoItem, error) {
+	items := make([]todo.TodoItem, 0)
+	query := fmt.Sprintf(`SELECT %s, li.list_id, li.position FROM %s ti INNER JOIN %s li on li.item_id = ti.id
+							INNER JOIN %s ul on ul.list_id = li.list_id
+							WHERE ul.user_id = $1 AND %s AND ti.done = false AND (ti.deadline < $2 OR (ti.deadline IS NULL AND ti.priority >= $3))
+							ORDER BY ti.deadline ASC NULLS LAST, ti.priority DESC, ti.created_at, ti.id`,
+		itemColumns, todoItemsTable, listsItemsTable, usersListsTable, liveItem)
+	if err := r.db.SelectContext(ctx, &items, query, userId, dueBefore, undatedPriority); err != nil {
+		return nil, err
+	}
+
+	if err := loadLabels(ctx, r.db, userId, items); err != nil {
 		return nil, err
 	}
 
 	return items, nil
 }
 
-func (r *TodoItemPostgres) GetById(userId, itemId int) (todo.TodoItem, error) {
+// escapeLike escapes the LIKE wildcards so user input is matched literally.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
+func (r *TodoItemPostgres) GetById(ctx context.Context, userId, itemId int) (todo.TodoItem, error) {
 	var item todo.TodoItem
-	query := fmt.Sprintf(`SELECT ti.id, ti.title, ti.description, ti.done FROM %s ti INNER JOIN %s li on li.item_id = ti.id
-							INNER JOIN %s ul on ul.list_id = li.list_id WHERE ti.id = $1 AND ul.user_id = $2`, 
-		todoItemsTable, listsItemsTable, usersListsTable)
-	if err := r.db.Get(&item, query, itemId, userId); err != nil {
+	query := fmt.Sprintf(`SELECT %s, li.list_id, li.position FROM %s ti INNER JOIN %s li on li.item_id = ti.id
+							INNER JOIN %s ul on ul.list_id = li.list_id WHERE ti.id = $1 AND ul.user_id = $2 AND %s`, 
+		itemColumns, todoItemsTable, listsItemsTable, usersListsTable, liveItem)
+	if err := r.db.GetContext(ctx, &item, query, itemId, userId); err != nil {
+		return item, notFound(err, todo.ErrItemNotFound)
+	}
+
+	items := []todo.TodoItem{item}
+	if err := loadLabels(ctx, r.db, userId, items); err != nil {
 		return item, err
 	}
 
-	return item, nil
+	return items[0], nil
 }
 
-func (r *TodoItemPostgres) Delete(userId, itemId int) error {
-	query := fmt.Sprintf(`DELETE FROM %s ti USING %s li, %s ul 
-							WHERE ti.id = li.item_id AND li.list_id = ul.list_id AND ul.user_id = $1 AND ti.id = $2`,
-							todoItemsTable, listsItemsTable, usersListsTable)
-	_, err := r.db.Exec(query, userId, itemId)
-	return err
+// Delete moves the item to the trash. With a version the item is only
+// deleted if it still has that version.
+func (r *TodoItemPostgres) Delete(ctx context.Context, userId, itemId int, version *int) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if err := requireItemRole(ctx, tx, userId, itemId, todo.RoleOwner); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	var listId int
+	query := fmt.Sprintf(`UPDATE %s ti SET deleted_at = now(), version = ti.version + 1 FROM %s li
+							WHERE ti.id = li.item_id AND ti.id = $1 AND ($2::int IS NULL OR ti.version = $2) RETURNING li.list_id`,
+		todoItemsTable, listsItemsTable)
+	if err := tx.GetContext(ctx, &listId, query, itemId, version); err != nil {
+		tx.Rollback()
+		return notFound(err, todo.ErrPreconditionFailed)
+	}
+
+	if err := logActivity(ctx, tx, todo.Activity{ActorId: &userId, ListId: listId, ItemId: &itemId, Action: todo.ActionDelete}); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
 }
 
-func (r *TodoItemPostgres) Update(userId, itemId int, input todo.UpdateItemInput) error {
-	setValues := make([]string, 0)
+func (r *TodoItemPostgres) Update(ctx context.Context, userId, itemId int, input todo.UpdateItemInput) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if err := requireItemRole(ctx, tx, userId, itemId, todo.RoleOwner, todo.RoleEditor); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	var before todo.TodoItem
+	beforeQuery := fmt.Sprintf("SELECT %s, li.list_id FROM %s ti INNER JOIN %s li on li.item_id = ti.id WHERE ti.id = $1 FOR UPDATE OF ti",
+		itemColumns, todoItemsTable, listsItemsTable)
+	if err := tx.GetContext(ctx, &before, beforeQuery, itemId); err != nil {
+		tx.Rollback()
+		return err
+	}
+	after := before
+
+	setValues := []string{"version=version+1"}
 	args := make([]interface{}, 0)
 	argId := 1
 
@@ -82,27 +435,97 @@ func (r *TodoItemPostgres) Update(userId, itemId int, input todo.UpdateItemInput
 		setValues = append(setValues, fmt.Sprintf("title=$%d", argId))
 		args = append(args, *&input.Title)
 		argId ++
+		after.Title = *input.Title
 	}
 
 	if input.Description != nil {
 		setValues = append(setValues, fmt.Sprintf("description=$%d", argId))
 		args = append(args, *&input.Description)
 		argId++
+		after.Description = *input.Description
 	}
 
 	if input.Done != nil {
 		setValues = append(setValues, fmt.Sprintf("done=$%d", argId))
 		args = append(args, *&input.Done)
 		argId++
+		after.Done = *input.Done
+	}
+
+	if input.Deadline != nil {
+		setValues = append(setValues, fmt.Sprintf("deadline=$%d", argId))
+		args = append(args, input.Deadline)
+		argId++
+		after.Deadline = input.Deadline
+	}
+
+	if input.AutoComplete != nil {
+		setValues = append(setValues, fmt.Sprintf("auto_complete=$%d", argId))
+		args = append(args, *input.AutoComplete)
+		argId++
+		after.AutoComplete = *input.AutoComplete
+	}
+
+	if input.Priority != nil {
+		setValues = append(setValues, fmt.Sprintf("priority=$%d", argId))
+		args = append(args, *input.Priority)
+		argId++
+		after.Priority = *input.Priority
 	}
 
 	setQuery := strings.Join(setValues, ", ")
+	query := fmt.Sprintf("UPDATE %s SET %s WHERE id = $%d AND ($%d::int IS NULL OR version = $%d)", todoItemsTable, setQuery, argId, argId+1, argId+1)
+	args = append(args, itemId, input.Version)
+
+	res, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
 
-	query := fmt.Sprintf(`UPDATE %s ti SET %s FROM %s li, %s ul 
-							WHERE ti.id = li.item_id AND li.list_id = ul.list_id AND ul.user_id = $%d AND ti.id = $%d`, 
-		todoItemsTable, setQuery, listsItemsTable, usersListsTable, argId, argId+1)
-	args = append(args, userId, itemId)
+	if err := checkAffected(res, func() error { return todo.ErrPreconditionFailed }); err != nil {
+		tx.Rollback()
+		return err
+	}
 
-	_, err := r.db.Exec(query, args...)
-	return err
+	if input.RRule != nil || input.Scope == todo.ScopeFuture {
+		if err := updateSeries(ctx, tx, userId, itemId, input); err != nil {
+			tx.Rollback()
+			return notUnique(err, todo.ErrDuplicateOccurrence)
+		}
+	}
+
+	if input.RRule != nil {
+		after.RRule = *input.RRule
+	}
+
+	if changes := itemChanges(before, after); len(changes) > 0 {
+		action := todo.ActionUpdate
+		if after.Done && !before.Done {
+			action = todo.ActionComplete
+		}
+
+		activity := todo.Activity{ActorId: &userId, ListId: before.ListId, ItemId: &itemId, Action: action, Changes: changes}
+		if err := logActivity(ctx, tx, activity); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GetDueBetween returns unfinished items whose deadline falls into (from, to],
+// one row per user that has access to the item's list.
+func (r *TodoItemPostgres) GetDueBetween(ctx context.Context, from, to time.Time) ([]todo.UserItem, error) {
+	var items []todo.UserItem
+	query := fmt.Sprintf(`SELECT ul.user_id, %s FROM %s ti
+							INNER JOIN %s li on li.item_id = ti.id INNER JOIN %s ul on ul.list_id = li.list_id
+							WHERE %s AND ti.done = false AND ti.deadline > $1 AND ti.deadline <= $2 ORDER BY ti.deadline`,
+		itemColumns, todoItemsTable, listsItemsTable, usersListsTable, liveItem)
+	if err := r.db.SelectContext(ctx, &items, query, from, to); err != nil {
+		return nil, err
+	}
+
+	return items, nil
 }
\ No newline at end of file
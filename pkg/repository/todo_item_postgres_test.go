@@ -0,0 +1,317 @@
+package repository
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/base64"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lypolix/todo-app"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTodoItemPostgres_GetAll_due(t *testing.T) {
+	march := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	april := time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)
+
+	testTable := []struct {
+		name          string
+		dueBefore     *time.Time
+		dueAfter      *time.Time
+		expectedWhere string
+		expectedArgs  []driver.Value
+	}{
+		{
+			name:          "Overdue",
+			dueBefore:     &march,
+			expectedWhere: "li.list_id = $2 AND ti.deadline < $3 ORDER BY",
+			expectedArgs:  []driver.Value{1, 3, march, todo.DefaultItemsLimit + 1},
+		},
+		{
+			name:          "Due Later",
+			dueAfter:      &march,
+			expectedWhere: "li.list_id = $2 AND ti.deadline >= $3 ORDER BY",
+			expectedArgs:  []driver.Value{1, 3, march, todo.DefaultItemsLimit + 1},
+		},
+		{
+			name:          "Due In March",
+			dueAfter:      &march,
+			dueBefore:     &april,
+			expectedWhere: "li.list_id = $2 AND ti.deadline < $3 AND ti.deadline >= $4 ORDER BY",
+			expectedArgs:  []driver.Value{1, 3, april, march, todo.DefaultItemsLimit + 1},
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			db, mock := newMockDB(t)
+
+			mock.ExpectQuery(regexp.QuoteMeta(testCase.expectedWhere)).
+				WithArgs(testCase.expectedArgs...).
+				WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+			filter := todo.ItemFilter{DueBefore: testCase.dueBefore, DueAfter: testCase.dueAfter, Sort: todo.SortByDeadline, Order: todo.OrderAsc, Limit: todo.DefaultItemsLimit}
+			_, _, err := NewTodoItemPostgres(db).GetAll(context.Background(), 1, 3, filter)
+
+			assert.NoError(t, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestTodoItemPostgres_GetDueBetween(t *testing.T) {
+	db, mock := newMockDB(t)
+
+	from := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+	to := from.Add(time.Hour)
+
+	// only unfinished items are due, once per user of their list
+	mock.ExpectQuery(regexp.QuoteMeta("ti.done = false AND ti.deadline > $1 AND ti.deadline <= $2 ORDER BY ti.deadline")).
+		WithArgs(from, to).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "id", "title", "deadline"}).
+			AddRow(1, 5, "Milk", from.Add(time.Minute)).
+			AddRow(2, 5, "Milk", from.Add(time.Minute)))
+
+	items, err := NewTodoItemPostgres(db).GetDueBetween(context.Background(), from, to)
+
+	assert.NoError(t, err)
+	if assert.Len(t, items, 2) {
+		assert.Equal(t, 1, items[0].UserId)
+		assert.Equal(t, 2, items[1].UserId)
+		assert.Equal(t, 5, items[1].Id)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTodoItemPostgres_GetAll_pages(t *testing.T) {
+	march := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+	april := time.Date(2025, 4, 1, 9, 0, 0, 0, time.UTC)
+
+	testTable := []struct {
+		name          string
+		sort          string
+		order         string
+		last          []driver.Value
+		expectedOrder string
+		expectedAfter string
+		expectedValue driver.Value
+	}{
+		{
+			name:          "Position",
+			sort:          todo.SortByPosition,
+			order:         todo.OrderAsc,
+			last:          []driver.Value{6, "Bread", nil, march, 1, "b"},
+			expectedOrder: "ORDER BY li.position ASC, ti.id ASC LIMIT $3",
+			expectedAfter: "(li.position, ti.id) > ($3::text, $4)",
+			expectedValue: "b",
+		},
+		{
+			name:          "Deadline Descending",
+			sort:          todo.SortByDeadline,
+			order:         todo.OrderDesc,
+			last:          []driver.Value{6, "Bread", april, march, 1, "b"},
+			expectedOrder: "ORDER BY COALESCE(ti.deadline, 'infinity') DESC, ti.id DESC LIMIT $3",
+			expectedAfter: "(COALESCE(ti.deadline, 'infinity'), ti.id) < ($3::timestamptz, $4)",
+			expectedValue: april,
+		},
+		{
+			// items without deadline come last and page on as infinity
+			name:          "Undated",
+			sort:          todo.SortByDeadline,
+			order:         todo.OrderAsc,
+			last:          []driver.Value{6, "Bread", nil, march, 1, "b"},
+			expectedOrder: "ORDER BY COALESCE(ti.deadline, 'infinity') ASC, ti.id ASC LIMIT $3",
+			expectedAfter: "(COALESCE(ti.deadline, 'infinity'), ti.id) > ($3::timestamptz, $4)",
+			expectedValue: "infinity",
+		},
+		{
+			name:          "Created At",
+			sort:          todo.SortByCreatedAt,
+			order:         todo.OrderAsc,
+			last:          []driver.Value{6, "Bread", nil, march, 1, "b"},
+			expectedOrder: "ORDER BY ti.created_at ASC, ti.id ASC LIMIT $3",
+			expectedAfter: "(ti.created_at, ti.id) > ($3::timestamptz, $4)",
+			expectedValue: march,
+		},
+		{
+			name:          "Priority Descending",
+			sort:          todo.SortByPriority,
+			order:         todo.OrderDesc,
+			last:          []driver.Value{6, "Bread", nil, march, 3, "b"},
+			expectedOrder: "ORDER BY ti.priority DESC, ti.id DESC LIMIT $3",
+			expectedAfter: "(ti.priority, ti.id) < ($3::smallint, $4)",
+			expectedValue: 3,
+		},
+		{
+			name:          "Title",
+			sort:          todo.SortByTitle,
+			order:         todo.OrderAsc,
+			last:          []driver.Value{6, "Bread", nil, march, 1, "b"},
+			expectedOrder: "ORDER BY ti.title ASC, ti.id ASC LIMIT $3",
+			expectedAfter: "(ti.title, ti.id) > ($3::text, $4)",
+			expectedValue: "Bread",
+		},
+	}
+
+	columns := []string{"id", "title", "deadline", "created_at", "priority", "position"}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			db, mock := newMockDB(t)
+			r := NewTodoItemPostgres(db)
+			filter := todo.ItemFilter{Sort: testCase.sort, Order: testCase.order, Limit: 2}
+
+			// one row more than the limit means there is a next page
+			mock.ExpectQuery(regexp.QuoteMeta(testCase.expectedOrder)).
+				WithArgs(1, 3, 3).
+				WillReturnRows(sqlmock.NewRows(columns).
+					AddRow(5, "Apples", nil, march, 1, "a").
+					AddRow(testCase.last...).
+					AddRow(7, "Cheese", nil, april, 1, "c"))
+			mock.ExpectQuery(regexp.QuoteMeta("SELECT il.item_id, l.id, l.name, l.color")).
+				WillReturnRows(sqlmock.NewRows([]string{"item_id", "id", "name", "color"}))
+
+			items, next, err := r.GetAll(context.Background(), 1, 3, filter)
+			assert.NoError(t, err)
+			assert.Len(t, items, 2)
+			if !assert.NotEmpty(t, next) {
+				return
+			}
+
+			// the next page starts after the last item of this one
+			mock.ExpectQuery(regexp.QuoteMeta(testCase.expectedAfter)).
+				WithArgs(1, 3, testCase.expectedValue, 6, 3).
+				WillReturnRows(sqlmock.NewRows(columns))
+
+			filter.Cursor = next
+			items, next, err = r.GetAll(context.Background(), 1, 3, filter)
+			assert.NoError(t, err)
+			assert.Empty(t, items)
+			assert.Empty(t, next)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestTodoItemPostgres_GetAll_invalidCursor(t *testing.T) {
+	testTable := []struct {
+		name   string
+		sort   string
+		cursor string
+	}{
+		{
+			name:   "Not Base64",
+			sort:   todo.SortByPosition,
+			cursor: "not a cursor!",
+		},
+		{
+			name:   "Not JSON",
+			sort:   todo.SortByPosition,
+			cursor: base64.RawURLEncoding.EncodeToString([]byte("abc")),
+		},
+		{
+			name:   "Other Sort",
+			sort:   todo.SortByTitle,
+			cursor: encodeCursor(cursor{Sort: todo.SortByPosition, Order: todo.OrderAsc, Value: "b", Id: 6}),
+		},
+		{
+			name:   "Bad Deadline",
+			sort:   todo.SortByDeadline,
+			cursor: encodeCursor(cursor{Sort: todo.SortByDeadline, Order: todo.OrderAsc, Value: "abc", Id: 6}),
+		},
+		{
+			name:   "Bad Creation Time",
+			sort:   todo.SortByCreatedAt,
+			cursor: encodeCursor(cursor{Sort: todo.SortByCreatedAt, Order: todo.OrderAsc, Value: "2025-03-01", Id: 6}),
+		},
+		{
+			name:   "Bad Priority",
+			sort:   todo.SortByPriority,
+			cursor: encodeCursor(cursor{Sort: todo.SortByPriority, Order: todo.OrderAsc, Value: "abc", Id: 6}),
+		},
+		{
+			name:   "Priority Out Of Range",
+			sort:   todo.SortByPriority,
+			cursor: encodeCursor(cursor{Sort: todo.SortByPriority, Order: todo.OrderAsc, Value: "40000", Id: 6}),
+		},
+		{
+			name:   "NUL In Title",
+			sort:   todo.SortByTitle,
+			cursor: encodeCursor(cursor{Sort: todo.SortByTitle, Order: todo.OrderAsc, Value: "a\x00b", Id: 6}),
+		},
+		{
+			name:   "Id Out Of Range",
+			sort:   todo.SortByPosition,
+			cursor: encodeCursor(cursor{Sort: todo.SortByPosition, Order: todo.OrderAsc, Value: "b", Id: 1 << 40}),
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			db, mock := newMockDB(t)
+
+			filter := todo.ItemFilter{Sort: testCase.sort, Order: todo.OrderAsc, Cursor: testCase.cursor, Limit: todo.DefaultItemsLimit}
+			_, _, err := NewTodoItemPostgres(db).GetAll(context.Background(), 1, 3, filter)
+
+			assert.ErrorIs(t, err, todo.ErrInvalidCursor)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestTodoItemPostgres_filters(t *testing.T) {
+	done := false
+	listId := 3
+
+	testTable := []struct {
+		name          string
+		listId        *int
+		filter        todo.ItemFilter
+		expectedWhere string
+		expectedArgs  []driver.Value
+	}{
+		{
+			name:          "Open Items Of List",
+			listId:        &listId,
+			filter:        todo.ItemFilter{Done: &done, Sort: todo.SortByPosition, Order: todo.OrderAsc, Limit: 10},
+			expectedWhere: "li.list_id = $2 AND ti.done = $3 ORDER BY li.position ASC",
+			expectedArgs:  []driver.Value{1, 3, false, 11},
+		},
+		{
+			name:          "Search Across Lists",
+			filter:        todo.ItemFilter{Query: "50%_off", Sort: todo.SortByCreatedAt, Order: todo.OrderDesc, Limit: 10},
+			expectedWhere: "(ti.title ILIKE $2 OR ti.description ILIKE $2) ORDER BY ti.created_at DESC",
+			expectedArgs:  []driver.Value{1, `%50\%\_off%`, 11},
+		},
+		{
+			name:          "Open Items By Title",
+			filter:        todo.ItemFilter{Done: &done, Query: "milk", Sort: todo.SortByTitle, Order: todo.OrderDesc, Limit: 10},
+			expectedWhere: "ti.done = $2 AND (ti.title ILIKE $3 OR ti.description ILIKE $3) ORDER BY ti.title DESC, ti.id DESC LIMIT $4",
+			expectedArgs:  []driver.Value{1, false, "%milk%", 11},
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			db, mock := newMockDB(t)
+			r := NewTodoItemPostgres(db)
+
+			mock.ExpectQuery(regexp.QuoteMeta(testCase.expectedWhere)).
+				WithArgs(testCase.expectedArgs...).
+				WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+			var err error
+			if testCase.listId != nil {
+				_, _, err = r.GetAll(context.Background(), 1, *testCase.listId, testCase.filter)
+			} else {
+				_, _, err = r.Find(context.Background(), 1, testCase.filter)
+			}
+
+			assert.NoError(t, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"context"
 	"fmt"
 	"github.com/jmoiron/sqlx"
 	"github.com/lypolix/todo-app"
@@ -16,83 +17,197 @@ func NewTodoListPostgres(db *sqlx.DB) *TodoListPostgres {
 	return &TodoListPostgres{db: db}
 }
 
-func (r *TodoListPostgres) Create(userId int, list todo.TodoList) (int, error) {
-	tx, err := r.db.Begin()
+func (r *TodoListPostgres) Create(ctx context.Context, userId int, list todo.TodoList) (int, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
 	if err != nil {
 		return 0, err
 	}
 
+	id, err := createList(ctx, tx, userId, list)
+	if err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	return id, tx.Commit()
+}
+
+// createList creates the list owned by the user within tx.
+func createList(ctx context.Context, tx *sqlx.Tx, userId int, list todo.TodoList) (int, error) {
 	var id int
 	createListQuery := fmt.Sprintf("INSERT INTO %s (title, description) VALUES ($1, $2) RETURNING id", todoListsTable)
-	row := tx.QueryRow(createListQuery, list.Title, list.Description)
+	row := tx.QueryRowContext(ctx, createListQuery, list.Title, list.Description)
 	if err := row.Scan(&id); err != nil {
-		tx.Rollback()
 		return 0, err
 	}
 
-	createUsersListQuery := fmt.Sprintf("INSERT INTO %s (user_id, list_id) VALUES ($1, $2)", usersListsTable)
-	_, err = tx.Exec(createUsersListQuery, userId, id)
+	createUsersListQuery := fmt.Sprintf("INSERT INTO %s (user_id, list_id, role) VALUES ($1, $2, $3)", usersListsTable)
+	if _, err := tx.ExecContext(ctx, createUsersListQuery, userId, id, todo.RoleOwner); err != nil {
+		return 0, err
+	}
+
+	changes := todo.Changes{}
+	addChange(changes, "title", "", list.Title)
+	addChange(changes, "description", "", list.Description)
+	if err := logActivity(ctx, tx, todo.Activity{ActorId: &userId, ListId: id, Action: todo.ActionCreate, Changes: changes}); err != nil {
+		return 0, err
+	}
+
+	return id, nil
+}
+
+// bumpListVersion marks a change of the list that is stored outside of its
+// row, like its members.
+func bumpListVersion(ctx context.Context, tx *sqlx.Tx, listId int) error {
+	query := fmt.Sprintf("UPDATE %s SET version = version + 1 WHERE id = $1", todoListsTable)
+	_, err := tx.ExecContext(ctx, query, listId)
+
+	return err
+}
+
+// Import creates the list together with its items in one transaction, the
+// same way Create and TodoItemPostgres.Create do. With dryRun everything is
+// rolled back at the end and no id is returned.
+func (r *TodoListPostgres) Import(ctx context.Context, userId int, list todo.TodoList, items []todo.TodoItem, dryRun bool) (int, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	id, err := createList(ctx, tx, userId, list)
 	if err != nil {
 		tx.Rollback()
 		return 0, err
 	}
 
+	for _, item := range items {
+		if _, err := createItem(ctx, tx, userId, id, item, item.Done); err != nil {
+			tx.Rollback()
+			return 0, err
+		}
+	}
+
+	if dryRun {
+		return 0, tx.Rollback()
+	}
+
 	return id, tx.Commit()
 }
 
-func (r *TodoListPostgres) GetAll(userId int) ([]todo.TodoList, error){
+func (r *TodoListPostgres) GetAll(ctx context.Context, userId int) ([]todo.TodoList, error){
 	var lists []todo.TodoList
-	query := fmt.Sprintf("SELECT tl.id, tl.title, tl.description FROM %s tl INNER JOIN %s ul on tl.id = ul.list_id WHERE ul.user_id = $1", todoListsTable, usersListsTable)
-	err := r.db.Select(&lists, query, userId)
+	query := fmt.Sprintf("SELECT tl.id, tl.title, tl.description, ul.role, tl.version FROM %s tl INNER JOIN %s ul on tl.id = ul.list_id WHERE ul.user_id = $1 AND tl.deleted_at IS NULL", todoListsTable, usersListsTable)
+	err := r.db.SelectContext(ctx, &lists, query, userId)
 
 	return lists, err
 }
 
-func (r *TodoListPostgres) GetById(userId, listId int) (todo.TodoList, error){
+func (r *TodoListPostgres) GetById(ctx context.Context, userId, listId int) (todo.TodoList, error){
 	var list todo.TodoList
 
-	query := fmt.Sprintf(`SELECT tl.id, tl.title, tl.description FROM %s tl 
-						INNER JOIN %s ul on tl.id = ul.list_id WHERE ul.user_id = $1 AND ul.list_id = $2`,
+	query := fmt.Sprintf(`SELECT tl.id, tl.title, tl.description, ul.role, tl.version FROM %s tl 
+						INNER JOIN %s ul on tl.id = ul.list_id WHERE ul.user_id = $1 AND ul.list_id = $2 AND tl.deleted_at IS NULL`,
 		todoListsTable, usersListsTable)
-	err := r.db.Get(&list, query, userId, listId)
+	err := r.db.GetContext(ctx, &list, query, userId, listId)
 
-	return list, err
+	return list, notFound(err, todo.ErrListNotFound)
 }
 
-func (r *TodoListPostgres) Delete(userId, listId int) error{
-	query := fmt.Sprintf("DELETE FROM %s tl USING %s ul WHERE tl.id = ul.list_id AND ul.user_id=$1 AND ul.list_id=$2",
-		todoListsTable, usersListsTable)
-	_, err := r.db.Exec(query, userId, listId)
+// Delete moves the list to the trash, its items go along with it. With a
+// version the list is only deleted if it still has that version.
+func (r *TodoListPostgres) Delete(ctx context.Context, userId, listId int, version *int) error{
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
 
-	return err
+	if err := requireListRole(ctx, tx, userId, listId, todo.RoleOwner); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	query := fmt.Sprintf("UPDATE %s SET deleted_at = now(), version = version + 1 WHERE id = $1 AND ($2::int IS NULL OR version = $2)", todoListsTable)
+	res, err := tx.ExecContext(ctx, query, listId, version)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := checkAffected(res, func() error { return todo.ErrPreconditionFailed }); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := logActivity(ctx, tx, todo.Activity{ActorId: &userId, ListId: listId, Action: todo.ActionDelete}); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
 }
 
-func (r *TodoListPostgres) Update(userId, listId int, input todo.UpdateListInput) error {
-	setValues := make([]string, 0)
+func (r *TodoListPostgres) Update(ctx context.Context, userId, listId int, input todo.UpdateListInput) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if err := requireListRole(ctx, tx, userId, listId, todo.RoleOwner, todo.RoleEditor); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	var before todo.TodoList
+	beforeQuery := fmt.Sprintf("SELECT id, title, description FROM %s WHERE id = $1 FOR UPDATE", todoListsTable)
+	if err := tx.GetContext(ctx, &before, beforeQuery, listId); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	setValues := []string{"version=version+1"}
 	args := make([]interface{}, 0)
 	argId := 1
+	changes := todo.Changes{}
 
 	if input.Title != nil {
 		setValues = append(setValues, fmt.Sprintf("title=$%d", argId))
 		args = append(args, *&input.Title)
 		argId ++
+		addChange(changes, "title", before.Title, *input.Title)
 	}
 
 	if input.Description != nil {
 		setValues = append(setValues, fmt.Sprintf("description=$%d", argId))
 		args = append(args, *&input.Description)
 		argId++
+		addChange(changes, "description", before.Description, *input.Description)
 	}
 
 	setQuery := strings.Join(setValues, ", ")
 
-	query := fmt.Sprintf("UPDATE %s tl SET %s FROM %s ul WHERE tl.id = ul.list_id AND ul.list_id=$%d AND ul.user_id=$%d", 
-		todoListsTable, setQuery, usersListsTable, argId, argId+1)
-	args = append(args, listId, userId)
+	query := fmt.Sprintf("UPDATE %s SET %s WHERE id=$%d AND ($%d::int IS NULL OR version=$%d)", todoListsTable, setQuery, argId, argId+1, argId+1)
+	args = append(args, listId, input.Version)
 
 	logrus.Debugf("updateQuery: %s", query)
 	logrus.Debugf("args: %s", args)
 
-	_, err := r.db.Exec(query, args...)
-	return err
+	res, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := checkAffected(res, func() error { return todo.ErrPreconditionFailed }); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if len(changes) > 0 {
+		if err := logActivity(ctx, tx, todo.Activity{ActorId: &userId, ListId: listId, Action: todo.ActionUpdate, Changes: changes}); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit()
 }
\ No newline at end of file
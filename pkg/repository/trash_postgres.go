@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lypolix/todo-app"
+)
+
+type TrashPostgres struct {
+	db *sqlx.DB
+}
+
+func NewTrashPostgres(db *sqlx.DB) *TrashPostgres {
+	return &TrashPostgres{db: db}
+}
+
+// GetAll returns the trashed lists and items the user may restore, most
+// recently deleted first.
+func (r *TrashPostgres) GetAll(ctx context.Context, userId int) (todo.Trash, error) {
+	trash := todo.Trash{
+		Lists: make([]todo.TodoList, 0),
+		Items: make([]todo.TodoItem, 0),
+	}
+
+	listsQuery := fmt.Sprintf(`SELECT tl.id, tl.title, tl.description, ul.role, tl.deleted_at FROM %s tl
+							INNER JOIN %s ul on tl.id = ul.list_id
+							WHERE ul.user_id = $1 AND ul.role IN (%s) AND tl.deleted_at IS NOT NULL ORDER BY tl.deleted_at DESC, tl.id`,
+		todoListsTable, usersListsTable, deleteRoles)
+	if err := r.db.SelectContext(ctx, &trash.Lists, listsQuery, userId); err != nil {
+		return trash, err
+	}
+
+	itemsQuery := fmt.Sprintf(`SELECT %s, li.list_id, li.position, ti.deleted_at FROM %s ti INNER JOIN %s li on li.item_id = ti.id
+							INNER JOIN %s ul on ul.list_id = li.list_id INNER JOIN %s tl on tl.id = li.list_id
+							WHERE ul.user_id = $1 AND ul.role IN (%s) AND ti.deleted_at IS NOT NULL AND tl.deleted_at IS NULL
+							ORDER BY ti.deleted_at DESC, ti.id`,
+		itemColumns, todoItemsTable, listsItemsTable, usersListsTable, todoListsTable, deleteRoles)
+	if err := r.db.SelectContext(ctx, &trash.Items, itemsQuery, userId); err != nil {
+		return trash, err
+	}
+
+	if err := loadLabels(ctx, r.db, userId, trash.Items); err != nil {
+		return trash, err
+	}
+
+	return trash, nil
+}
+
+func (r *TrashPostgres) RestoreList(ctx context.Context, userId, listId int) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf(`UPDATE %s tl SET deleted_at = NULL, version = tl.version + 1 FROM %s ul
+							WHERE tl.id = ul.list_id AND ul.user_id = $1 AND tl.id = $2 AND ul.role IN (%s) AND tl.deleted_at IS NOT NULL`,
+		todoListsTable, usersListsTable, deleteRoles)
+	res, err := tx.ExecContext(ctx, query, userId, listId)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := checkAffected(res, func() error { return todo.ErrListNotFound }); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := logActivity(ctx, tx, todo.Activity{ActorId: &userId, ListId: listId, Action: todo.ActionRestore}); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// RestoreItem takes the item out of the trash. Items of a trashed list can
+// only come back with the list.
+func (r *TrashPostgres) RestoreItem(ctx context.Context, userId, itemId int) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	var listId int
+	query := fmt.Sprintf(`UPDATE %s ti SET deleted_at = NULL, version = ti.version + 1 FROM %s li, %s ul, %s tl
+							WHERE ti.id = li.item_id AND li.list_id = ul.list_id AND tl.id = li.list_id
+								AND ul.user_id = $1 AND ti.id = $2 AND ul.role IN (%s) AND ti.deleted_at IS NOT NULL AND tl.deleted_at IS NULL
+							RETURNING li.list_id`,
+		todoItemsTable, listsItemsTable, usersListsTable, todoListsTable, deleteRoles)
+	if err := tx.GetContext(ctx, &listId, query, userId, itemId); err != nil {
+		tx.Rollback()
+		return notFound(err, todo.ErrItemNotFound)
+	}
+
+	if err := logActivity(ctx, tx, todo.Activity{ActorId: &userId, ListId: listId, ItemId: &itemId, Action: todo.ActionRestore}); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Purge permanently deletes the lists and items trashed before the given
+// time, together with all items of the purged lists.
+func (r *TrashPostgres) Purge(ctx context.Context, before time.Time) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	listItemsQuery := fmt.Sprintf(`DELETE FROM %s WHERE id IN (SELECT li.item_id FROM %s li INNER JOIN %s tl on tl.id = li.list_id
+							WHERE tl.deleted_at < $1)`, todoItemsTable, listsItemsTable, todoListsTable)
+	if _, err := tx.ExecContext(ctx, listItemsQuery, before); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	listsQuery := fmt.Sprintf("DELETE FROM %s WHERE deleted_at < $1", todoListsTable)
+	if _, err := tx.ExecContext(ctx, listsQuery, before); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	itemsQuery := fmt.Sprintf("DELETE FROM %s WHERE deleted_at < $1", todoItemsTable)
+	if _, err := tx.ExecContext(ctx, itemsQuery, before); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,32 @@
+package service
+
+import (
+	"context"
+
+	"github.com/lypolix/todo-app"
+	"github.com/lypolix/todo-app/pkg/repository"
+)
+
+type ActivityService struct {
+	repo repository.Activity
+}
+
+func NewActivityService(repo repository.Activity) *ActivityService {
+	return &ActivityService{repo: repo}
+}
+
+func (s *ActivityService) GetByList(ctx context.Context, userId, listId int, page todo.Page) ([]todo.Activity, string, error) {
+	if err := page.Validate(); err != nil {
+		return nil, "", err
+	}
+
+	return s.repo.GetByList(ctx, userId, listId, page)
+}
+
+func (s *ActivityService) GetByItem(ctx context.Context, userId, itemId int, page todo.Page) ([]todo.Activity, string, error) {
+	if err := page.Validate(); err != nil {
+		return nil, "", err
+	}
+
+	return s.repo.GetByItem(ctx, userId, itemId, page)
+}
@@ -0,0 +1,62 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/lypolix/todo-app"
+)
+
+// GetAgenda groups the user's open items relative to now, whose location
+// decides where the user's days and weeks begin. Weeks start on Monday.
+func (s *TodoItemService) GetAgenda(ctx context.Context, userId int, now time.Time) (todo.Agenda, error) {
+	_, _, weekEnd := agendaBounds(now)
+
+	items, err := s.repo.GetAgenda(ctx, userId, weekEnd, todo.PriorityHigh)
+	if err != nil {
+		return todo.Agenda{}, err
+	}
+
+	return splitAgenda(items, now), nil
+}
+
+// agendaBounds returns the start of the day of now and the starts of the next
+// day and the next week.
+func agendaBounds(now time.Time) (today, tomorrow, weekEnd time.Time) {
+	today = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	tomorrow = today.AddDate(0, 0, 1)
+
+	daysLeft := (8 - int(today.Weekday())) % 7
+	if daysLeft == 0 {
+		daysLeft = 7
+	}
+	weekEnd = today.AddDate(0, 0, daysLeft)
+
+	return today, tomorrow, weekEnd
+}
+
+// splitAgenda puts the items into the agenda groups keeping their order.
+func splitAgenda(items []todo.TodoItem, now time.Time) todo.Agenda {
+	_, tomorrow, weekEnd := agendaBounds(now)
+	agenda := todo.Agenda{
+		Overdue:  make([]todo.TodoItem, 0),
+		Today:    make([]todo.TodoItem, 0),
+		ThisWeek: make([]todo.TodoItem, 0),
+		Undated:  make([]todo.TodoItem, 0),
+	}
+
+	for _, item := range items {
+		switch {
+		case item.Deadline == nil:
+			agenda.Undated = append(agenda.Undated, item)
+		case item.Deadline.Before(now):
+			agenda.Overdue = append(agenda.Overdue, item)
+		case item.Deadline.Before(tomorrow):
+			agenda.Today = append(agenda.Today, item)
+		case item.Deadline.Before(weekEnd):
+			agenda.ThisWeek = append(agenda.ThisWeek, item)
+		}
+	}
+
+	return agenda
+}
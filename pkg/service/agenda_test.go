@@ -0,0 +1,81 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lypolix/todo-app"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAgendaBounds(t *testing.T) {
+	moscow := time.FixedZone("MSK", 3*60*60)
+
+	testTable := []struct {
+		name            string
+		now             time.Time
+		expectedWeekEnd time.Time
+	}{
+		{
+			name:            "wednesday",
+			now:             time.Date(2025, time.January, 8, 15, 0, 0, 0, time.UTC),
+			expectedWeekEnd: time.Date(2025, time.January, 13, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:            "monday",
+			now:             time.Date(2025, time.January, 6, 0, 0, 0, 0, time.UTC),
+			expectedWeekEnd: time.Date(2025, time.January, 13, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:            "sunday",
+			now:             time.Date(2025, time.January, 12, 23, 59, 0, 0, time.UTC),
+			expectedWeekEnd: time.Date(2025, time.January, 13, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:            "user's time zone",
+			now:             time.Date(2025, time.January, 12, 22, 0, 0, 0, time.UTC).In(moscow),
+			expectedWeekEnd: time.Date(2025, time.January, 20, 0, 0, 0, 0, moscow),
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			_, _, weekEnd := agendaBounds(testCase.now)
+
+			assert.True(t, testCase.expectedWeekEnd.Equal(weekEnd), "expected %s, got %s", testCase.expectedWeekEnd, weekEnd)
+		})
+	}
+}
+
+func TestSplitAgenda(t *testing.T) {
+	// Wednesday
+	now := time.Date(2025, time.January, 8, 15, 0, 0, 0, time.UTC)
+	at := func(days, hours int) *time.Time {
+		deadline := time.Date(2025, time.January, 8+days, hours, 0, 0, 0, time.UTC)
+		return &deadline
+	}
+
+	items := []todo.TodoItem{
+		{Id: 1, Deadline: at(-2, 10)},
+		{Id: 2, Deadline: at(0, 9)},
+		{Id: 3, Deadline: at(0, 18)},
+		{Id: 4, Deadline: at(1, 0)},
+		{Id: 5, Deadline: at(4, 23)},
+		{Id: 6, Priority: todo.PriorityUrgent},
+		{Id: 7, Priority: todo.PriorityHigh},
+	}
+
+	agenda := splitAgenda(items, now)
+
+	ids := func(items []todo.TodoItem) []int {
+		ids := make([]int, 0, len(items))
+		for _, item := range items {
+			ids = append(ids, item.Id)
+		}
+		return ids
+	}
+	assert.Equal(t, []int{1, 2}, ids(agenda.Overdue))
+	assert.Equal(t, []int{3}, ids(agenda.Today))
+	assert.Equal(t, []int{4, 5}, ids(agenda.ThisWeek))
+	assert.Equal(t, []int{6, 7}, ids(agenda.Undated))
+}
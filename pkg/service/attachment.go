@@ -0,0 +1,190 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/lypolix/todo-app"
+	"github.com/lypolix/todo-app/pkg/repository"
+	"github.com/lypolix/todo-app/pkg/storage"
+	"github.com/sirupsen/logrus"
+)
+
+const maxFileNameLength = 255
+
+type AttachmentConfig struct {
+	// MaxSize is the largest file that can be uploaded, zero means no limit
+	MaxSize int64
+	// Quota is how much all files uploaded by one user may take, zero means no limit
+	Quota int64
+}
+
+type AttachmentService struct {
+	repo  repository.Attachment
+	files storage.Storage
+	cfg   AttachmentConfig
+}
+
+func NewAttachmentService(repo repository.Attachment, files storage.Storage, cfg AttachmentConfig) *AttachmentService {
+	return &AttachmentService{repo: repo, files: files, cfg: cfg}
+}
+
+// Create stores the content and saves the metadata of the file. Size is
+// what the client announced, the stored size is what was actually read. The
+// role and the quota are checked before anything is stored.
+func (s *AttachmentService) Create(ctx context.Context, userId, itemId int, attachment todo.Attachment, content io.Reader) (int, error) {
+	if s.cfg.MaxSize > 0 && attachment.Size > s.cfg.MaxSize {
+		return 0, todo.ErrFileTooLarge
+	}
+
+	if err := s.repo.CheckUpload(ctx, userId, itemId, attachment.Size, s.cfg.Quota); err != nil {
+		return 0, err
+	}
+
+	key, err := randomToken(24)
+	if err != nil {
+		return 0, err
+	}
+
+	body := bufio.NewReader(content)
+	// a short or failing read shows up again when the content is saved
+	head, _ := body.Peek(512)
+
+	attachment.FileName = cleanFileName(attachment.FileName)
+	attachment.ContentType = contentType(attachment.FileName, attachment.ContentType, head)
+	attachment.StorageKey = key
+
+	var r io.Reader = body
+	if s.cfg.MaxSize > 0 {
+		r = &sizeLimiter{r: body, left: s.cfg.MaxSize}
+	}
+
+	attachment.Size, err = s.files.Save(ctx, key, r)
+	if err != nil {
+		return 0, err
+	}
+
+	id, err := s.repo.Create(ctx, userId, itemId, attachment, s.cfg.Quota)
+	if err != nil {
+		if err := s.files.Delete(context.WithoutCancel(ctx), key); err != nil {
+			logrus.Errorf("failed to delete file %s: %s", key, err.Error())
+		}
+		return 0, err
+	}
+
+	return id, nil
+}
+
+func (s *AttachmentService) GetAll(ctx context.Context, userId, itemId int) ([]todo.Attachment, error) {
+	return s.repo.GetAll(ctx, userId, itemId)
+}
+
+// Open returns the metadata and the content of the file, the caller closes
+// the content.
+func (s *AttachmentService) Open(ctx context.Context, userId, itemId, attachmentId int) (todo.Attachment, io.ReadCloser, error) {
+	attachment, err := s.repo.GetById(ctx, userId, itemId, attachmentId)
+	if err != nil {
+		return attachment, nil, err
+	}
+
+	content, err := s.files.Open(ctx, attachment.StorageKey)
+	if err != nil {
+		return attachment, nil, err
+	}
+
+	return attachment, content, nil
+}
+
+func (s *AttachmentService) Delete(ctx context.Context, userId, itemId, attachmentId int) error {
+	attachment, err := s.repo.Delete(ctx, userId, itemId, attachmentId)
+	if err != nil {
+		return err
+	}
+
+	// the attachment is gone for the user, a stale file only takes up space
+	if err := s.files.Delete(context.WithoutCancel(ctx), attachment.StorageKey); err != nil {
+		logrus.Errorf("failed to delete file %s: %s", attachment.StorageKey, err.Error())
+	}
+
+	return nil
+}
+
+// PurgeDetached deletes the files of purged items. The metadata of a file
+// is only deleted with the file, so a failed deletion is retried next time.
+func (s *AttachmentService) PurgeDetached(ctx context.Context) error {
+	keys, err := s.repo.GetDetached(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, key := range keys {
+		if err := s.files.Delete(ctx, key); err != nil {
+			return err
+		}
+
+		if err := s.repo.DeleteDetached(ctx, key); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// cleanFileName drops the directories some clients send along with the name.
+func cleanFileName(name string) string {
+	name = strings.TrimSpace(path.Base(strings.ReplaceAll(name, "\\", "/")))
+	if name == "" || name == "." || name == "/" {
+		return "file"
+	}
+
+	if runes := []rune(name); len(runes) > maxFileNameLength {
+		name = string(runes[:maxFileNameLength])
+	}
+
+	return name
+}
+
+// contentType trusts the type the client sent unless it is missing or
+// generic, then guesses it from the extension and at last from the content.
+func contentType(fileName, declared string, head []byte) string {
+	if mediaType, _, err := mime.ParseMediaType(declared); err == nil && mediaType != "application/octet-stream" {
+		return declared
+	}
+
+	if byExtension := mime.TypeByExtension(filepath.Ext(fileName)); byExtension != "" {
+		return byExtension
+	}
+
+	return http.DetectContentType(head)
+}
+
+// sizeLimiter fails with todo.ErrFileTooLarge as soon as more than left
+// bytes are read, so an oversized upload is not stored in full.
+type sizeLimiter struct {
+	r    io.Reader
+	left int64
+}
+
+func (l *sizeLimiter) Read(p []byte) (int, error) {
+	if l.left < 0 {
+		return 0, todo.ErrFileTooLarge
+	}
+
+	if int64(len(p)) > l.left+1 {
+		p = p[:l.left+1]
+	}
+
+	n, err := l.r.Read(p)
+	l.left -= int64(n)
+	if l.left < 0 {
+		return n, todo.ErrFileTooLarge
+	}
+
+	return n, err
+}
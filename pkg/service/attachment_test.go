@@ -0,0 +1,194 @@
+package service_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/lypolix/todo-app"
+	"github.com/lypolix/todo-app/pkg/service"
+	"github.com/lypolix/todo-app/pkg/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+// attachmentRepo keeps the last created attachment and fails with err, or
+// with checkErr already before the file is stored.
+type attachmentRepo struct {
+	created  *todo.Attachment
+	err      error
+	checkErr error
+}
+
+func (r *attachmentRepo) CheckUpload(context.Context, int, int, int64, int64) error {
+	return r.checkErr
+}
+func (r *attachmentRepo) Create(_ context.Context, _, _ int, attachment todo.Attachment, _ int64) (int, error) {
+	if r.err != nil {
+		return 0, r.err
+	}
+	r.created = &attachment
+	return 1, nil
+}
+func (r *attachmentRepo) GetAll(context.Context, int, int) ([]todo.Attachment, error) {
+	return nil, nil
+}
+func (r *attachmentRepo) GetById(context.Context, int, int, int) (todo.Attachment, error) {
+	return todo.Attachment{}, nil
+}
+func (r *attachmentRepo) Delete(context.Context, int, int, int) (todo.Attachment, error) {
+	return todo.Attachment{}, nil
+}
+func (r *attachmentRepo) GetDetached(context.Context) ([]string, error) { return nil, nil }
+func (r *attachmentRepo) DeleteDetached(context.Context, string) error  { return nil }
+
+// memoryStorage is a storage.Storage in a map.
+type memoryStorage map[string][]byte
+
+// errNotSaved is what failingStorage fails with.
+var errNotSaved = errors.New("file must not be saved")
+
+// failingStorage refuses to save anything.
+type failingStorage struct {
+	memoryStorage
+}
+
+func (s failingStorage) Save(context.Context, string, io.Reader) (int64, error) {
+	return 0, errNotSaved
+}
+
+func (s memoryStorage) Save(_ context.Context, key string, r io.Reader) (int64, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return 0, err
+	}
+	s[key] = data
+	return int64(len(data)), nil
+}
+func (s memoryStorage) Open(_ context.Context, key string) (io.ReadCloser, error) {
+	data, ok := s[key]
+	if !ok {
+		return nil, storage.ErrNotExist
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+func (s memoryStorage) Delete(_ context.Context, key string) error {
+	delete(s, key)
+	return nil
+}
+
+func TestAttachmentService_Create(t *testing.T) {
+	png := "\x89PNG\r\n\x1a\n" + strings.Repeat("\x00", 16)
+
+	testTable := []struct {
+		name                string
+		attachment          todo.Attachment
+		content             string
+		repoErr             error
+		expectedFileName    string
+		expectedContentType string
+		expectedErr         error
+	}{
+		{
+			name:                "declared type",
+			attachment:          todo.Attachment{FileName: "report.pdf", ContentType: "application/pdf"},
+			content:             "%PDF-1.7",
+			expectedFileName:    "report.pdf",
+			expectedContentType: "application/pdf",
+		},
+		{
+			name:                "type from extension",
+			attachment:          todo.Attachment{FileName: "notes.txt", ContentType: "application/octet-stream"},
+			content:             "buy milk",
+			expectedFileName:    "notes.txt",
+			expectedContentType: "text/plain; charset=utf-8",
+		},
+		{
+			name:                "type from content",
+			attachment:          todo.Attachment{FileName: "screenshot"},
+			content:             png,
+			expectedFileName:    "screenshot",
+			expectedContentType: "image/png",
+		},
+		{
+			name:                "path in file name",
+			attachment:          todo.Attachment{FileName: `C:\Users\alex\..\todo.txt`, ContentType: "text/plain"},
+			content:             "x",
+			expectedFileName:    "todo.txt",
+			expectedContentType: "text/plain",
+		},
+		{
+			name:        "announced size too large",
+			attachment:  todo.Attachment{FileName: "big.bin", Size: 65},
+			content:     "x",
+			expectedErr: todo.ErrFileTooLarge,
+		},
+		{
+			name:        "content too large",
+			attachment:  todo.Attachment{FileName: "big.bin", Size: 1},
+			content:     strings.Repeat("x", 65),
+			expectedErr: todo.ErrFileTooLarge,
+		},
+		{
+			name:        "quota exceeded",
+			attachment:  todo.Attachment{FileName: "notes.txt"},
+			content:     "buy milk",
+			repoErr:     todo.ErrQuotaExceeded,
+			expectedErr: todo.ErrQuotaExceeded,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			repo := &attachmentRepo{err: testCase.repoErr}
+			files := memoryStorage{}
+			attachments := service.NewAttachmentService(repo, files, service.AttachmentConfig{MaxSize: 64})
+
+			_, err := attachments.Create(context.Background(), 1, 2, testCase.attachment, strings.NewReader(testCase.content))
+
+			if testCase.expectedErr != nil {
+				assert.ErrorIs(t, err, testCase.expectedErr)
+				assert.Empty(t, files, "no file is left behind")
+				return
+			}
+			assert.NoError(t, err)
+			if assert.NotNil(t, repo.created) {
+				assert.Equal(t, testCase.expectedFileName, repo.created.FileName)
+				assert.Equal(t, testCase.expectedContentType, repo.created.ContentType)
+				assert.Equal(t, int64(len(testCase.content)), repo.created.Size)
+				assert.Equal(t, testCase.content, string(files[repo.created.StorageKey]))
+			}
+		})
+	}
+}
+
+func TestAttachmentService_CreateChecksFirst(t *testing.T) {
+	testTable := []struct {
+		name     string
+		checkErr error
+	}{
+		{
+			name:     "viewer",
+			checkErr: todo.ErrForbidden,
+		},
+		{
+			name:     "quota exceeded",
+			checkErr: todo.ErrQuotaExceeded,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			repo := &attachmentRepo{checkErr: testCase.checkErr}
+			attachments := service.NewAttachmentService(repo, failingStorage{memoryStorage{}}, service.AttachmentConfig{})
+
+			_, err := attachments.Create(context.Background(), 1, 2, todo.Attachment{FileName: "notes.txt", Size: 8}, strings.NewReader("buy milk"))
+
+			assert.ErrorIs(t, err, testCase.checkErr)
+			assert.NotErrorIs(t, err, errNotSaved)
+			assert.Nil(t, repo.created)
+		})
+	}
+}
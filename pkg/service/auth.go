@@ -1,80 +1,248 @@
 package service
 
 import (
-	"crypto/sha1"
-	"fmt"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
 	"time"
 	"errors"
 	"github.com/dgrijalva/jwt-go"
 	"github.com/lypolix/todo-app"
 	"github.com/lypolix/todo-app/pkg/repository"
+	"github.com/sirupsen/logrus"
 )
-const (
-	salt = "hjgrhjqw124617ajfhajs"
-	signingKey = "qrkjk#4#%35FSFJlja#4353KSFjH"
-	tokenTTL = 12 * time.Hour
-)
+type AuthConfig struct {
+	TokenTTL time.Duration
+	RefreshTokenTTL time.Duration
+	Keys *KeySet
+}
 
 type tokenClaims struct {
 	jwt.StandardClaims
 	UserId int `json:"user_id"`
 }
 
-
 type AuthService struct {
 	repo repository.Authorization
+	hasher PasswordHasher
+	cfg AuthConfig
 }
 
-func NewAuthService(repo repository.Authorization) *AuthService {
-	return &AuthService{repo: repo}
+func NewAuthService(repo repository.Authorization, hasher PasswordHasher, cfg AuthConfig) *AuthService {
+	return &AuthService{repo: repo, hasher: hasher, cfg: cfg}
 }	
 
-func (s *AuthService) CreateUser(user todo.User) (int, error) {
-	user.Password = generatePasswordHash(user.Password)
-	return s.repo.CreateUser(user)
+func (s *AuthService) CreateUser(ctx context.Context, user todo.User) (int, error) {
+	hash, err := s.hasher.Hash(user.Password)
+	if err != nil {
+		return 0, err
+	}
+
+	user.PasswordHash = hash
+	return s.repo.CreateUser(ctx, user)
 }
 
-func (s *AuthService) GenerateToken(username, password string) (string, error){
-	user, err := s.repo.GetUser(username, generatePasswordHash(password))
+func (s *AuthService) GenerateToken(ctx context.Context, username, password string) (todo.Tokens, error){
+	user, err := s.authenticate(ctx, username, password)
 	if err != nil {
-		return "", err
+		return todo.Tokens{}, err
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, &tokenClaims{
-		jwt.StandardClaims{
-		ExpiresAt: time.Now().Add(tokenTTL).Unix(),
-		IssuedAt: time.Now().Unix(),
-	    }, 
-		user.Id,
-    })
+	return s.newTokens(user.Id, func(token todo.RefreshToken) error {
+		return s.repo.CreateRefreshToken(ctx, token)
+	})
+}
 
-	return token.SignedString([]byte(signingKey))
+// Authenticate checks the credentials of clients that send them with every
+// request, like CalDAV clients, without issuing tokens.
+func (s *AuthService) Authenticate(ctx context.Context, username, password string) (int, error) {
+	user, err := s.authenticate(ctx, username, password)
+	if err != nil {
+		return 0, err
+	}
+
+	return user.Id, nil
 }
 
-func (s *AuthService) ParseToken(accessToken string) (int, error){
-	token, err := jwt.ParseWithClaims(accessToken, &tokenClaims{}, func(token *jwt.Token) (interface{}, error){
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, errors.New("invalid signing method")
+// RefreshToken exchanges a refresh token for a new token pair. Every refresh
+// token is single use: presenting an already rotated one means it leaked, so
+// all sessions of its owner are revoked.
+func (s *AuthService) RefreshToken(ctx context.Context, refreshToken string) (todo.Tokens, error) {
+	token, err := s.repo.GetRefreshToken(ctx, hashToken(refreshToken))
+	if errors.Is(err, sql.ErrNoRows) {
+		return todo.Tokens{}, todo.ErrInvalidRefreshToken
+	}
+	if err != nil {
+		return todo.Tokens{}, err
+	}
+
+	if token.ReplacedBy != nil {
+		if err := s.repo.RevokeAllTokens(ctx, token.UserId); err != nil {
+			return todo.Tokens{}, err
 		}
+		return todo.Tokens{}, todo.ErrInvalidRefreshToken
+	}
 
-		return []byte(signingKey), nil
+	if token.RevokedAt != nil || time.Now().After(token.ExpiresAt) {
+		return todo.Tokens{}, todo.ErrInvalidRefreshToken
+	}
+
+	tokens, err := s.newTokens(token.UserId, func(next todo.RefreshToken) error {
+		return s.repo.RotateRefreshToken(ctx, token.Id, next)
 	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return todo.Tokens{}, todo.ErrInvalidRefreshToken
+	}
 
-	if err != nil{
+	return tokens, err
+}
+
+// Logout revokes the access token and, when given, the refresh token of the session.
+func (s *AuthService) Logout(ctx context.Context, accessToken, refreshToken string) error {
+	claims, err := s.parseClaims(accessToken)
+	if err != nil {
+		return err
+	}
+
+	if refreshToken != "" {
+		if err := s.repo.RevokeRefreshToken(ctx, claims.UserId, hashToken(refreshToken)); err != nil {
+			return err
+		}
+	}
+
+	// tokens issued before jti was introduced cannot be denylisted and just expire
+	if claims.Id == "" {
+		return nil
+	}
+
+	return s.repo.RevokeAccessToken(ctx, claims.Id, time.Unix(claims.ExpiresAt, 0))
+}
+
+// LogoutAll ends every session of the user on all devices.
+func (s *AuthService) LogoutAll(ctx context.Context, userId int) error {
+	return s.repo.RevokeAllTokens(ctx, userId)
+}
+
+func (s *AuthService) ParseToken(ctx context.Context, accessToken string) (int, error){
+	claims, err := s.parseClaims(accessToken)
+	if err != nil {
 		return 0, err
+	}
+
+	revoked, err := s.repo.IsTokenRevoked(ctx, claims.UserId, claims.Id, time.Unix(claims.IssuedAt, 0))
+	if err != nil {
+		return 0, err
+	}
+	if revoked {
+		return 0, todo.ErrTokenRevoked
+	}
+
+	return claims.UserId, nil
+}
+
+func (s *AuthService) parseClaims(accessToken string) (*tokenClaims, error) {
+	token, err := jwt.ParseWithClaims(accessToken, &tokenClaims{}, s.cfg.Keys.Keyfunc)
+
+	if err != nil{
+		logrus.Debugf("invalid access token: %s", err.Error())
+		return nil, todo.ErrInvalidToken
 	} 
 
 	claims, ok := token.Claims.(*tokenClaims)
 	if !ok {
-		return 0, errors.New("token claims are not of type *tokenClaims")
+		return nil, todo.ErrInvalidToken
 	}
 
-	return claims.UserId, nil
+	return claims, nil
+}
+
+// newTokens signs an access token and creates a refresh token, handing the
+// latter to store so callers decide how it is persisted.
+func (s *AuthService) newTokens(userId int, store func(todo.RefreshToken) error) (todo.Tokens, error) {
+	refreshToken, err := randomToken(32)
+	if err != nil {
+		return todo.Tokens{}, err
+	}
+
+	err = store(todo.RefreshToken{
+		UserId: userId,
+		TokenHash: hashToken(refreshToken),
+		ExpiresAt: time.Now().Add(s.cfg.RefreshTokenTTL),
+	})
+	if err != nil {
+		return todo.Tokens{}, err
+	}
+
+	jti, err := randomToken(16)
+	if err != nil {
+		return todo.Tokens{}, err
+	}
+
+	accessToken, err := s.cfg.Keys.Sign(&tokenClaims{
+		jwt.StandardClaims{
+			Id: jti,
+			ExpiresAt: time.Now().Add(s.cfg.TokenTTL).Unix(),
+			IssuedAt: time.Now().Unix(),
+		},
+		userId,
+	})
+	if err != nil {
+		return todo.Tokens{}, err
+	}
+
+	return todo.Tokens{AccessToken: accessToken, RefreshToken: refreshToken}, nil
+}
+
+func randomToken(size int) (string, error) {
+	buf := make([]byte, size)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// hashToken is how refresh tokens are stored, so a database leak does not leak sessions.
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func (s *AuthService) JWKS() JWKS {
+	return s.cfg.Keys.JWKS()
 }
 
-func generatePasswordHash(password string) string {
-	hash := sha1.New()
-	hash.Write([]byte(password))
+// authenticate checks the password in Go and transparently upgrades hashes
+// produced by an outdated hasher or with outdated parameters.
+func (s *AuthService) authenticate(ctx context.Context, username, password string) (todo.User, error) {
+	user, err := s.repo.GetUser(ctx, username)
+	if errors.Is(err, sql.ErrNoRows) {
+		// hash anyway so unknown usernames take as long as wrong passwords
+		s.hasher.Hash(password)
+		return user, todo.ErrInvalidCredentials
+	}
+	if err != nil {
+		return user, err
+	}
+
+	ok, err := s.hasher.Verify(password, user.PasswordHash)
+	if err != nil {
+		return user, err
+	}
+	if !ok {
+		return user, todo.ErrInvalidCredentials
+	}
+
+	if s.hasher.NeedsRehash(user.PasswordHash) {
+		if hash, err := s.hasher.Hash(password); err != nil {
+			logrus.Errorf("failed to rehash password of user %d: %s", user.Id, err.Error())
+		} else if err := s.repo.UpdatePasswordHash(ctx, user.Id, hash); err != nil {
+			logrus.Errorf("failed to store rehashed password of user %d: %s", user.Id, err.Error())
+		}
+	}
 
-	return fmt.Sprintf("%x", hash.Sum([]byte(salt)))
+	return user, nil
 }
\ No newline at end of file
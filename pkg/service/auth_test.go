@@ -0,0 +1,235 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/golang/mock/gomock"
+	"github.com/lypolix/todo-app"
+	mock_repository "github.com/lypolix/todo-app/pkg/repository/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestAuthService(t *testing.T, repo *mock_repository.MockAuthorization) *AuthService {
+	keys, err := NewKeySet("hs", []KeyConfig{{Id: "hs", Algorithm: "HS256", Secret: []byte("secret")}})
+	assert.NoError(t, err)
+
+	return NewAuthService(repo, nil, AuthConfig{TokenTTL: time.Minute, RefreshTokenTTL: time.Hour, Keys: keys})
+}
+
+func TestAuthService_RefreshToken(t *testing.T) {
+	type mockBehavior func(r *mock_repository.MockAuthorization, hash string)
+
+	now := time.Now()
+	replacedBy := 8
+
+	testTable := []struct {
+		name         string
+		mockBehavior mockBehavior
+		expectedErr  error
+	}{
+		{
+			name: "Rotate",
+			mockBehavior: func(r *mock_repository.MockAuthorization, hash string) {
+				r.EXPECT().GetRefreshToken(gomock.Any(), hash).Return(todo.RefreshToken{Id: 7, UserId: 2, ExpiresAt: now.Add(time.Hour)}, nil)
+				r.EXPECT().RotateRefreshToken(gomock.Any(), 7, gomock.Any()).DoAndReturn(
+					func(_ context.Context, _ int, next todo.RefreshToken) error {
+						assert.Equal(t, 2, next.UserId)
+						assert.NotEqual(t, hash, next.TokenHash)
+						return nil
+					})
+			},
+		},
+		{
+			// a rotated token is presented again, so it leaked
+			name: "Reuse",
+			mockBehavior: func(r *mock_repository.MockAuthorization, hash string) {
+				r.EXPECT().GetRefreshToken(gomock.Any(), hash).Return(todo.RefreshToken{Id: 7, UserId: 2, ExpiresAt: now.Add(time.Hour), RevokedAt: &now, ReplacedBy: &replacedBy}, nil)
+				r.EXPECT().RevokeAllTokens(gomock.Any(), 2).Return(nil)
+			},
+			expectedErr: todo.ErrInvalidRefreshToken,
+		},
+		{
+			name: "Used Concurrently",
+			mockBehavior: func(r *mock_repository.MockAuthorization, hash string) {
+				r.EXPECT().GetRefreshToken(gomock.Any(), hash).Return(todo.RefreshToken{Id: 7, UserId: 2, ExpiresAt: now.Add(time.Hour)}, nil)
+				r.EXPECT().RotateRefreshToken(gomock.Any(), 7, gomock.Any()).Return(sql.ErrNoRows)
+			},
+			expectedErr: todo.ErrInvalidRefreshToken,
+		},
+		{
+			name: "Logged Out",
+			mockBehavior: func(r *mock_repository.MockAuthorization, hash string) {
+				r.EXPECT().GetRefreshToken(gomock.Any(), hash).Return(todo.RefreshToken{Id: 7, UserId: 2, ExpiresAt: now.Add(time.Hour), RevokedAt: &now}, nil)
+			},
+			expectedErr: todo.ErrInvalidRefreshToken,
+		},
+		{
+			name: "Expired",
+			mockBehavior: func(r *mock_repository.MockAuthorization, hash string) {
+				r.EXPECT().GetRefreshToken(gomock.Any(), hash).Return(todo.RefreshToken{Id: 7, UserId: 2, ExpiresAt: now.Add(-time.Hour)}, nil)
+			},
+			expectedErr: todo.ErrInvalidRefreshToken,
+		},
+		{
+			name: "Unknown",
+			mockBehavior: func(r *mock_repository.MockAuthorization, hash string) {
+				r.EXPECT().GetRefreshToken(gomock.Any(), hash).Return(todo.RefreshToken{}, sql.ErrNoRows)
+			},
+			expectedErr: todo.ErrInvalidRefreshToken,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock_repository.NewMockAuthorization(c)
+			testCase.mockBehavior(repo, hashToken("refresh"))
+
+			tokens, err := newTestAuthService(t, repo).RefreshToken(context.Background(), "refresh")
+
+			assert.ErrorIs(t, err, testCase.expectedErr)
+			if testCase.expectedErr == nil {
+				assert.NotEmpty(t, tokens.AccessToken)
+				assert.NotEmpty(t, tokens.RefreshToken)
+				assert.NotEqual(t, "refresh", tokens.RefreshToken)
+			}
+		})
+	}
+}
+
+func TestAuthService_Logout(t *testing.T) {
+	type mockBehavior func(r *mock_repository.MockAuthorization, expiresAt time.Time)
+
+	expiresAt := time.Now().Add(time.Minute).Truncate(time.Second)
+
+	testTable := []struct {
+		name         string
+		jti          string
+		refreshToken string
+		signedWith   string
+		mockBehavior mockBehavior
+		expectedErr  error
+	}{
+		{
+			name:         "Session",
+			jti:          "jti",
+			refreshToken: "refresh",
+			mockBehavior: func(r *mock_repository.MockAuthorization, expiresAt time.Time) {
+				r.EXPECT().RevokeRefreshToken(gomock.Any(), 2, hashToken("refresh")).Return(nil)
+				r.EXPECT().RevokeAccessToken(gomock.Any(), "jti", expiresAt).Return(nil)
+			},
+		},
+		{
+			name: "Access Token Only",
+			jti:  "jti",
+			mockBehavior: func(r *mock_repository.MockAuthorization, expiresAt time.Time) {
+				r.EXPECT().RevokeAccessToken(gomock.Any(), "jti", expiresAt).Return(nil)
+			},
+		},
+		{
+			// tokens without jti cannot be denylisted and just expire
+			name:         "Without Jti",
+			refreshToken: "refresh",
+			mockBehavior: func(r *mock_repository.MockAuthorization, expiresAt time.Time) {
+				r.EXPECT().RevokeRefreshToken(gomock.Any(), 2, hashToken("refresh")).Return(nil)
+			},
+		},
+		{
+			name:         "Invalid Token",
+			jti:          "jti",
+			refreshToken: "refresh",
+			signedWith:   "other secret",
+			mockBehavior: func(r *mock_repository.MockAuthorization, expiresAt time.Time) {},
+			expectedErr:  todo.ErrInvalidToken,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock_repository.NewMockAuthorization(c)
+			testCase.mockBehavior(repo, expiresAt)
+			auth := newTestAuthService(t, repo)
+
+			keys := auth.cfg.Keys
+			if testCase.signedWith != "" {
+				var err error
+				keys, err = NewKeySet("hs", []KeyConfig{{Id: "hs", Algorithm: "HS256", Secret: []byte(testCase.signedWith)}})
+				assert.NoError(t, err)
+			}
+			accessToken, err := keys.Sign(&tokenClaims{
+				jwt.StandardClaims{Id: testCase.jti, ExpiresAt: expiresAt.Unix(), IssuedAt: time.Now().Unix()},
+				2,
+			})
+			assert.NoError(t, err)
+
+			err = auth.Logout(context.Background(), accessToken, testCase.refreshToken)
+
+			assert.ErrorIs(t, err, testCase.expectedErr)
+		})
+	}
+}
+
+func TestAuthService_LogoutAll(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	repo := mock_repository.NewMockAuthorization(c)
+	repo.EXPECT().RevokeAllTokens(gomock.Any(), 2).Return(nil)
+
+	err := newTestAuthService(t, repo).LogoutAll(context.Background(), 2)
+
+	assert.NoError(t, err)
+}
+
+func TestAuthService_ParseToken(t *testing.T) {
+	issuedAt := time.Now().Truncate(time.Second)
+
+	testTable := []struct {
+		name           string
+		revoked        bool
+		expectedUserId int
+		expectedErr    error
+	}{
+		{
+			name:           "Valid",
+			expectedUserId: 2,
+		},
+		{
+			// denylisted by logout or issued before logout-all
+			name:        "Revoked",
+			revoked:     true,
+			expectedErr: todo.ErrTokenRevoked,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock_repository.NewMockAuthorization(c)
+			repo.EXPECT().IsTokenRevoked(gomock.Any(), 2, "jti", issuedAt).Return(testCase.revoked, nil)
+			auth := newTestAuthService(t, repo)
+
+			accessToken, err := auth.cfg.Keys.Sign(&tokenClaims{
+				jwt.StandardClaims{Id: "jti", ExpiresAt: issuedAt.Add(time.Minute).Unix(), IssuedAt: issuedAt.Unix()},
+				2,
+			})
+			assert.NoError(t, err)
+
+			userId, err := auth.ParseToken(context.Background(), accessToken)
+
+			assert.ErrorIs(t, err, testCase.expectedErr)
+			assert.Equal(t, testCase.expectedUserId, userId)
+		})
+	}
+}
@@ -0,0 +1,215 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+
+	"github.com/lypolix/todo-app"
+	"github.com/lypolix/todo-app/pkg/ical"
+	"github.com/lypolix/todo-app/pkg/repository"
+)
+
+type CalDAVService struct {
+	repo  repository.CalDAV
+	items TodoItem
+}
+
+// NewCalDAVService serves the lists as CalDAV calendars. Changes of items go
+// through items, so completing an occurrence over CalDAV schedules the next
+// one like the API does.
+func NewCalDAVService(repo repository.CalDAV, items TodoItem) *CalDAVService {
+	return &CalDAVService{repo: repo, items: items}
+}
+
+func (s *CalDAVService) GetCalendars(ctx context.Context, userId int) ([]todo.Calendar, error) {
+	return s.repo.GetCalendars(ctx, userId)
+}
+
+func (s *CalDAVService) GetCalendar(ctx context.Context, userId, listId int) (todo.Calendar, error) {
+	return s.repo.GetCalendar(ctx, userId, listId)
+}
+
+func (s *CalDAVService) GetObjects(ctx context.Context, userId, listId int) ([]todo.CalendarObject, error) {
+	objects, err := s.repo.GetObjects(ctx, userId, listId)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range objects {
+		if objects[i].Data, err = objectData(objects[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	return objects, nil
+}
+
+func (s *CalDAVService) GetObject(ctx context.Context, userId, listId int, name string) (todo.CalendarObject, error) {
+	object, err := s.repo.GetObject(ctx, userId, listId, name)
+	if err != nil {
+		return object, err
+	}
+
+	object.Data, err = objectData(object)
+
+	return object, err
+}
+
+// PutObject stores the VTODO of a resource, creating its item when the name
+// is new. It returns the stored object and whether it was created. The
+// update only applies to the version the precondition was checked against.
+// Removing DUE keeps the deadline of an item, an update cannot clear it.
+func (s *CalDAVService) PutObject(ctx context.Context, userId, listId int, name string, data io.Reader, cond todo.Precondition) (todo.CalendarObject, bool, error) {
+	component, err := parseObject(data)
+	if err != nil {
+		return todo.CalendarObject{}, false, err
+	}
+
+	stored, err := componentItem(component)
+	if err != nil {
+		return todo.CalendarObject{}, false, todo.InvalidCalendarObject(err.Error())
+	}
+	if err := todo.ValidateExportItems([]todo.ExportItem{stored}); err != nil {
+		return todo.CalendarObject{}, false, err
+	}
+
+	current, err := s.repo.GetObject(ctx, userId, listId, name)
+	if errors.Is(err, todo.ErrObjectNotFound) {
+		if err := cond.Check(""); err != nil {
+			return todo.CalendarObject{}, false, err
+		}
+
+		uid, _ := component.Get("UID")
+		if uid.Value == "" {
+			return todo.CalendarObject{}, false, todo.InvalidCalendarObject("VTODO has no UID")
+		}
+
+		object := todo.CalendarObject{
+			TodoItem: todo.TodoItem{
+				Title:       stored.Title,
+				Description: stored.Description,
+				Done:        stored.Done,
+				Deadline:    stored.Deadline,
+				Priority:    stored.Priority,
+			},
+			Name: name,
+			UID:  uid.Value,
+		}
+		if _, err := s.repo.CreateObject(ctx, userId, listId, object); err != nil {
+			return todo.CalendarObject{}, false, err
+		}
+
+		object, err = s.GetObject(ctx, userId, listId, name)
+		return object, true, err
+	}
+	if err != nil {
+		return todo.CalendarObject{}, false, err
+	}
+
+	if err := cond.Check(current.ETag()); err != nil {
+		return todo.CalendarObject{}, false, err
+	}
+
+	if input := objectUpdate(current.TodoItem, stored); input != (todo.UpdateItemInput{}) {
+		input.Version = &current.Version
+		if err := s.items.Update(ctx, userId, current.Id, input); err != nil {
+			return todo.CalendarObject{}, false, err
+		}
+	}
+
+	object, err := s.GetObject(ctx, userId, listId, name)
+	return object, false, err
+}
+
+// DeleteObject moves the item of the resource to the trash, unless it has
+// changed since the precondition was checked.
+func (s *CalDAVService) DeleteObject(ctx context.Context, userId, listId int, name string, cond todo.Precondition) error {
+	object, err := s.repo.GetObject(ctx, userId, listId, name)
+	if err != nil {
+		return err
+	}
+
+	if err := cond.Check(object.ETag()); err != nil {
+		return err
+	}
+
+	return s.items.Delete(ctx, userId, object.Id, &object.Version)
+}
+
+// parseObject reads a calendar object resource, a VCALENDAR with the one
+// VTODO stored in it.
+func parseObject(data io.Reader) (ical.Component, error) {
+	calendar, err := ical.Parse(data)
+	if err != nil {
+		if errors.Is(err, ical.ErrInvalid) {
+			return ical.Component{}, todo.InvalidCalendarObject(err.Error())
+		}
+		return ical.Component{}, err
+	}
+
+	if calendar.Name != "VCALENDAR" {
+		return ical.Component{}, todo.InvalidCalendarObject("data is not a VCALENDAR")
+	}
+
+	var todos []ical.Component
+	for _, component := range calendar.Components {
+		if component.Name == "VTODO" {
+			todos = append(todos, component)
+		}
+	}
+
+	if len(todos) != 1 {
+		return ical.Component{}, todo.InvalidCalendarObject("calendar object must contain exactly one VTODO")
+	}
+
+	return todos[0], nil
+}
+
+// objectUpdate changes the fields of the item that differ from the stored
+// to-do.
+func objectUpdate(item todo.TodoItem, stored todo.ExportItem) todo.UpdateItemInput {
+	var input todo.UpdateItemInput
+	if stored.Title != item.Title {
+		input.Title = &stored.Title
+	}
+	if stored.Description != item.Description {
+		input.Description = &stored.Description
+	}
+	if stored.Done != item.Done {
+		input.Done = &stored.Done
+	}
+	if stored.Deadline != nil && (item.Deadline == nil || !stored.Deadline.Equal(*item.Deadline)) {
+		input.Deadline = stored.Deadline
+	}
+	if stored.Priority != item.Priority {
+		input.Priority = &stored.Priority
+	}
+
+	return input
+}
+
+// objectData is the object as a calendar with its VTODO. The to-do is
+// stamped with the creation time of the item, so the data only changes
+// together with the ETag.
+func objectData(object todo.CalendarObject) ([]byte, error) {
+	c := itemTodo(object.TodoItem, object.CreatedAt)
+	if object.UID != "" {
+		for i := range c.Properties {
+			if c.Properties[i].Name == "UID" {
+				c.Properties[i].Value = object.UID
+			}
+		}
+	}
+
+	calendar := newCalendar()
+	calendar.Components = append(calendar.Components, c)
+
+	var buf bytes.Buffer
+	if err := calendar.Encode(&buf); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
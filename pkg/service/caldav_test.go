@@ -0,0 +1,86 @@
+package service
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lypolix/todo-app"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseObject(t *testing.T) {
+	testTable := []struct {
+		name        string
+		data        string
+		expectedErr bool
+	}{
+		{
+			name: "one to-do",
+			data: "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nBEGIN:VTODO\r\nUID:a\r\nSUMMARY:Milk\r\nEND:VTODO\r\nEND:VCALENDAR\r\n",
+		},
+		{
+			name:        "event",
+			data:        "BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nUID:a\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n",
+			expectedErr: true,
+		},
+		{
+			name:        "two to-dos",
+			data:        "BEGIN:VCALENDAR\r\nBEGIN:VTODO\r\nUID:a\r\nEND:VTODO\r\nBEGIN:VTODO\r\nUID:b\r\nEND:VTODO\r\nEND:VCALENDAR\r\n",
+			expectedErr: true,
+		},
+		{
+			name:        "not a calendar",
+			data:        "BEGIN:VTODO\r\nUID:a\r\nEND:VTODO\r\n",
+			expectedErr: true,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			component, err := parseObject(strings.NewReader(testCase.data))
+
+			if testCase.expectedErr {
+				assert.ErrorIs(t, err, todo.ErrInvalidInput)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, "Milk", component.Text("SUMMARY"))
+		})
+	}
+}
+
+func TestObjectUpdate(t *testing.T) {
+	deadline := time.Date(2025, time.March, 1, 9, 0, 0, 0, time.UTC)
+	later := deadline.Add(time.Hour)
+	item := todo.TodoItem{Title: "Milk", Description: "2 l", Deadline: &deadline, Priority: todo.PriorityHigh}
+
+	title := "Oat milk"
+	done := true
+
+	testTable := []struct {
+		name     string
+		stored   todo.ExportItem
+		expected todo.UpdateItemInput
+	}{
+		{
+			name:   "unchanged",
+			stored: todo.ExportItem{Title: "Milk", Description: "2 l", Deadline: &deadline, Priority: todo.PriorityHigh},
+		},
+		{
+			name:     "changed",
+			stored:   todo.ExportItem{Title: title, Description: "2 l", Done: true, Deadline: &later, Priority: todo.PriorityHigh},
+			expected: todo.UpdateItemInput{Title: &title, Done: &done, Deadline: &later},
+		},
+		{
+			name:   "due removed",
+			stored: todo.ExportItem{Title: "Milk", Description: "2 l", Priority: todo.PriorityHigh},
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			assert.Equal(t, testCase.expected, objectUpdate(item, testCase.stored))
+		})
+	}
+}
@@ -0,0 +1,271 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lypolix/todo-app"
+	"github.com/lypolix/todo-app/pkg/ical"
+	"github.com/lypolix/todo-app/pkg/repository"
+)
+
+// Calendar components the feed can be limited to.
+const (
+	ComponentTodo  = "vtodo"
+	ComponentEvent = "vevent"
+)
+
+const productId = "-//todo-app//EN"
+
+type CalendarService struct {
+	repo  repository.Calendar
+	items repository.TodoItem
+}
+
+func NewCalendarService(repo repository.Calendar, items repository.TodoItem) *CalendarService {
+	return &CalendarService{repo: repo, items: items}
+}
+
+// CreateToken gives the user a new secret for the URL of their feed. Only
+// its hash is stored, so it cannot be shown again.
+func (s *CalendarService) CreateToken(ctx context.Context, userId int) (string, error) {
+	token, err := randomToken(32)
+	if err != nil {
+		return "", err
+	}
+
+	if err := s.repo.SetToken(ctx, userId, hashToken(token)); err != nil {
+		return "", err
+	}
+
+	return token, nil
+}
+
+func (s *CalendarService) DeleteToken(ctx context.Context, userId int) error {
+	return s.repo.DeleteToken(ctx, userId)
+}
+
+// Feed is the calendar of the items with a deadline the owner of the token
+// can see. Every item is a VTODO and a VEVENT at its deadline, component
+// limits the feed to one of them.
+func (s *CalendarService) Feed(ctx context.Context, token, component string) ([]byte, error) {
+	if component != "" && component != ComponentTodo && component != ComponentEvent {
+		return nil, todo.NewError(todo.ErrInvalidInput, "invalid_param", "component must be vtodo or vevent")
+	}
+
+	userId, err := s.repo.GetUserId(ctx, hashToken(token))
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := s.repo.GetItems(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	calendar := newCalendar()
+	calendar.AddText("X-WR-CALNAME", "Todo")
+	now := time.Now()
+	for _, item := range items {
+		if component != ComponentEvent {
+			calendar.Components = append(calendar.Components, itemTodo(item, now))
+		}
+		if component != ComponentTodo {
+			calendar.Components = append(calendar.Components, itemEvent(item, now))
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := calendar.Encode(&buf); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// Import appends the to-dos and events of an iCalendar file to the list.
+// Cancelled ones are skipped, nothing is created if any of the others
+// cannot be.
+func (s *CalendarService) Import(ctx context.Context, userId, listId int, data io.Reader) ([]int, error) {
+	calendar, err := ical.Parse(data)
+	if err != nil {
+		if errors.Is(err, ical.ErrInvalid) {
+			return nil, todo.InvalidImport(err.Error())
+		}
+		return nil, err
+	}
+
+	if calendar.Name != "VCALENDAR" {
+		return nil, todo.InvalidImport("data is not a VCALENDAR")
+	}
+
+	var imported []todo.ExportItem
+	for i, component := range calendar.Components {
+		if component.Name != "VTODO" && component.Name != "VEVENT" {
+			continue
+		}
+		if strings.EqualFold(component.Text("STATUS"), "CANCELLED") {
+			continue
+		}
+
+		item, err := componentItem(component)
+		if err != nil {
+			return nil, todo.InvalidImport(fmt.Sprintf("component %d: %s", i+1, err.Error()))
+		}
+		imported = append(imported, item)
+	}
+
+	if len(imported) == 0 {
+		return nil, todo.InvalidImport("no to-dos or events found")
+	}
+
+	if err := todo.ValidateExportItems(imported); err != nil {
+		return nil, err
+	}
+
+	items := make([]todo.TodoItem, len(imported))
+	for i, item := range imported {
+		items[i] = todo.TodoItem{
+			Title:       item.Title,
+			Description: item.Description,
+			Done:        item.Done,
+			Deadline:    item.Deadline,
+			Priority:    item.Priority,
+		}
+	}
+
+	return s.items.CreateAll(ctx, userId, listId, items)
+}
+
+func newCalendar() ical.Component {
+	calendar := ical.Component{Name: "VCALENDAR"}
+	calendar.Add("VERSION", "2.0")
+	calendar.Add("PRODID", productId)
+	calendar.Add("CALSCALE", "GREGORIAN")
+
+	return calendar
+}
+
+func itemUID(itemId int) string {
+	return fmt.Sprintf("item-%d@todo-app", itemId)
+}
+
+// itemTodo is the item as a VTODO, stamped at now.
+func itemTodo(item todo.TodoItem, now time.Time) ical.Component {
+	c := ical.Component{Name: "VTODO"}
+	c.Add("UID", itemUID(item.Id))
+	c.AddTime("DTSTAMP", now)
+	c.AddTime("CREATED", item.CreatedAt)
+	c.AddText("SUMMARY", item.Title)
+	if item.Description != "" {
+		c.AddText("DESCRIPTION", item.Description)
+	}
+	if item.Deadline != nil {
+		c.AddTime("DUE", *item.Deadline)
+	}
+	if priority := icalPriority(item.Priority); priority != 0 {
+		c.Add("PRIORITY", strconv.Itoa(priority))
+	}
+	if item.Done {
+		c.Add("STATUS", "COMPLETED")
+	} else {
+		c.Add("STATUS", "NEEDS-ACTION")
+	}
+
+	return c
+}
+
+// itemEvent is the deadline of the item as an event without duration, for
+// calendars that do not show to-dos. Done items are marked in the summary.
+func itemEvent(item todo.TodoItem, now time.Time) ical.Component {
+	summary := item.Title
+	if item.Done {
+		summary = "✓ " + summary
+	}
+
+	c := ical.Component{Name: "VEVENT"}
+	c.Add("UID", fmt.Sprintf("item-%d-due@todo-app", item.Id))
+	c.AddTime("DTSTAMP", now)
+	c.AddTime("DTSTART", *item.Deadline)
+	c.AddText("SUMMARY", summary)
+	if item.Description != "" {
+		c.AddText("DESCRIPTION", item.Description)
+	}
+	c.Add("TRANSP", "TRANSPARENT")
+
+	return c
+}
+
+// componentItem reads a VTODO or a VEVENT. The deadline of a to-do is its
+// due time, or its start without one, the deadline of an event its start.
+func componentItem(c ical.Component) (todo.ExportItem, error) {
+	item := todo.ExportItem{
+		Title:       strings.TrimSpace(c.Text("SUMMARY")),
+		Description: strings.TrimSpace(c.Text("DESCRIPTION")),
+	}
+
+	deadline, ok := c.Get("DTSTART")
+	if due, hasDue := c.Get("DUE"); hasDue && c.Name == "VTODO" {
+		deadline, ok = due, true
+	}
+	if ok {
+		t, err := deadline.Time()
+		if err != nil {
+			return item, fmt.Errorf("invalid %s", deadline.Name)
+		}
+		item.Deadline = &t
+	}
+
+	if c.Name == "VTODO" {
+		_, completed := c.Get("COMPLETED")
+		item.Done = completed || strings.EqualFold(c.Text("STATUS"), "COMPLETED")
+	}
+
+	if value := c.Text("PRIORITY"); value != "" {
+		priority, err := strconv.Atoi(value)
+		if err != nil || priority < 0 || priority > 9 {
+			return item, errors.New("priority must be a number from 0 to 9")
+		}
+		item.Priority = itemPriority(priority)
+	}
+
+	return item, nil
+}
+
+// icalPriority maps priorities to the iCalendar scale, where 1 is the
+// highest, 9 the lowest and 0 undefined.
+func icalPriority(p todo.Priority) int {
+	switch p {
+	case todo.PriorityUrgent:
+		return 1
+	case todo.PriorityHigh:
+		return 3
+	case todo.PriorityMedium:
+		return 5
+	case todo.PriorityLow:
+		return 7
+	default:
+		return 0
+	}
+}
+
+func itemPriority(p int) todo.Priority {
+	switch {
+	case p == 0:
+		return todo.PriorityNone
+	case p <= 2:
+		return todo.PriorityUrgent
+	case p <= 4:
+		return todo.PriorityHigh
+	case p == 5:
+		return todo.PriorityMedium
+	default:
+		return todo.PriorityLow
+	}
+}
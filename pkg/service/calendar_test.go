@@ -0,0 +1,93 @@
+package service
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lypolix/todo-app"
+	"github.com/lypolix/todo-app/pkg/ical"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestItemTodoRoundTrip(t *testing.T) {
+	deadline := time.Date(2025, time.August, 20, 15, 0, 0, 0, time.UTC)
+	item := todo.TodoItem{Id: 42, Title: "Report, draft", Description: "two\nlines", Done: true, Deadline: &deadline, Priority: todo.PriorityHigh}
+
+	calendar := newCalendar()
+	calendar.Components = append(calendar.Components, itemTodo(item, time.Now()))
+
+	var buf bytes.Buffer
+	if !assert.NoError(t, calendar.Encode(&buf)) {
+		return
+	}
+
+	parsed, err := ical.Parse(&buf)
+	if !assert.NoError(t, err) || !assert.Len(t, parsed.Components, 1) {
+		return
+	}
+
+	assert.Equal(t, "item-42@todo-app", parsed.Components[0].Text("UID"))
+
+	imported, err := componentItem(parsed.Components[0])
+
+	assert.NoError(t, err)
+	assert.Equal(t, todo.ExportItem{Title: item.Title, Description: item.Description, Done: true, Deadline: &deadline, Priority: todo.PriorityHigh}, imported)
+}
+
+func TestComponentItem(t *testing.T) {
+	start := time.Date(2025, time.August, 20, 9, 0, 0, 0, time.UTC)
+	due := time.Date(2025, time.August, 22, 0, 0, 0, 0, time.UTC)
+
+	testTable := []struct {
+		name        string
+		data        string
+		expected    todo.ExportItem
+		expectedErr bool
+	}{
+		{
+			name:     "event",
+			data:     "BEGIN:VEVENT\r\nSUMMARY:Dentist\r\nDTSTART:20250820T090000Z\r\nDTEND:20250820T100000Z\r\nPRIORITY:1\r\nEND:VEVENT\r\n",
+			expected: todo.ExportItem{Title: "Dentist", Deadline: &start, Priority: todo.PriorityUrgent},
+		},
+		{
+			name:     "to-do due date",
+			data:     "BEGIN:VTODO\r\nSUMMARY:Taxes\r\nDTSTART:20250820T090000Z\r\nDUE;VALUE=DATE:20250822\r\nCOMPLETED:20250821T120000Z\r\nEND:VTODO\r\n",
+			expected: todo.ExportItem{Title: "Taxes", Deadline: &due, Done: true},
+		},
+		{
+			name:     "to-do without dates",
+			data:     "BEGIN:VTODO\r\nSUMMARY:Someday\r\nPRIORITY:6\r\nEND:VTODO\r\n",
+			expected: todo.ExportItem{Title: "Someday", Priority: todo.PriorityLow},
+		},
+		{
+			name:        "invalid priority",
+			data:        "BEGIN:VTODO\r\nSUMMARY:Taxes\r\nPRIORITY:high\r\nEND:VTODO\r\n",
+			expectedErr: true,
+		},
+		{
+			name:        "invalid date",
+			data:        "BEGIN:VEVENT\r\nSUMMARY:Dentist\r\nDTSTART:tomorrow\r\nEND:VEVENT\r\n",
+			expectedErr: true,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			component, err := ical.Parse(strings.NewReader(testCase.data))
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			item, err := componentItem(component)
+
+			if testCase.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.expected, item)
+		})
+	}
+}
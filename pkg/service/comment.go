@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+
+	"github.com/lypolix/todo-app"
+	"github.com/lypolix/todo-app/pkg/repository"
+	"github.com/sirupsen/logrus"
+)
+
+type CommentService struct {
+	repo   repository.Comment
+	events *Events
+}
+
+func NewCommentService(repo repository.Comment, events *Events) *CommentService {
+	return &CommentService{repo: repo, events: events}
+}
+
+// Create adds the comment and tells the other users of the item's list
+// about it.
+func (s *CommentService) Create(ctx context.Context, userId, itemId int, comment todo.Comment) (int, error) {
+	if err := comment.Validate(); err != nil {
+		return 0, err
+	}
+
+	id, err := s.repo.Create(ctx, userId, itemId, comment)
+	if err != nil {
+		return 0, err
+	}
+
+	// the comment is saved, failing to announce it must not fail the request
+	if err := s.announce(ctx, userId, itemId, id); err != nil {
+		logrus.Errorf("failed to announce comment %d: %s", id, err.Error())
+	}
+
+	return id, nil
+}
+
+func (s *CommentService) announce(ctx context.Context, userId, itemId, commentId int) error {
+	comment, err := s.repo.GetById(ctx, userId, itemId, commentId)
+	if err != nil {
+		return err
+	}
+
+	userIds, err := s.repo.GetItemUsers(ctx, itemId)
+	if err != nil {
+		return err
+	}
+
+	recipients := make([]int, 0, len(userIds))
+	for _, id := range userIds {
+		if id != userId {
+			recipients = append(recipients, id)
+		}
+	}
+
+	if len(recipients) > 0 {
+		s.events.Publish(Event{Type: EventCommentCreated, ItemId: itemId, Data: comment, Recipients: recipients})
+	}
+
+	return nil
+}
+
+func (s *CommentService) GetAll(ctx context.Context, userId, itemId int) ([]todo.Comment, error) {
+	return s.repo.GetAll(ctx, userId, itemId)
+}
+
+func (s *CommentService) Update(ctx context.Context, userId, itemId, commentId int, input todo.UpdateCommentInput) error {
+	if err := input.Validate(); err != nil {
+		return err
+	}
+
+	return s.repo.Update(ctx, userId, itemId, commentId, input)
+}
+
+func (s *CommentService) Delete(ctx context.Context, userId, itemId, commentId int) error {
+	return s.repo.Delete(ctx, userId, itemId, commentId)
+}
@@ -0,0 +1,79 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/lypolix/todo-app"
+	"github.com/lypolix/todo-app/pkg/service"
+	"github.com/stretchr/testify/assert"
+)
+
+// commentRepo saves every comment, users are the users of the item's list.
+type commentRepo struct {
+	users []int
+}
+
+func (commentRepo) Create(context.Context, int, int, todo.Comment) (int, error) { return 1, nil }
+func (commentRepo) GetAll(context.Context, int, int) ([]todo.Comment, error)    { return nil, nil }
+func (commentRepo) GetById(_ context.Context, userId, itemId, commentId int) (todo.Comment, error) {
+	return todo.Comment{Id: commentId, ItemId: itemId, AuthorId: userId, Body: "hi"}, nil
+}
+func (commentRepo) Update(context.Context, int, int, int, todo.UpdateCommentInput) error {
+	return nil
+}
+func (commentRepo) Delete(context.Context, int, int, int) error { return nil }
+func (r commentRepo) GetItemUsers(context.Context, int) ([]int, error) {
+	return r.users, nil
+}
+
+func TestCommentService_Create(t *testing.T) {
+	testTable := []struct {
+		name               string
+		users              []int
+		expectedRecipients []int
+	}{
+		{
+			name:               "other users",
+			users:              []int{1, 2, 3},
+			expectedRecipients: []int{2, 3},
+		},
+		{
+			name:  "author only",
+			users: []int{1},
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			var published []service.Event
+			events := service.NewEvents()
+			events.Subscribe(func(event service.Event) {
+				published = append(published, event)
+			})
+
+			comments := service.NewCommentService(commentRepo{users: testCase.users}, events)
+			id, err := comments.Create(context.Background(), 1, 5, todo.Comment{Body: "hi"})
+
+			assert.NoError(t, err)
+			assert.Equal(t, 1, id)
+			if testCase.expectedRecipients == nil {
+				assert.Empty(t, published)
+				return
+			}
+			if assert.Len(t, published, 1) {
+				assert.Equal(t, service.EventCommentCreated, published[0].Type)
+				assert.Equal(t, 5, published[0].ItemId)
+				assert.Equal(t, testCase.expectedRecipients, published[0].Recipients)
+			}
+		})
+	}
+}
+
+func TestCommentService_CreateInvalid(t *testing.T) {
+	comments := service.NewCommentService(commentRepo{}, service.NewEvents())
+
+	_, err := comments.Create(context.Background(), 1, 5, todo.Comment{Body: "   "})
+
+	assert.ErrorIs(t, err, todo.ErrInvalidComment)
+}
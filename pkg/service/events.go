@@ -0,0 +1,41 @@
+package service
+
+import "sync"
+
+const EventCommentCreated = "comment_created"
+
+// Event is a change other users should learn about right away, Recipients
+// are the ids of those users.
+type Event struct {
+	Type       string      `json:"type"`
+	ItemId     int         `json:"item_id"`
+	Data       interface{} `json:"data"`
+	Recipients []int       `json:"-"`
+}
+
+// Events passes events from the services to whoever delivers them, e.g. the
+// websocket server. Handlers are called synchronously and must not block.
+type Events struct {
+	mu       sync.RWMutex
+	handlers []func(Event)
+}
+
+func NewEvents() *Events {
+	return &Events{}
+}
+
+func (e *Events) Subscribe(handler func(Event)) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.handlers = append(e.handlers, handler)
+}
+
+func (e *Events) Publish(event Event) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	for _, handler := range e.handlers {
+		handler(event)
+	}
+}
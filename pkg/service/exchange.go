@@ -0,0 +1,102 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"io"
+
+	"github.com/lypolix/todo-app"
+	"github.com/lypolix/todo-app/pkg/repository"
+)
+
+type ExchangeService struct {
+	lists repository.TodoList
+	items repository.TodoItem
+}
+
+func NewExchangeService(lists repository.TodoList, items repository.TodoItem) *ExchangeService {
+	return &ExchangeService{lists: lists, items: items}
+}
+
+// Export writes the list and its items, in their order in the list, in the
+// given format.
+func (s *ExchangeService) Export(ctx context.Context, userId, listId int, format string) ([]byte, error) {
+	if err := todo.ValidateFormat(format); err != nil {
+		return nil, err
+	}
+
+	list, err := s.lists.GetById(ctx, userId, listId)
+	if err != nil {
+		return nil, err
+	}
+
+	export := todo.ListExport{Title: list.Title, Description: list.Description, Items: make([]todo.ExportItem, 0)}
+	filter := todo.ItemFilter{Sort: todo.SortByPosition, Order: todo.OrderAsc, TagMatch: todo.TagMatchAny, Limit: todo.MaxItemsLimit}
+	for {
+		items, next, err := s.items.GetAll(ctx, userId, listId, filter)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, item := range items {
+			export.Items = append(export.Items, todo.ExportItem{
+				Title:       item.Title,
+				Description: item.Description,
+				Done:        item.Done,
+				Deadline:    item.Deadline,
+				Priority:    item.Priority,
+			})
+		}
+
+		if next == "" {
+			break
+		}
+		filter.Cursor = next
+	}
+
+	var buf bytes.Buffer
+	if err := encodeList(&buf, format, export); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// Import creates a new list owned by the user from data. Nothing is created
+// unless the whole list can be, and with DryRun nothing is created at all.
+func (s *ExchangeService) Import(ctx context.Context, userId int, input todo.ImportInput, data io.Reader) (todo.ImportResult, error) {
+	if err := input.Validate(); err != nil {
+		return todo.ImportResult{}, err
+	}
+
+	list, err := decodeList(data, input.Format)
+	if err != nil {
+		return todo.ImportResult{}, err
+	}
+
+	if input.Title != "" {
+		list.Title = input.Title
+	}
+
+	if err := list.Validate(); err != nil {
+		return todo.ImportResult{}, err
+	}
+
+	items := make([]todo.TodoItem, len(list.Items))
+	for i, item := range list.Items {
+		items[i] = todo.TodoItem{
+			Title:       item.Title,
+			Description: item.Description,
+			Done:        item.Done,
+			Deadline:    item.Deadline,
+			Priority:    item.Priority,
+		}
+	}
+
+	id, err := s.lists.Import(ctx, userId, todo.TodoList{Title: list.Title, Description: list.Description}, items, input.DryRun)
+	if err != nil {
+		return todo.ImportResult{}, err
+	}
+
+	return todo.ImportResult{ListId: id, DryRun: input.DryRun, List: list}, nil
+}
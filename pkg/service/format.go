@@ -0,0 +1,253 @@
+package service
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lypolix/todo-app"
+)
+
+var csvHeader = []string{"title", "description", "done", "deadline", "priority"}
+
+// Markdown checklist items, "- [x] title", and plain list items, "- title".
+var (
+	checklistItem = regexp.MustCompile(`^\s*[-*+]\s+\[([ xX])\]\s*(.*)$`)
+	listItem      = regexp.MustCompile(`^\s*[-*+]\s+(.*)$`)
+)
+
+func encodeList(w io.Writer, format string, list todo.ListExport) error {
+	switch format {
+	case todo.FormatJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(list)
+	case todo.FormatCSV:
+		return encodeCSV(w, list)
+	case todo.FormatMarkdown:
+		return encodeMarkdown(w, list)
+	default:
+		return todo.ErrInvalidFormat
+	}
+}
+
+func decodeList(r io.Reader, format string) (todo.ListExport, error) {
+	switch format {
+	case todo.FormatJSON:
+		var list todo.ListExport
+		if err := json.NewDecoder(r).Decode(&list); err != nil {
+			return list, invalidData(err)
+		}
+		return list, nil
+	case todo.FormatCSV:
+		return decodeCSV(r)
+	case todo.FormatMarkdown:
+		return decodeMarkdown(r)
+	default:
+		return todo.ListExport{}, todo.ErrInvalidFormat
+	}
+}
+
+// invalidData keeps domain errors, e.g. of an unknown priority, and reports
+// anything else as data that cannot be imported.
+func invalidData(err error) error {
+	var e *todo.Error
+	if errors.As(err, &e) {
+		return e
+	}
+
+	return todo.InvalidImport(err.Error())
+}
+
+// encodeCSV writes one row per item, CSV has no place for the list itself.
+func encodeCSV(w io.Writer, list todo.ListExport) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(csvHeader); err != nil {
+		return err
+	}
+
+	for _, item := range list.Items {
+		deadline := ""
+		if item.Deadline != nil {
+			deadline = item.Deadline.UTC().Format(time.RFC3339)
+		}
+
+		record := []string{item.Title, item.Description, strconv.FormatBool(item.Done), deadline, item.Priority.String()}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// decodeCSV reads the columns by the names in the header, only title is
+// required. Deadlines are RFC 3339 times or dates.
+func decodeCSV(r io.Reader) (todo.ListExport, error) {
+	list := todo.ListExport{Items: make([]todo.ExportItem, 0)}
+
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return list, todo.InvalidImport("csv header is missing")
+	}
+	if err != nil {
+		return list, invalidData(err)
+	}
+
+	columns := make(map[string]int)
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	if _, ok := columns["title"]; !ok {
+		return list, todo.InvalidImport("csv header has no title column")
+	}
+
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return list, nil
+		}
+		if err != nil {
+			return list, invalidData(err)
+		}
+
+		line, _ := reader.FieldPos(0)
+		field := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		item := todo.ExportItem{Title: field("title"), Description: field("description")}
+
+		if done := field("done"); done != "" {
+			if item.Done, err = strconv.ParseBool(done); err != nil {
+				return list, todo.InvalidImport(fmt.Sprintf("line %d: done must be true or false", line))
+			}
+		}
+
+		if deadline := field("deadline"); deadline != "" {
+			t, err := parseDeadline(deadline)
+			if err != nil {
+				return list, todo.InvalidImport(fmt.Sprintf("line %d: deadline must be a RFC 3339 time or a date", line))
+			}
+			item.Deadline = &t
+		}
+
+		if priority := field("priority"); priority != "" {
+			if item.Priority, err = todo.ParsePriority(strings.ToLower(priority)); err != nil {
+				return list, err
+			}
+		}
+
+		list.Items = append(list.Items, item)
+	}
+}
+
+func parseDeadline(s string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+
+	return time.Parse("2006-01-02", s)
+}
+
+// encodeMarkdown writes the list as a checklist under a heading with its
+// title. Item descriptions are indented below their item, deadlines and
+// priorities are left out.
+func encodeMarkdown(w io.Writer, list todo.ListExport) error {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# %s\n", oneLine(list.Title))
+	if list.Description != "" {
+		fmt.Fprintf(&b, "\n%s\n", list.Description)
+	}
+
+	if len(list.Items) > 0 {
+		b.WriteString("\n")
+	}
+
+	for _, item := range list.Items {
+		mark := " "
+		if item.Done {
+			mark = "x"
+		}
+		fmt.Fprintf(&b, "- [%s] %s\n", mark, oneLine(item.Title))
+
+		if item.Description != "" {
+			for _, line := range strings.Split(item.Description, "\n") {
+				fmt.Fprintf(&b, "  %s\n", line)
+			}
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func oneLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// decodeMarkdown takes the first "# " heading as the title of the list and
+// the text before the first item as its description. Every list item is an
+// item, done when it is checked, and indented lines below an item are its
+// description. Anything else is ignored.
+func decodeMarkdown(r io.Reader) (todo.ListExport, error) {
+	list := todo.ListExport{Items: make([]todo.ExportItem, 0)}
+	var description []string
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), " \t\r")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		if match := checklistItem.FindStringSubmatch(line); match != nil {
+			list.Items = append(list.Items, todo.ExportItem{Title: strings.TrimSpace(match[2]), Done: match[1] != " "})
+			continue
+		}
+
+		if match := listItem.FindStringSubmatch(line); match != nil {
+			list.Items = append(list.Items, todo.ExportItem{Title: strings.TrimSpace(match[1])})
+			continue
+		}
+
+		if len(list.Items) > 0 {
+			if line[0] == ' ' || line[0] == '\t' {
+				item := &list.Items[len(list.Items)-1]
+				item.Description = strings.TrimLeft(strings.Join([]string{item.Description, strings.TrimSpace(line)}, "\n"), "\n")
+			}
+			continue
+		}
+
+		if strings.HasPrefix(line, "# ") && list.Title == "" {
+			list.Title = strings.TrimSpace(line[2:])
+			continue
+		}
+
+		if !strings.HasPrefix(line, "#") {
+			description = append(description, strings.TrimSpace(line))
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return list, invalidData(err)
+	}
+
+	list.Description = strings.Join(description, "\n")
+	return list, nil
+}
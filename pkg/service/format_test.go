@@ -0,0 +1,126 @@
+package service
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lypolix/todo-app"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncodeDecodeList(t *testing.T) {
+	deadline := time.Date(2025, time.May, 30, 18, 0, 0, 0, time.UTC)
+	list := todo.ListExport{
+		Title: "Work",
+		Items: []todo.ExportItem{
+			{Title: "Report, May", Description: "send to \"finance\"", Done: true, Deadline: &deadline, Priority: todo.PriorityHigh},
+			{Title: "Standup"},
+		},
+	}
+
+	for _, format := range []string{todo.FormatJSON, todo.FormatCSV} {
+		t.Run(format, func(t *testing.T) {
+			var buf bytes.Buffer
+			assert.NoError(t, encodeList(&buf, format, list))
+
+			decoded, err := decodeList(&buf, format)
+
+			assert.NoError(t, err)
+			assert.Equal(t, list.Items, decoded.Items)
+		})
+	}
+
+	t.Run("md", func(t *testing.T) {
+		var buf bytes.Buffer
+		assert.NoError(t, encodeList(&buf, todo.FormatMarkdown, list))
+		assert.Equal(t, "# Work\n\n- [x] Report, May\n  send to \"finance\"\n- [ ] Standup\n", buf.String())
+
+		decoded, err := decodeList(&buf, todo.FormatMarkdown)
+
+		assert.NoError(t, err)
+		assert.Equal(t, todo.ListExport{
+			Title: "Work",
+			Items: []todo.ExportItem{
+				{Title: "Report, May", Description: "send to \"finance\"", Done: true},
+				{Title: "Standup"},
+			},
+		}, decoded)
+	})
+}
+
+func TestDecodeMarkdown(t *testing.T) {
+	data := `# Groceries
+For the weekend
+## Dairy
+- [ ] Milk
+- [X] Cheese
+    the hard one
+    not too old
+* Bread
+
+Some closing words
+`
+
+	list, err := decodeList(strings.NewReader(data), todo.FormatMarkdown)
+
+	assert.NoError(t, err)
+	assert.Equal(t, todo.ListExport{
+		Title:       "Groceries",
+		Description: "For the weekend",
+		Items: []todo.ExportItem{
+			{Title: "Milk"},
+			{Title: "Cheese", Description: "the hard one\nnot too old", Done: true},
+			{Title: "Bread"},
+		},
+	}, list)
+}
+
+func TestDecodeCSV(t *testing.T) {
+	deadline := time.Date(2025, time.May, 30, 0, 0, 0, 0, time.UTC)
+
+	testTable := []struct {
+		name          string
+		data          string
+		expected      []todo.ExportItem
+		expectedError string
+	}{
+		{
+			name:     "columns by name",
+			data:     "\ufeffPriority,Title,Deadline\nurgent,Taxes,2025-05-30\n,Walk,\n",
+			expected: []todo.ExportItem{{Title: "Taxes", Priority: todo.PriorityUrgent, Deadline: &deadline}, {Title: "Walk"}},
+		},
+		{
+			name:          "no title column",
+			data:          "name\nTaxes\n",
+			expectedError: "invalid_import",
+		},
+		{
+			name:          "invalid done",
+			data:          "title,done\nTaxes,maybe\n",
+			expectedError: "invalid_import",
+		},
+		{
+			name:          "invalid priority",
+			data:          "title,priority\nTaxes,asap\n",
+			expectedError: "invalid_priority",
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			list, err := decodeList(strings.NewReader(testCase.data), todo.FormatCSV)
+
+			if testCase.expectedError != "" {
+				var e *todo.Error
+				if assert.ErrorAs(t, err, &e) {
+					assert.Equal(t, testCase.expectedError, e.Code)
+				}
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.expected, list.Items)
+		})
+	}
+}
@@ -0,0 +1,262 @@
+package service
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+// KeyConfig describes one JWT key. HS256 keys need Secret, RS256 and EdDSA keys
+// need PEM encoded key material. A key without a private part can only verify
+// tokens, which is how retired keys stay valid until their tokens expire.
+type KeyConfig struct {
+	Id         string
+	Algorithm  string
+	Secret     []byte
+	PrivateKey []byte
+	PublicKey  []byte
+}
+
+type signingKey struct {
+	id        string
+	method    jwt.SigningMethod
+	signKey   interface{}
+	verifyKey interface{}
+}
+
+// KeySet holds the key used to sign new tokens and every key accepted for verification.
+type KeySet struct {
+	signing *signingKey
+	keys    map[string]*signingKey
+}
+
+// JWK is a public key in RFC 7517 format.
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+func NewKeySet(signingKeyId string, configs []KeyConfig) (*KeySet, error) {
+	set := &KeySet{keys: make(map[string]*signingKey)}
+
+	for _, cfg := range configs {
+		if cfg.Id == "" {
+			return nil, errors.New("jwt key without id")
+		}
+		if _, ok := set.keys[cfg.Id]; ok {
+			return nil, fmt.Errorf("duplicate jwt key id %q", cfg.Id)
+		}
+
+		key, err := parseKey(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("jwt key %q: %w", cfg.Id, err)
+		}
+		set.keys[cfg.Id] = key
+	}
+
+	signing, ok := set.keys[signingKeyId]
+	if !ok {
+		return nil, fmt.Errorf("signing key %q is not configured", signingKeyId)
+	}
+	if signing.signKey == nil {
+		return nil, fmt.Errorf("signing key %q has no private key", signingKeyId)
+	}
+	set.signing = signing
+
+	return set, nil
+}
+
+func parseKey(cfg KeyConfig) (*signingKey, error) {
+	key := &signingKey{id: cfg.Id}
+
+	switch cfg.Algorithm {
+	case jwt.SigningMethodHS256.Alg():
+		if len(cfg.Secret) == 0 {
+			return nil, errors.New("empty secret")
+		}
+		key.method = jwt.SigningMethodHS256
+		key.signKey = cfg.Secret
+		key.verifyKey = cfg.Secret
+	case jwt.SigningMethodRS256.Alg():
+		key.method = jwt.SigningMethodRS256
+		if len(cfg.PrivateKey) != 0 {
+			private, err := jwt.ParseRSAPrivateKeyFromPEM(cfg.PrivateKey)
+			if err != nil {
+				return nil, err
+			}
+			key.signKey = private
+			key.verifyKey = &private.PublicKey
+		}
+		if len(cfg.PublicKey) != 0 {
+			public, err := jwt.ParseRSAPublicKeyFromPEM(cfg.PublicKey)
+			if err != nil {
+				return nil, err
+			}
+			key.verifyKey = public
+		}
+	case SigningMethodEdDSA.Alg():
+		key.method = SigningMethodEdDSA
+		if len(cfg.PrivateKey) != 0 {
+			private, err := parsePEM(cfg.PrivateKey, x509.ParsePKCS8PrivateKey)
+			if err != nil {
+				return nil, err
+			}
+			edPrivate, ok := private.(ed25519.PrivateKey)
+			if !ok {
+				return nil, errors.New("private key is not an ed25519 key")
+			}
+			key.signKey = edPrivate
+			key.verifyKey = edPrivate.Public()
+		}
+		if len(cfg.PublicKey) != 0 {
+			public, err := parsePEM(cfg.PublicKey, x509.ParsePKIXPublicKey)
+			if err != nil {
+				return nil, err
+			}
+			edPublic, ok := public.(ed25519.PublicKey)
+			if !ok {
+				return nil, errors.New("public key is not an ed25519 key")
+			}
+			key.verifyKey = edPublic
+		}
+	default:
+		return nil, fmt.Errorf("unsupported algorithm %q", cfg.Algorithm)
+	}
+
+	if key.verifyKey == nil {
+		return nil, errors.New("neither private nor public key is set")
+	}
+
+	return key, nil
+}
+
+func parsePEM(data []byte, parse func([]byte) (interface{}, error)) (interface{}, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("invalid PEM data")
+	}
+
+	return parse(block.Bytes)
+}
+
+// Sign signs claims with the current signing key and sets the kid header.
+func (s *KeySet) Sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(s.signing.method, claims)
+	token.Header["kid"] = s.signing.id
+
+	return token.SignedString(s.signing.signKey)
+}
+
+// Keyfunc resolves the verification key by the kid header. Tokens issued
+// before key ids were introduced have no kid and are checked with the signing key.
+func (s *KeySet) Keyfunc(token *jwt.Token) (interface{}, error) {
+	key := s.signing
+	if kid, ok := token.Header["kid"]; ok {
+		id, _ := kid.(string)
+		if key, ok = s.keys[id]; !ok {
+			return nil, fmt.Errorf("unknown key id %q", id)
+		}
+	}
+
+	if token.Method.Alg() != key.method.Alg() {
+		return nil, errors.New("invalid signing method")
+	}
+
+	return key.verifyKey, nil
+}
+
+// JWKS returns the public keys of the set. Symmetric keys are never published.
+func (s *KeySet) JWKS() JWKS {
+	jwks := JWKS{Keys: make([]JWK, 0, len(s.keys))}
+
+	for _, key := range s.keys {
+		switch public := key.verifyKey.(type) {
+		case *rsa.PublicKey:
+			jwks.Keys = append(jwks.Keys, JWK{
+				Kty: "RSA",
+				Kid: key.id,
+				Use: "sig",
+				Alg: key.method.Alg(),
+				N:   base64.RawURLEncoding.EncodeToString(public.N.Bytes()),
+				E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes()),
+			})
+		case ed25519.PublicKey:
+			jwks.Keys = append(jwks.Keys, JWK{
+				Kty: "OKP",
+				Kid: key.id,
+				Use: "sig",
+				Alg: key.method.Alg(),
+				Crv: "Ed25519",
+				X:   base64.RawURLEncoding.EncodeToString(public),
+			})
+		}
+	}
+
+	return jwks
+}
+
+type signingMethodEdDSA struct{}
+
+// SigningMethodEdDSA implements the EdDSA (Ed25519) algorithm of RFC 8037,
+// which jwt-go does not ship.
+var SigningMethodEdDSA = &signingMethodEdDSA{}
+
+func init() {
+	jwt.RegisterSigningMethod(SigningMethodEdDSA.Alg(), func() jwt.SigningMethod {
+		return SigningMethodEdDSA
+	})
+}
+
+func (m *signingMethodEdDSA) Alg() string {
+	return "EdDSA"
+}
+
+func (m *signingMethodEdDSA) Verify(signingString, signature string, key interface{}) error {
+	public, ok := key.(ed25519.PublicKey)
+	if !ok {
+		return jwt.ErrInvalidKeyType
+	}
+
+	sig, err := jwt.DecodeSegment(signature)
+	if err != nil {
+		return err
+	}
+
+	if !ed25519.Verify(public, []byte(signingString), sig) {
+		return jwt.ErrSignatureInvalid
+	}
+
+	return nil
+}
+
+func (m *signingMethodEdDSA) Sign(signingString string, key interface{}) (string, error) {
+	private, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return "", jwt.ErrInvalidKeyType
+	}
+
+	sig, err := private.Sign(nil, []byte(signingString), crypto.Hash(0))
+	if err != nil {
+		return "", err
+	}
+
+	return jwt.EncodeSegment(sig), nil
+}
@@ -0,0 +1,92 @@
+package service
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func generatePEMKeys(t *testing.T, private interface{}, public interface{}) ([]byte, []byte) {
+	privateDER, err := x509.MarshalPKCS8PrivateKey(private)
+	assert.NoError(t, err)
+	publicDER, err := x509.MarshalPKIXPublicKey(public)
+	assert.NoError(t, err)
+
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateDER}),
+		pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER})
+}
+
+func TestKeySet_rotation(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	rsaPrivate, rsaPublic := generatePEMKeys(t, rsaKey, &rsaKey.PublicKey)
+
+	edPublicKey, edPrivateKey, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+	edPrivate, edPublic := generatePEMKeys(t, edPrivateKey, edPublicKey)
+
+	configs := []KeyConfig{
+		{Id: "hs", Algorithm: "HS256", Secret: []byte("secret")},
+		{Id: "rs", Algorithm: "RS256", PrivateKey: rsaPrivate},
+		{Id: "ed", Algorithm: "EdDSA", PrivateKey: edPrivate},
+	}
+
+	for _, signingKeyId := range []string{"hs", "rs", "ed"} {
+		t.Run(signingKeyId, func(t *testing.T) {
+			old, err := NewKeySet(signingKeyId, configs)
+			assert.NoError(t, err)
+
+			token, err := old.Sign(&tokenClaims{UserId: 1})
+			assert.NoError(t, err)
+
+			// the next deployment signs with a new key but still verifies the old one
+			rotated, err := NewKeySet("new", append([]KeyConfig{
+				{Id: "new", Algorithm: "HS256", Secret: []byte("new secret")},
+				{Id: "rs", Algorithm: "RS256", PublicKey: rsaPublic},
+				{Id: "ed", Algorithm: "EdDSA", PublicKey: edPublic},
+			}, configs[0]))
+			assert.NoError(t, err)
+
+			claims := &tokenClaims{}
+			_, err = jwt.ParseWithClaims(token, claims, rotated.Keyfunc)
+			assert.NoError(t, err)
+			assert.Equal(t, 1, claims.UserId)
+		})
+	}
+}
+
+func TestKeySet_JWKS(t *testing.T) {
+	edPublicKey, edPrivateKey, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+	edPrivate, _ := generatePEMKeys(t, edPrivateKey, edPublicKey)
+
+	keys, err := NewKeySet("hs", []KeyConfig{
+		{Id: "hs", Algorithm: "HS256", Secret: []byte("secret")},
+		{Id: "ed", Algorithm: "EdDSA", PrivateKey: edPrivate},
+	})
+	assert.NoError(t, err)
+
+	jwks := keys.JWKS()
+	assert.Len(t, jwks.Keys, 1)
+	assert.Equal(t, "ed", jwks.Keys[0].Kid)
+	assert.Equal(t, "OKP", jwks.Keys[0].Kty)
+}
+
+func TestKeySet_algorithmMismatch(t *testing.T) {
+	keys, err := NewKeySet("hs", []KeyConfig{{Id: "hs", Algorithm: "HS256", Secret: []byte("secret")}})
+	assert.NoError(t, err)
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS512, &tokenClaims{UserId: 1})
+	token.Header["kid"] = "hs"
+	signed, err := token.SignedString([]byte("secret"))
+	assert.NoError(t, err)
+
+	_, err = jwt.ParseWithClaims(signed, &tokenClaims{}, keys.Keyfunc)
+	assert.Error(t, err)
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"github.com/lypolix/todo-app"
+	"github.com/lypolix/todo-app/pkg/repository"
+)
+
+type LabelService struct {
+	repo repository.Label
+}
+
+func NewLabelService(repo repository.Label) *LabelService {
+	return &LabelService{repo: repo}
+}
+
+func (s *LabelService) Create(ctx context.Context, userId int, label todo.Label) (int, error) {
+	if err := label.Validate(); err != nil {
+		return 0, err
+	}
+
+	label.Name = strings.TrimSpace(label.Name)
+	if label.Color == "" {
+		label.Color = todo.DefaultLabelColor
+	}
+
+	return s.repo.Create(ctx, userId, label)
+}
+
+func (s *LabelService) GetAll(ctx context.Context, userId int) ([]todo.Label, error) {
+	return s.repo.GetAll(ctx, userId)
+}
+
+func (s *LabelService) Update(ctx context.Context, userId, labelId int, input todo.UpdateLabelInput) error {
+	if err := input.Validate(); err != nil {
+		return err
+	}
+
+	if input.Name != nil {
+		name := strings.TrimSpace(*input.Name)
+		input.Name = &name
+	}
+
+	return s.repo.Update(ctx, userId, labelId, input)
+}
+
+func (s *LabelService) Delete(ctx context.Context, userId, labelId int) error {
+	return s.repo.Delete(ctx, userId, labelId)
+}
+
+func (s *LabelService) Attach(ctx context.Context, userId, itemId, labelId int) error {
+	return s.repo.Attach(ctx, userId, itemId, labelId)
+}
+
+func (s *LabelService) Detach(ctx context.Context, userId, itemId, labelId int) error {
+	return s.repo.Detach(ctx, userId, itemId, labelId)
+}
+
+// normalizeTags makes tag queries case-insensitive, like label names are
+// unique regardless of case, and drops duplicates and blanks.
+func normalizeTags(tags []string) []string {
+	normalized := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+
+	return normalized
+}
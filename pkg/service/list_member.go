@@ -0,0 +1,41 @@
+package service
+
+import (
+	"context"
+	"github.com/lypolix/todo-app"
+	"github.com/lypolix/todo-app/pkg/repository"
+)
+
+type ListMemberService struct {
+	repo repository.ListMember
+}
+
+func NewListMemberService(repo repository.ListMember) *ListMemberService {
+	return &ListMemberService{repo: repo}
+}
+
+func (s *ListMemberService) Add(ctx context.Context, userId, listId int, input todo.AddMemberInput) error {
+	if err := input.Validate(); err != nil {
+		return err
+	}
+	return s.repo.Add(ctx, userId, listId, input)
+}
+
+func (s *ListMemberService) GetAll(ctx context.Context, userId, listId int) ([]todo.ListMember, error) {
+	return s.repo.GetAll(ctx, userId, listId)
+}
+
+func (s *ListMemberService) UpdateRole(ctx context.Context, userId, listId, memberId int, input todo.UpdateMemberInput) error {
+	if err := input.Validate(); err != nil {
+		return err
+	}
+	return s.repo.UpdateRole(ctx, userId, listId, memberId, input)
+}
+
+func (s *ListMemberService) Delete(ctx context.Context, userId, listId, memberId int) error {
+	return s.repo.Delete(ctx, userId, listId, memberId)
+}
+
+func (s *ListMemberService) TransferOwnership(ctx context.Context, userId, listId, newOwnerId int) error {
+	return s.repo.TransferOwnership(ctx, userId, listId, newOwnerId)
+}
@@ -5,10 +5,14 @@
 package mock_service
 
 import (
+	context "context"
+	io "io"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 	todo "github.com/lypolix/todo-app"
+	service "github.com/lypolix/todo-app/pkg/service"
 )
 
 // MockAuthorization is a mock of Authorization interface.
@@ -34,49 +38,121 @@ func (m *MockAuthorization) EXPECT() *MockAuthorizationMockRecorder {
 	return m.recorder
 }
 
+// Authenticate mocks base method.
+func (m *MockAuthorization) Authenticate(ctx context.Context, username, password string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Authenticate", ctx, username, password)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Authenticate indicates an expected call of Authenticate.
+func (mr *MockAuthorizationMockRecorder) Authenticate(ctx, username, password interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Authenticate", reflect.TypeOf((*MockAuthorization)(nil).Authenticate), ctx, username, password)
+}
+
 // CreateUser mocks base method.
-func (m *MockAuthorization) CreateUser(user todo.User) (int, error) {
+func (m *MockAuthorization) CreateUser(ctx context.Context, user todo.User) (int, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateUser", user)
+	ret := m.ctrl.Call(m, "CreateUser", ctx, user)
 	ret0, _ := ret[0].(int)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CreateUser indicates an expected call of CreateUser.
-func (mr *MockAuthorizationMockRecorder) CreateUser(user interface{}) *gomock.Call {
+func (mr *MockAuthorizationMockRecorder) CreateUser(ctx, user interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockAuthorization)(nil).CreateUser), user)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockAuthorization)(nil).CreateUser), ctx, user)
 }
 
 // GenerateToken mocks base method.
-func (m *MockAuthorization) GenerateToken(username, password string) (string, error) {
+func (m *MockAuthorization) GenerateToken(ctx context.Context, username, password string) (todo.Tokens, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GenerateToken", username, password)
-	ret0, _ := ret[0].(string)
+	ret := m.ctrl.Call(m, "GenerateToken", ctx, username, password)
+	ret0, _ := ret[0].(todo.Tokens)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GenerateToken indicates an expected call of GenerateToken.
-func (mr *MockAuthorizationMockRecorder) GenerateToken(username, password interface{}) *gomock.Call {
+func (mr *MockAuthorizationMockRecorder) GenerateToken(ctx, username, password interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateToken", reflect.TypeOf((*MockAuthorization)(nil).GenerateToken), ctx, username, password)
+}
+
+// JWKS mocks base method.
+func (m *MockAuthorization) JWKS() service.JWKS {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "JWKS")
+	ret0, _ := ret[0].(service.JWKS)
+	return ret0
+}
+
+// JWKS indicates an expected call of JWKS.
+func (mr *MockAuthorizationMockRecorder) JWKS() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "JWKS", reflect.TypeOf((*MockAuthorization)(nil).JWKS))
+}
+
+// Logout mocks base method.
+func (m *MockAuthorization) Logout(ctx context.Context, accessToken, refreshToken string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Logout", ctx, accessToken, refreshToken)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Logout indicates an expected call of Logout.
+func (mr *MockAuthorizationMockRecorder) Logout(ctx, accessToken, refreshToken interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Logout", reflect.TypeOf((*MockAuthorization)(nil).Logout), ctx, accessToken, refreshToken)
+}
+
+// LogoutAll mocks base method.
+func (m *MockAuthorization) LogoutAll(ctx context.Context, userId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LogoutAll", ctx, userId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LogoutAll indicates an expected call of LogoutAll.
+func (mr *MockAuthorizationMockRecorder) LogoutAll(ctx, userId interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateToken", reflect.TypeOf((*MockAuthorization)(nil).GenerateToken), username, password)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LogoutAll", reflect.TypeOf((*MockAuthorization)(nil).LogoutAll), ctx, userId)
 }
 
 // ParseToken mocks base method.
-func (m *MockAuthorization) ParseToken(token string) (int, error) {
+func (m *MockAuthorization) ParseToken(ctx context.Context, token string) (int, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ParseToken", token)
+	ret := m.ctrl.Call(m, "ParseToken", ctx, token)
 	ret0, _ := ret[0].(int)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // ParseToken indicates an expected call of ParseToken.
-func (mr *MockAuthorizationMockRecorder) ParseToken(token interface{}) *gomock.Call {
+func (mr *MockAuthorizationMockRecorder) ParseToken(ctx, token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ParseToken", reflect.TypeOf((*MockAuthorization)(nil).ParseToken), ctx, token)
+}
+
+// RefreshToken mocks base method.
+func (m *MockAuthorization) RefreshToken(ctx context.Context, refreshToken string) (todo.Tokens, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RefreshToken", ctx, refreshToken)
+	ret0, _ := ret[0].(todo.Tokens)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RefreshToken indicates an expected call of RefreshToken.
+func (mr *MockAuthorizationMockRecorder) RefreshToken(ctx, refreshToken interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ParseToken", reflect.TypeOf((*MockAuthorization)(nil).ParseToken), token)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshToken", reflect.TypeOf((*MockAuthorization)(nil).RefreshToken), ctx, refreshToken)
 }
 
 // MockTodoList is a mock of TodoList interface.
@@ -103,76 +179,76 @@ func (m *MockTodoList) EXPECT() *MockTodoListMockRecorder {
 }
 
 // Create mocks base method.
-func (m *MockTodoList) Create(userId int, list todo.TodoList) (int, error) {
+func (m *MockTodoList) Create(ctx context.Context, userId int, list todo.TodoList) (int, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Create", userId, list)
+	ret := m.ctrl.Call(m, "Create", ctx, userId, list)
 	ret0, _ := ret[0].(int)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Create indicates an expected call of Create.
-func (mr *MockTodoListMockRecorder) Create(userId, list interface{}) *gomock.Call {
+func (mr *MockTodoListMockRecorder) Create(ctx, userId, list interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockTodoList)(nil).Create), userId, list)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockTodoList)(nil).Create), ctx, userId, list)
 }
 
 // Delete mocks base method.
-func (m *MockTodoList) Delete(userId, listId int) error {
+func (m *MockTodoList) Delete(ctx context.Context, userId, listId int, version *int) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Delete", userId, listId)
+	ret := m.ctrl.Call(m, "Delete", ctx, userId, listId, version)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Delete indicates an expected call of Delete.
-func (mr *MockTodoListMockRecorder) Delete(userId, listId interface{}) *gomock.Call {
+func (mr *MockTodoListMockRecorder) Delete(ctx, userId, listId, version interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockTodoList)(nil).Delete), userId, listId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockTodoList)(nil).Delete), ctx, userId, listId, version)
 }
 
 // GetAll mocks base method.
-func (m *MockTodoList) GetAll(userId int) ([]todo.TodoList, error) {
+func (m *MockTodoList) GetAll(ctx context.Context, userId int) ([]todo.TodoList, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAll", userId)
+	ret := m.ctrl.Call(m, "GetAll", ctx, userId)
 	ret0, _ := ret[0].([]todo.TodoList)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetAll indicates an expected call of GetAll.
-func (mr *MockTodoListMockRecorder) GetAll(userId interface{}) *gomock.Call {
+func (mr *MockTodoListMockRecorder) GetAll(ctx, userId interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockTodoList)(nil).GetAll), userId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockTodoList)(nil).GetAll), ctx, userId)
 }
 
 // GetById mocks base method.
-func (m *MockTodoList) GetById(userId, listId int) (todo.TodoList, error) {
+func (m *MockTodoList) GetById(ctx context.Context, userId, listId int) (todo.TodoList, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetById", userId, listId)
+	ret := m.ctrl.Call(m, "GetById", ctx, userId, listId)
 	ret0, _ := ret[0].(todo.TodoList)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetById indicates an expected call of GetById.
-func (mr *MockTodoListMockRecorder) GetById(userId, listId interface{}) *gomock.Call {
+func (mr *MockTodoListMockRecorder) GetById(ctx, userId, listId interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetById", reflect.TypeOf((*MockTodoList)(nil).GetById), userId, listId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetById", reflect.TypeOf((*MockTodoList)(nil).GetById), ctx, userId, listId)
 }
 
 // Update mocks base method.
-func (m *MockTodoList) Update(userId, listId int, input todo.UpdateListInput) error {
+func (m *MockTodoList) Update(ctx context.Context, userId, listId int, input todo.UpdateListInput) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", userId, listId, input)
+	ret := m.ctrl.Call(m, "Update", ctx, userId, listId, input)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Update indicates an expected call of Update.
-func (mr *MockTodoListMockRecorder) Update(userId, listId, input interface{}) *gomock.Call {
+func (mr *MockTodoListMockRecorder) Update(ctx, userId, listId, input interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockTodoList)(nil).Update), userId, listId, input)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockTodoList)(nil).Update), ctx, userId, listId, input)
 }
 
 // MockTodoItem is a mock of TodoItem interface.
@@ -199,74 +275,1004 @@ func (m *MockTodoItem) EXPECT() *MockTodoItemMockRecorder {
 }
 
 // Create mocks base method.
-func (m *MockTodoItem) Create(userId, listId int, item todo.TodoItem) (int, error) {
+func (m *MockTodoItem) Create(ctx context.Context, userId, listId int, input todo.CreateItemInput) (int, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Create", userId, listId, item)
+	ret := m.ctrl.Call(m, "Create", ctx, userId, listId, input)
 	ret0, _ := ret[0].(int)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Create indicates an expected call of Create.
-func (mr *MockTodoItemMockRecorder) Create(userId, listId, item interface{}) *gomock.Call {
+func (mr *MockTodoItemMockRecorder) Create(ctx, userId, listId, input interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockTodoItem)(nil).Create), userId, listId, item)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockTodoItem)(nil).Create), ctx, userId, listId, input)
 }
 
 // Delete mocks base method.
-func (m *MockTodoItem) Delete(userId, itemId int) error {
+func (m *MockTodoItem) Delete(ctx context.Context, userId, itemId int, version *int) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Delete", userId, itemId)
+	ret := m.ctrl.Call(m, "Delete", ctx, userId, itemId, version)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Delete indicates an expected call of Delete.
-func (mr *MockTodoItemMockRecorder) Delete(userId, itemId interface{}) *gomock.Call {
+func (mr *MockTodoItemMockRecorder) Delete(ctx, userId, itemId, version interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockTodoItem)(nil).Delete), userId, itemId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockTodoItem)(nil).Delete), ctx, userId, itemId, version)
 }
 
-// GetAll mocks base method.
-func (m *MockTodoItem) GetAll(userId, listId int) ([]todo.TodoItem, error) {
+// Find mocks base method.
+func (m *MockTodoItem) Find(ctx context.Context, userId int, filter todo.ItemFilter) ([]todo.TodoItem, string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAll", userId, listId)
+	ret := m.ctrl.Call(m, "Find", ctx, userId, filter)
 	ret0, _ := ret[0].([]todo.TodoItem)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Find indicates an expected call of Find.
+func (mr *MockTodoItemMockRecorder) Find(ctx, userId, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Find", reflect.TypeOf((*MockTodoItem)(nil).Find), ctx, userId, filter)
+}
+
+// GetAgenda mocks base method.
+func (m *MockTodoItem) GetAgenda(ctx context.Context, userId int, now time.Time) (todo.Agenda, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAgenda", ctx, userId, now)
+	ret0, _ := ret[0].(todo.Agenda)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
+// GetAgenda indicates an expected call of GetAgenda.
+func (mr *MockTodoItemMockRecorder) GetAgenda(ctx, userId, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAgenda", reflect.TypeOf((*MockTodoItem)(nil).GetAgenda), ctx, userId, now)
+}
+
+// GetAll mocks base method.
+func (m *MockTodoItem) GetAll(ctx context.Context, userId, listId int, filter todo.ItemFilter) ([]todo.TodoItem, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", ctx, userId, listId, filter)
+	ret0, _ := ret[0].([]todo.TodoItem)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
 // GetAll indicates an expected call of GetAll.
-func (mr *MockTodoItemMockRecorder) GetAll(userId, listId interface{}) *gomock.Call {
+func (mr *MockTodoItemMockRecorder) GetAll(ctx, userId, listId, filter interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockTodoItem)(nil).GetAll), userId, listId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockTodoItem)(nil).GetAll), ctx, userId, listId, filter)
 }
 
 // GetById mocks base method.
-func (m *MockTodoItem) GetById(userId, itemId int) (todo.TodoItem, error) {
+func (m *MockTodoItem) GetById(ctx context.Context, userId, itemId int) (todo.TodoItem, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetById", userId, itemId)
+	ret := m.ctrl.Call(m, "GetById", ctx, userId, itemId)
 	ret0, _ := ret[0].(todo.TodoItem)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetById indicates an expected call of GetById.
-func (mr *MockTodoItemMockRecorder) GetById(userId, itemId interface{}) *gomock.Call {
+func (mr *MockTodoItemMockRecorder) GetById(ctx, userId, itemId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetById", reflect.TypeOf((*MockTodoItem)(nil).GetById), ctx, userId, itemId)
+}
+
+// GetDueBetween mocks base method.
+func (m *MockTodoItem) GetDueBetween(ctx context.Context, from, to time.Time) ([]todo.UserItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDueBetween", ctx, from, to)
+	ret0, _ := ret[0].([]todo.UserItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDueBetween indicates an expected call of GetDueBetween.
+func (mr *MockTodoItemMockRecorder) GetDueBetween(ctx, from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDueBetween", reflect.TypeOf((*MockTodoItem)(nil).GetDueBetween), ctx, from, to)
+}
+
+// Move mocks base method.
+func (m *MockTodoItem) Move(ctx context.Context, userId, itemId int, input todo.MoveItemInput) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Move", ctx, userId, itemId, input)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Move indicates an expected call of Move.
+func (mr *MockTodoItemMockRecorder) Move(ctx, userId, itemId, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Move", reflect.TypeOf((*MockTodoItem)(nil).Move), ctx, userId, itemId, input)
+}
+
+// Update mocks base method.
+func (m *MockTodoItem) Update(ctx context.Context, userId, itemId int, input todo.UpdateItemInput) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, userId, itemId, input)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockTodoItemMockRecorder) Update(ctx, userId, itemId, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockTodoItem)(nil).Update), ctx, userId, itemId, input)
+}
+
+// MockSubtask is a mock of Subtask interface.
+type MockSubtask struct {
+	ctrl     *gomock.Controller
+	recorder *MockSubtaskMockRecorder
+}
+
+// MockSubtaskMockRecorder is the mock recorder for MockSubtask.
+type MockSubtaskMockRecorder struct {
+	mock *MockSubtask
+}
+
+// NewMockSubtask creates a new mock instance.
+func NewMockSubtask(ctrl *gomock.Controller) *MockSubtask {
+	mock := &MockSubtask{ctrl: ctrl}
+	mock.recorder = &MockSubtaskMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSubtask) EXPECT() *MockSubtaskMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockSubtask) Create(ctx context.Context, userId, itemId int, subtask todo.Subtask) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, userId, itemId, subtask)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockSubtaskMockRecorder) Create(ctx, userId, itemId, subtask interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockSubtask)(nil).Create), ctx, userId, itemId, subtask)
+}
+
+// Delete mocks base method.
+func (m *MockSubtask) Delete(ctx context.Context, userId, itemId, subtaskId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, userId, itemId, subtaskId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockSubtaskMockRecorder) Delete(ctx, userId, itemId, subtaskId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockSubtask)(nil).Delete), ctx, userId, itemId, subtaskId)
+}
+
+// GetAll mocks base method.
+func (m *MockSubtask) GetAll(ctx context.Context, userId, itemId int) ([]todo.Subtask, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", ctx, userId, itemId)
+	ret0, _ := ret[0].([]todo.Subtask)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockSubtaskMockRecorder) GetAll(ctx, userId, itemId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockSubtask)(nil).GetAll), ctx, userId, itemId)
+}
+
+// Update mocks base method.
+func (m *MockSubtask) Update(ctx context.Context, userId, itemId, subtaskId int, input todo.UpdateSubtaskInput) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, userId, itemId, subtaskId, input)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockSubtaskMockRecorder) Update(ctx, userId, itemId, subtaskId, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockSubtask)(nil).Update), ctx, userId, itemId, subtaskId, input)
+}
+
+// MockComment is a mock of Comment interface.
+type MockComment struct {
+	ctrl     *gomock.Controller
+	recorder *MockCommentMockRecorder
+}
+
+// MockCommentMockRecorder is the mock recorder for MockComment.
+type MockCommentMockRecorder struct {
+	mock *MockComment
+}
+
+// NewMockComment creates a new mock instance.
+func NewMockComment(ctrl *gomock.Controller) *MockComment {
+	mock := &MockComment{ctrl: ctrl}
+	mock.recorder = &MockCommentMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockComment) EXPECT() *MockCommentMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockComment) Create(ctx context.Context, userId, itemId int, comment todo.Comment) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, userId, itemId, comment)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockCommentMockRecorder) Create(ctx, userId, itemId, comment interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockComment)(nil).Create), ctx, userId, itemId, comment)
+}
+
+// Delete mocks base method.
+func (m *MockComment) Delete(ctx context.Context, userId, itemId, commentId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, userId, itemId, commentId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockCommentMockRecorder) Delete(ctx, userId, itemId, commentId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockComment)(nil).Delete), ctx, userId, itemId, commentId)
+}
+
+// GetAll mocks base method.
+func (m *MockComment) GetAll(ctx context.Context, userId, itemId int) ([]todo.Comment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", ctx, userId, itemId)
+	ret0, _ := ret[0].([]todo.Comment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockCommentMockRecorder) GetAll(ctx, userId, itemId interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetById", reflect.TypeOf((*MockTodoItem)(nil).GetById), userId, itemId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockComment)(nil).GetAll), ctx, userId, itemId)
 }
 
 // Update mocks base method.
-func (m *MockTodoItem) Update(userId, itemId int, input todo.UpdateItemInput) error {
+func (m *MockComment) Update(ctx context.Context, userId, itemId, commentId int, input todo.UpdateCommentInput) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", userId, itemId, input)
+	ret := m.ctrl.Call(m, "Update", ctx, userId, itemId, commentId, input)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Update indicates an expected call of Update.
-func (mr *MockTodoItemMockRecorder) Update(userId, itemId, input interface{}) *gomock.Call {
+func (mr *MockCommentMockRecorder) Update(ctx, userId, itemId, commentId, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockComment)(nil).Update), ctx, userId, itemId, commentId, input)
+}
+
+// MockAttachment is a mock of Attachment interface.
+type MockAttachment struct {
+	ctrl     *gomock.Controller
+	recorder *MockAttachmentMockRecorder
+}
+
+// MockAttachmentMockRecorder is the mock recorder for MockAttachment.
+type MockAttachmentMockRecorder struct {
+	mock *MockAttachment
+}
+
+// NewMockAttachment creates a new mock instance.
+func NewMockAttachment(ctrl *gomock.Controller) *MockAttachment {
+	mock := &MockAttachment{ctrl: ctrl}
+	mock.recorder = &MockAttachmentMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAttachment) EXPECT() *MockAttachmentMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockAttachment) Create(ctx context.Context, userId, itemId int, attachment todo.Attachment, content io.Reader) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, userId, itemId, attachment, content)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockAttachmentMockRecorder) Create(ctx, userId, itemId, attachment, content interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockAttachment)(nil).Create), ctx, userId, itemId, attachment, content)
+}
+
+// Delete mocks base method.
+func (m *MockAttachment) Delete(ctx context.Context, userId, itemId, attachmentId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, userId, itemId, attachmentId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockAttachmentMockRecorder) Delete(ctx, userId, itemId, attachmentId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockAttachment)(nil).Delete), ctx, userId, itemId, attachmentId)
+}
+
+// GetAll mocks base method.
+func (m *MockAttachment) GetAll(ctx context.Context, userId, itemId int) ([]todo.Attachment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", ctx, userId, itemId)
+	ret0, _ := ret[0].([]todo.Attachment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockAttachmentMockRecorder) GetAll(ctx, userId, itemId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockAttachment)(nil).GetAll), ctx, userId, itemId)
+}
+
+// Open mocks base method.
+func (m *MockAttachment) Open(ctx context.Context, userId, itemId, attachmentId int) (todo.Attachment, io.ReadCloser, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Open", ctx, userId, itemId, attachmentId)
+	ret0, _ := ret[0].(todo.Attachment)
+	ret1, _ := ret[1].(io.ReadCloser)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Open indicates an expected call of Open.
+func (mr *MockAttachmentMockRecorder) Open(ctx, userId, itemId, attachmentId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Open", reflect.TypeOf((*MockAttachment)(nil).Open), ctx, userId, itemId, attachmentId)
+}
+
+// PurgeDetached mocks base method.
+func (m *MockAttachment) PurgeDetached(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeDetached", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PurgeDetached indicates an expected call of PurgeDetached.
+func (mr *MockAttachmentMockRecorder) PurgeDetached(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeDetached", reflect.TypeOf((*MockAttachment)(nil).PurgeDetached), ctx)
+}
+
+// MockLabel is a mock of Label interface.
+type MockLabel struct {
+	ctrl     *gomock.Controller
+	recorder *MockLabelMockRecorder
+}
+
+// MockLabelMockRecorder is the mock recorder for MockLabel.
+type MockLabelMockRecorder struct {
+	mock *MockLabel
+}
+
+// NewMockLabel creates a new mock instance.
+func NewMockLabel(ctrl *gomock.Controller) *MockLabel {
+	mock := &MockLabel{ctrl: ctrl}
+	mock.recorder = &MockLabelMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLabel) EXPECT() *MockLabelMockRecorder {
+	return m.recorder
+}
+
+// Attach mocks base method.
+func (m *MockLabel) Attach(ctx context.Context, userId, itemId, labelId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Attach", ctx, userId, itemId, labelId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Attach indicates an expected call of Attach.
+func (mr *MockLabelMockRecorder) Attach(ctx, userId, itemId, labelId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Attach", reflect.TypeOf((*MockLabel)(nil).Attach), ctx, userId, itemId, labelId)
+}
+
+// Create mocks base method.
+func (m *MockLabel) Create(ctx context.Context, userId int, label todo.Label) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, userId, label)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockLabelMockRecorder) Create(ctx, userId, label interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockLabel)(nil).Create), ctx, userId, label)
+}
+
+// Delete mocks base method.
+func (m *MockLabel) Delete(ctx context.Context, userId, labelId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, userId, labelId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockLabelMockRecorder) Delete(ctx, userId, labelId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockLabel)(nil).Delete), ctx, userId, labelId)
+}
+
+// Detach mocks base method.
+func (m *MockLabel) Detach(ctx context.Context, userId, itemId, labelId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Detach", ctx, userId, itemId, labelId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Detach indicates an expected call of Detach.
+func (mr *MockLabelMockRecorder) Detach(ctx, userId, itemId, labelId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Detach", reflect.TypeOf((*MockLabel)(nil).Detach), ctx, userId, itemId, labelId)
+}
+
+// GetAll mocks base method.
+func (m *MockLabel) GetAll(ctx context.Context, userId int) ([]todo.Label, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", ctx, userId)
+	ret0, _ := ret[0].([]todo.Label)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockLabelMockRecorder) GetAll(ctx, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockLabel)(nil).GetAll), ctx, userId)
+}
+
+// Update mocks base method.
+func (m *MockLabel) Update(ctx context.Context, userId, labelId int, input todo.UpdateLabelInput) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, userId, labelId, input)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockLabelMockRecorder) Update(ctx, userId, labelId, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockLabel)(nil).Update), ctx, userId, labelId, input)
+}
+
+// MockTrash is a mock of Trash interface.
+type MockTrash struct {
+	ctrl     *gomock.Controller
+	recorder *MockTrashMockRecorder
+}
+
+// MockTrashMockRecorder is the mock recorder for MockTrash.
+type MockTrashMockRecorder struct {
+	mock *MockTrash
+}
+
+// NewMockTrash creates a new mock instance.
+func NewMockTrash(ctrl *gomock.Controller) *MockTrash {
+	mock := &MockTrash{ctrl: ctrl}
+	mock.recorder = &MockTrashMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTrash) EXPECT() *MockTrashMockRecorder {
+	return m.recorder
+}
+
+// GetAll mocks base method.
+func (m *MockTrash) GetAll(ctx context.Context, userId int) (todo.Trash, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", ctx, userId)
+	ret0, _ := ret[0].(todo.Trash)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockTrashMockRecorder) GetAll(ctx, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockTrash)(nil).GetAll), ctx, userId)
+}
+
+// Purge mocks base method.
+func (m *MockTrash) Purge(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Purge", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Purge indicates an expected call of Purge.
+func (mr *MockTrashMockRecorder) Purge(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Purge", reflect.TypeOf((*MockTrash)(nil).Purge), ctx)
+}
+
+// Restore mocks base method.
+func (m *MockTrash) Restore(ctx context.Context, userId int, kind string, id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", ctx, userId, kind, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockTrashMockRecorder) Restore(ctx, userId, kind, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockTrash)(nil).Restore), ctx, userId, kind, id)
+}
+
+// MockActivity is a mock of Activity interface.
+type MockActivity struct {
+	ctrl     *gomock.Controller
+	recorder *MockActivityMockRecorder
+}
+
+// MockActivityMockRecorder is the mock recorder for MockActivity.
+type MockActivityMockRecorder struct {
+	mock *MockActivity
+}
+
+// NewMockActivity creates a new mock instance.
+func NewMockActivity(ctrl *gomock.Controller) *MockActivity {
+	mock := &MockActivity{ctrl: ctrl}
+	mock.recorder = &MockActivityMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockActivity) EXPECT() *MockActivityMockRecorder {
+	return m.recorder
+}
+
+// GetByItem mocks base method.
+func (m *MockActivity) GetByItem(ctx context.Context, userId, itemId int, page todo.Page) ([]todo.Activity, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByItem", ctx, userId, itemId, page)
+	ret0, _ := ret[0].([]todo.Activity)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetByItem indicates an expected call of GetByItem.
+func (mr *MockActivityMockRecorder) GetByItem(ctx, userId, itemId, page interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByItem", reflect.TypeOf((*MockActivity)(nil).GetByItem), ctx, userId, itemId, page)
+}
+
+// GetByList mocks base method.
+func (m *MockActivity) GetByList(ctx context.Context, userId, listId int, page todo.Page) ([]todo.Activity, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByList", ctx, userId, listId, page)
+	ret0, _ := ret[0].([]todo.Activity)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetByList indicates an expected call of GetByList.
+func (mr *MockActivityMockRecorder) GetByList(ctx, userId, listId, page interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByList", reflect.TypeOf((*MockActivity)(nil).GetByList), ctx, userId, listId, page)
+}
+
+// MockExchange is a mock of Exchange interface.
+type MockExchange struct {
+	ctrl     *gomock.Controller
+	recorder *MockExchangeMockRecorder
+}
+
+// MockExchangeMockRecorder is the mock recorder for MockExchange.
+type MockExchangeMockRecorder struct {
+	mock *MockExchange
+}
+
+// NewMockExchange creates a new mock instance.
+func NewMockExchange(ctrl *gomock.Controller) *MockExchange {
+	mock := &MockExchange{ctrl: ctrl}
+	mock.recorder = &MockExchangeMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockExchange) EXPECT() *MockExchangeMockRecorder {
+	return m.recorder
+}
+
+// Export mocks base method.
+func (m *MockExchange) Export(ctx context.Context, userId, listId int, format string) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Export", ctx, userId, listId, format)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Export indicates an expected call of Export.
+func (mr *MockExchangeMockRecorder) Export(ctx, userId, listId, format interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Export", reflect.TypeOf((*MockExchange)(nil).Export), ctx, userId, listId, format)
+}
+
+// Import mocks base method.
+func (m *MockExchange) Import(ctx context.Context, userId int, input todo.ImportInput, data io.Reader) (todo.ImportResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Import", ctx, userId, input, data)
+	ret0, _ := ret[0].(todo.ImportResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Import indicates an expected call of Import.
+func (mr *MockExchangeMockRecorder) Import(ctx, userId, input, data interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Import", reflect.TypeOf((*MockExchange)(nil).Import), ctx, userId, input, data)
+}
+
+// MockCalendar is a mock of Calendar interface.
+type MockCalendar struct {
+	ctrl     *gomock.Controller
+	recorder *MockCalendarMockRecorder
+}
+
+// MockCalendarMockRecorder is the mock recorder for MockCalendar.
+type MockCalendarMockRecorder struct {
+	mock *MockCalendar
+}
+
+// NewMockCalendar creates a new mock instance.
+func NewMockCalendar(ctrl *gomock.Controller) *MockCalendar {
+	mock := &MockCalendar{ctrl: ctrl}
+	mock.recorder = &MockCalendarMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCalendar) EXPECT() *MockCalendarMockRecorder {
+	return m.recorder
+}
+
+// CreateToken mocks base method.
+func (m *MockCalendar) CreateToken(ctx context.Context, userId int) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateToken", ctx, userId)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateToken indicates an expected call of CreateToken.
+func (mr *MockCalendarMockRecorder) CreateToken(ctx, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateToken", reflect.TypeOf((*MockCalendar)(nil).CreateToken), ctx, userId)
+}
+
+// DeleteToken mocks base method.
+func (m *MockCalendar) DeleteToken(ctx context.Context, userId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteToken", ctx, userId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteToken indicates an expected call of DeleteToken.
+func (mr *MockCalendarMockRecorder) DeleteToken(ctx, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteToken", reflect.TypeOf((*MockCalendar)(nil).DeleteToken), ctx, userId)
+}
+
+// Feed mocks base method.
+func (m *MockCalendar) Feed(ctx context.Context, token, component string) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Feed", ctx, token, component)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Feed indicates an expected call of Feed.
+func (mr *MockCalendarMockRecorder) Feed(ctx, token, component interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Feed", reflect.TypeOf((*MockCalendar)(nil).Feed), ctx, token, component)
+}
+
+// Import mocks base method.
+func (m *MockCalendar) Import(ctx context.Context, userId, listId int, data io.Reader) ([]int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Import", ctx, userId, listId, data)
+	ret0, _ := ret[0].([]int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Import indicates an expected call of Import.
+func (mr *MockCalendarMockRecorder) Import(ctx, userId, listId, data interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Import", reflect.TypeOf((*MockCalendar)(nil).Import), ctx, userId, listId, data)
+}
+
+// MockCalDAV is a mock of CalDAV interface.
+type MockCalDAV struct {
+	ctrl     *gomock.Controller
+	recorder *MockCalDAVMockRecorder
+}
+
+// MockCalDAVMockRecorder is the mock recorder for MockCalDAV.
+type MockCalDAVMockRecorder struct {
+	mock *MockCalDAV
+}
+
+// NewMockCalDAV creates a new mock instance.
+func NewMockCalDAV(ctrl *gomock.Controller) *MockCalDAV {
+	mock := &MockCalDAV{ctrl: ctrl}
+	mock.recorder = &MockCalDAVMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCalDAV) EXPECT() *MockCalDAVMockRecorder {
+	return m.recorder
+}
+
+// DeleteObject mocks base method.
+func (m *MockCalDAV) DeleteObject(ctx context.Context, userId, listId int, name string, cond todo.Precondition) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteObject", ctx, userId, listId, name, cond)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteObject indicates an expected call of DeleteObject.
+func (mr *MockCalDAVMockRecorder) DeleteObject(ctx, userId, listId, name, cond interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteObject", reflect.TypeOf((*MockCalDAV)(nil).DeleteObject), ctx, userId, listId, name, cond)
+}
+
+// GetCalendar mocks base method.
+func (m *MockCalDAV) GetCalendar(ctx context.Context, userId, listId int) (todo.Calendar, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCalendar", ctx, userId, listId)
+	ret0, _ := ret[0].(todo.Calendar)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCalendar indicates an expected call of GetCalendar.
+func (mr *MockCalDAVMockRecorder) GetCalendar(ctx, userId, listId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCalendar", reflect.TypeOf((*MockCalDAV)(nil).GetCalendar), ctx, userId, listId)
+}
+
+// GetCalendars mocks base method.
+func (m *MockCalDAV) GetCalendars(ctx context.Context, userId int) ([]todo.Calendar, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCalendars", ctx, userId)
+	ret0, _ := ret[0].([]todo.Calendar)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCalendars indicates an expected call of GetCalendars.
+func (mr *MockCalDAVMockRecorder) GetCalendars(ctx, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCalendars", reflect.TypeOf((*MockCalDAV)(nil).GetCalendars), ctx, userId)
+}
+
+// GetObject mocks base method.
+func (m *MockCalDAV) GetObject(ctx context.Context, userId, listId int, name string) (todo.CalendarObject, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetObject", ctx, userId, listId, name)
+	ret0, _ := ret[0].(todo.CalendarObject)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetObject indicates an expected call of GetObject.
+func (mr *MockCalDAVMockRecorder) GetObject(ctx, userId, listId, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetObject", reflect.TypeOf((*MockCalDAV)(nil).GetObject), ctx, userId, listId, name)
+}
+
+// GetObjects mocks base method.
+func (m *MockCalDAV) GetObjects(ctx context.Context, userId, listId int) ([]todo.CalendarObject, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetObjects", ctx, userId, listId)
+	ret0, _ := ret[0].([]todo.CalendarObject)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetObjects indicates an expected call of GetObjects.
+func (mr *MockCalDAVMockRecorder) GetObjects(ctx, userId, listId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetObjects", reflect.TypeOf((*MockCalDAV)(nil).GetObjects), ctx, userId, listId)
+}
+
+// PutObject mocks base method.
+func (m *MockCalDAV) PutObject(ctx context.Context, userId, listId int, name string, data io.Reader, cond todo.Precondition) (todo.CalendarObject, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PutObject", ctx, userId, listId, name, data, cond)
+	ret0, _ := ret[0].(todo.CalendarObject)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// PutObject indicates an expected call of PutObject.
+func (mr *MockCalDAVMockRecorder) PutObject(ctx, userId, listId, name, data, cond interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutObject", reflect.TypeOf((*MockCalDAV)(nil).PutObject), ctx, userId, listId, name, data, cond)
+}
+
+// MockSearch is a mock of Search interface.
+type MockSearch struct {
+	ctrl     *gomock.Controller
+	recorder *MockSearchMockRecorder
+}
+
+// MockSearchMockRecorder is the mock recorder for MockSearch.
+type MockSearchMockRecorder struct {
+	mock *MockSearch
+}
+
+// NewMockSearch creates a new mock instance.
+func NewMockSearch(ctrl *gomock.Controller) *MockSearch {
+	mock := &MockSearch{ctrl: ctrl}
+	mock.recorder = &MockSearchMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSearch) EXPECT() *MockSearchMockRecorder {
+	return m.recorder
+}
+
+// Search mocks base method.
+func (m *MockSearch) Search(ctx context.Context, userId int, query todo.SearchQuery) ([]todo.SearchResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Search", ctx, userId, query)
+	ret0, _ := ret[0].([]todo.SearchResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Search indicates an expected call of Search.
+func (mr *MockSearchMockRecorder) Search(ctx, userId, query interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockSearch)(nil).Search), ctx, userId, query)
+}
+
+// MockListMember is a mock of ListMember interface.
+type MockListMember struct {
+	ctrl     *gomock.Controller
+	recorder *MockListMemberMockRecorder
+}
+
+// MockListMemberMockRecorder is the mock recorder for MockListMember.
+type MockListMemberMockRecorder struct {
+	mock *MockListMember
+}
+
+// NewMockListMember creates a new mock instance.
+func NewMockListMember(ctrl *gomock.Controller) *MockListMember {
+	mock := &MockListMember{ctrl: ctrl}
+	mock.recorder = &MockListMemberMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockListMember) EXPECT() *MockListMemberMockRecorder {
+	return m.recorder
+}
+
+// Add mocks base method.
+func (m *MockListMember) Add(ctx context.Context, userId, listId int, input todo.AddMemberInput) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Add", ctx, userId, listId, input)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Add indicates an expected call of Add.
+func (mr *MockListMemberMockRecorder) Add(ctx, userId, listId, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Add", reflect.TypeOf((*MockListMember)(nil).Add), ctx, userId, listId, input)
+}
+
+// Delete mocks base method.
+func (m *MockListMember) Delete(ctx context.Context, userId, listId, memberId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, userId, listId, memberId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockListMemberMockRecorder) Delete(ctx, userId, listId, memberId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockListMember)(nil).Delete), ctx, userId, listId, memberId)
+}
+
+// GetAll mocks base method.
+func (m *MockListMember) GetAll(ctx context.Context, userId, listId int) ([]todo.ListMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", ctx, userId, listId)
+	ret0, _ := ret[0].([]todo.ListMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockListMemberMockRecorder) GetAll(ctx, userId, listId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockListMember)(nil).GetAll), ctx, userId, listId)
+}
+
+// TransferOwnership mocks base method.
+func (m *MockListMember) TransferOwnership(ctx context.Context, userId, listId, newOwnerId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TransferOwnership", ctx, userId, listId, newOwnerId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// TransferOwnership indicates an expected call of TransferOwnership.
+func (mr *MockListMemberMockRecorder) TransferOwnership(ctx, userId, listId, newOwnerId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TransferOwnership", reflect.TypeOf((*MockListMember)(nil).TransferOwnership), ctx, userId, listId, newOwnerId)
+}
+
+// UpdateRole mocks base method.
+func (m *MockListMember) UpdateRole(ctx context.Context, userId, listId, memberId int, input todo.UpdateMemberInput) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateRole", ctx, userId, listId, memberId, input)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateRole indicates an expected call of UpdateRole.
+func (mr *MockListMemberMockRecorder) UpdateRole(ctx, userId, listId, memberId, input interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockTodoItem)(nil).Update), userId, itemId, input)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateRole", reflect.TypeOf((*MockListMember)(nil).UpdateRole), ctx, userId, listId, memberId, input)
 }
@@ -0,0 +1,177 @@
+package service
+
+import (
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+)
+
+// legacySalt is the static salt of the SHA1 hashes stored before argon2id.
+const legacySalt = "hjgrhjqw124617ajfhajs"
+
+var errUnknownHashFormat = errors.New("unknown password hash format")
+
+// PasswordHasher hashes passwords and verifies them against stored hashes.
+type PasswordHasher interface {
+	Hash(password string) (string, error)
+	Verify(password, hash string) (bool, error)
+	// NeedsRehash reports whether hash should be replaced by a fresh Hash result.
+	NeedsRehash(hash string) bool
+}
+
+// Argon2idHasher produces PHC formatted argon2id hashes with a random per-user salt:
+// $argon2id$v=19$m=65536,t=1,p=4$<salt>$<key>
+type Argon2idHasher struct {
+	Time    uint32
+	Memory  uint32
+	Threads uint8
+	SaltLen uint32
+	KeyLen  uint32
+}
+
+func NewArgon2idHasher() *Argon2idHasher {
+	return &Argon2idHasher{
+		Time:    1,
+		Memory:  64 * 1024,
+		Threads: 4,
+		SaltLen: 16,
+		KeyLen:  32,
+	}
+}
+
+type argon2idHash struct {
+	time    uint32
+	memory  uint32
+	threads uint8
+	salt    []byte
+	key     []byte
+}
+
+func (h *Argon2idHasher) Hash(password string) (string, error) {
+	salt := make([]byte, h.SaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	key := argon2.IDKey([]byte(password), salt, h.Time, h.Memory, h.Threads, h.KeyLen)
+
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s", argon2.Version, h.Memory, h.Time, h.Threads,
+		base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key)), nil
+}
+
+func (h *Argon2idHasher) Verify(password, hash string) (bool, error) {
+	decoded, err := decodeArgon2id(hash)
+	if err != nil {
+		return false, err
+	}
+
+	key := argon2.IDKey([]byte(password), decoded.salt, decoded.time, decoded.memory, decoded.threads, uint32(len(decoded.key)))
+
+	return subtle.ConstantTimeCompare(key, decoded.key) == 1, nil
+}
+
+func (h *Argon2idHasher) NeedsRehash(hash string) bool {
+	decoded, err := decodeArgon2id(hash)
+	if err != nil {
+		return true
+	}
+
+	return decoded.time != h.Time || decoded.memory != h.Memory || decoded.threads != h.Threads ||
+		uint32(len(decoded.salt)) != h.SaltLen || uint32(len(decoded.key)) != h.KeyLen
+}
+
+func decodeArgon2id(hash string) (argon2idHash, error) {
+	var decoded argon2idHash
+
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 || parts[0] != "" || parts[1] != "argon2id" {
+		return decoded, errUnknownHashFormat
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil {
+		return decoded, err
+	}
+	if version != argon2.Version {
+		return decoded, fmt.Errorf("unsupported argon2 version %d", version)
+	}
+
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &decoded.memory, &decoded.time, &decoded.threads); err != nil {
+		return decoded, err
+	}
+
+	var err error
+	if decoded.salt, err = base64.RawStdEncoding.DecodeString(parts[4]); err != nil {
+		return decoded, err
+	}
+	if decoded.key, err = base64.RawStdEncoding.DecodeString(parts[5]); err != nil {
+		return decoded, err
+	}
+
+	return decoded, nil
+}
+
+// LegacySHA1Hasher verifies the SHA1 hashes with the static salt that were
+// stored before the switch to argon2id. Every such hash needs a rehash.
+type LegacySHA1Hasher struct{}
+
+func (LegacySHA1Hasher) Hash(password string) (string, error) {
+	hash := sha1.New()
+	hash.Write([]byte(password))
+
+	return fmt.Sprintf("%x", hash.Sum([]byte(legacySalt))), nil
+}
+
+func (h LegacySHA1Hasher) Verify(password, hash string) (bool, error) {
+	if strings.HasPrefix(hash, "$") {
+		return false, errUnknownHashFormat
+	}
+
+	expected, _ := h.Hash(password)
+	return subtle.ConstantTimeCompare([]byte(expected), []byte(hash)) == 1, nil
+}
+
+func (LegacySHA1Hasher) NeedsRehash(string) bool {
+	return true
+}
+
+// MigratingHasher hashes with the primary hasher and additionally accepts
+// hashes of the legacy hashers, which are always reported as needing a rehash.
+type MigratingHasher struct {
+	primary PasswordHasher
+	legacy  []PasswordHasher
+}
+
+func NewMigratingHasher(primary PasswordHasher, legacy ...PasswordHasher) *MigratingHasher {
+	return &MigratingHasher{primary: primary, legacy: legacy}
+}
+
+func (h *MigratingHasher) Hash(password string) (string, error) {
+	return h.primary.Hash(password)
+}
+
+func (h *MigratingHasher) Verify(password, hash string) (bool, error) {
+	ok, err := h.primary.Verify(password, hash)
+	if !errors.Is(err, errUnknownHashFormat) {
+		return ok, err
+	}
+
+	for _, legacy := range h.legacy {
+		ok, err = legacy.Verify(password, hash)
+		if !errors.Is(err, errUnknownHashFormat) {
+			return ok, err
+		}
+	}
+
+	return false, errUnknownHashFormat
+}
+
+func (h *MigratingHasher) NeedsRehash(hash string) bool {
+	return h.primary.NeedsRehash(hash)
+}
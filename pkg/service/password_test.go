@@ -0,0 +1,66 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMigratingHasher(t *testing.T) {
+	hasher := NewMigratingHasher(NewArgon2idHasher(), LegacySHA1Hasher{})
+	legacyHash, _ := LegacySHA1Hasher{}.Hash("qwerty")
+	argonHash, err := hasher.Hash("qwerty")
+	assert.NoError(t, err)
+
+	testTable := []struct {
+		name        string
+		password    string
+		hash        string
+		expectedOk  bool
+		needsRehash bool
+	}{
+		{
+			name:       "argon2id",
+			password:   "qwerty",
+			hash:       argonHash,
+			expectedOk: true,
+		},
+		{
+			name:     "argon2id wrong password",
+			password: "qwerty1",
+			hash:     argonHash,
+		},
+		{
+			name:        "legacy sha1",
+			password:    "qwerty",
+			hash:        legacyHash,
+			expectedOk:  true,
+			needsRehash: true,
+		},
+		{
+			name:        "legacy sha1 wrong password",
+			password:    "qwerty1",
+			hash:        legacyHash,
+			needsRehash: true,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			ok, err := hasher.Verify(testCase.password, testCase.hash)
+
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.expectedOk, ok)
+			assert.Equal(t, testCase.needsRehash, hasher.NeedsRehash(testCase.hash))
+		})
+	}
+}
+
+func TestArgon2idHasher_uniqueSalt(t *testing.T) {
+	hasher := NewArgon2idHasher()
+
+	first, _ := hasher.Hash("qwerty")
+	second, _ := hasher.Hash("qwerty")
+
+	assert.NotEqual(t, first, second)
+}
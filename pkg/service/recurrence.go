@@ -0,0 +1,68 @@
+package service
+
+import (
+	"strings"
+	"time"
+
+	"github.com/lypolix/todo-app"
+	"github.com/teambition/rrule-go"
+)
+
+// parseRRule parses an RFC 5545 RRULE value such as FREQ=WEEKLY;BYDAY=MO,
+// optionally prefixed with "RRULE:", and anchors it at dtstart. The start of a
+// series is always the deadline of its item, so DTSTART is not accepted.
+func parseRRule(value string, dtstart time.Time) (*rrule.RRule, error) {
+	value = strings.TrimPrefix(strings.ToUpper(strings.TrimSpace(value)), "RRULE:")
+	if strings.ContainsAny(value, "\r\n") || strings.Contains(value, "DTSTART") {
+		return nil, invalidRRule("DTSTART is taken from the deadline")
+	}
+
+	option, err := rrule.StrToROption(value)
+	if err != nil {
+		return nil, invalidRRule(err.Error())
+	}
+	option.Dtstart = dtstart
+
+	rule, err := rrule.NewRRule(*option)
+	if err != nil {
+		return nil, invalidRRule(err.Error())
+	}
+
+	return rule, nil
+}
+
+func invalidRRule(reason string) error {
+	return todo.NewError(todo.ErrInvalidInput, "invalid_rrule", "invalid rrule: "+reason)
+}
+
+// normalizeRRule validates the rule of an item due at deadline and returns it
+// in canonical form.
+func normalizeRRule(value string, deadline *time.Time) (string, error) {
+	if deadline == nil {
+		return "", todo.ErrRRuleWithoutDeadline
+	}
+
+	rule, err := parseRRule(value, *deadline)
+	if err != nil {
+		return "", err
+	}
+
+	return rule.OrigOptions.RRuleString(), nil
+}
+
+// nextOccurrence returns the first occurrence of the series after the given
+// one and false when the series has no more occurrences.
+func nextOccurrence(series todo.ItemSeries, after time.Time) (time.Time, bool, error) {
+	if series.RRule == "" {
+		return time.Time{}, false, nil
+	}
+
+	rule, err := parseRRule(series.RRule, series.DtStart)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+
+	next := rule.After(after, false)
+
+	return next, !next.IsZero(), nil
+}
@@ -0,0 +1,135 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lypolix/todo-app"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNextOccurrence(t *testing.T) {
+	// Monday
+	dtstart := time.Date(2025, time.January, 6, 9, 0, 0, 0, time.UTC)
+
+	testTable := []struct {
+		name       string
+		rrule      string
+		after      time.Time
+		expected   time.Time
+		expectedOk bool
+	}{
+		{
+			name:       "weekly",
+			rrule:      "FREQ=WEEKLY",
+			after:      dtstart,
+			expected:   dtstart.AddDate(0, 0, 7),
+			expectedOk: true,
+		},
+		{
+			name:       "weekly by day",
+			rrule:      "FREQ=WEEKLY;BYDAY=MO,TH",
+			after:      dtstart,
+			expected:   dtstart.AddDate(0, 0, 3),
+			expectedOk: true,
+		},
+		{
+			name:       "last working day of month",
+			rrule:      "FREQ=MONTHLY;BYDAY=MO,TU,WE,TH,FR;BYSETPOS=-1",
+			after:      dtstart,
+			expected:   time.Date(2025, time.January, 31, 9, 0, 0, 0, time.UTC),
+			expectedOk: true,
+		},
+		{
+			name:       "interval",
+			rrule:      "FREQ=DAILY;INTERVAL=3",
+			after:      dtstart.AddDate(0, 0, 3),
+			expected:   dtstart.AddDate(0, 0, 6),
+			expectedOk: true,
+		},
+		{
+			name:  "count exhausted",
+			rrule: "FREQ=DAILY;COUNT=2",
+			after: dtstart.AddDate(0, 0, 1),
+		},
+		{
+			name:  "until passed",
+			rrule: "FREQ=WEEKLY;UNTIL=20250110T000000Z",
+			after: dtstart,
+		},
+		{
+			name:  "ended series",
+			rrule: "",
+			after: dtstart,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			series := todo.ItemSeries{RRule: testCase.rrule, DtStart: dtstart}
+
+			next, ok, err := nextOccurrence(series, testCase.after)
+
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.expectedOk, ok)
+			if testCase.expectedOk {
+				assert.True(t, testCase.expected.Equal(next), "expected %s, got %s", testCase.expected, next)
+			}
+		})
+	}
+}
+
+func TestNormalizeRRule(t *testing.T) {
+	deadline := time.Date(2025, time.January, 6, 9, 0, 0, 0, time.UTC)
+
+	testTable := []struct {
+		name        string
+		rrule       string
+		deadline    *time.Time
+		expected    string
+		expectedErr error
+	}{
+		{
+			name:     "canonical form",
+			rrule:    "rrule:freq=monthly;bymonthday=1",
+			deadline: &deadline,
+			expected: "FREQ=MONTHLY;BYMONTHDAY=1",
+		},
+		{
+			name:        "no deadline",
+			rrule:       "FREQ=DAILY",
+			expectedErr: todo.ErrRRuleWithoutDeadline,
+		},
+		{
+			name:        "dtstart",
+			rrule:       "DTSTART:20250101T000000Z\nRRULE:FREQ=DAILY",
+			deadline:    &deadline,
+			expectedErr: todo.ErrInvalidInput,
+		},
+		{
+			name:        "no freq",
+			rrule:       "COUNT=3",
+			deadline:    &deadline,
+			expectedErr: todo.ErrInvalidInput,
+		},
+		{
+			name:        "unknown property",
+			rrule:       "FREQ=DAILY;EVERY=2",
+			deadline:    &deadline,
+			expectedErr: todo.ErrInvalidInput,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			rule, err := normalizeRRule(testCase.rrule, testCase.deadline)
+
+			if testCase.expectedErr != nil {
+				assert.ErrorIs(t, err, testCase.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.expected, rule)
+		})
+	}
+}
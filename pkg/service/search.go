@@ -0,0 +1,49 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"unicode"
+
+	"github.com/lypolix/todo-app"
+	"github.com/lypolix/todo-app/pkg/repository"
+)
+
+type SearchService struct {
+	repo repository.Search
+}
+
+func NewSearchService(repo repository.Search) *SearchService {
+	return &SearchService{repo: repo}
+}
+
+// Search finds the lists, items and comments containing every word of the
+// query, either as a whole word or as the beginning of one.
+func (s *SearchService) Search(ctx context.Context, userId int, query todo.SearchQuery) ([]todo.SearchResult, error) {
+	if err := query.Validate(); err != nil {
+		return nil, err
+	}
+
+	tsquery := prefixQuery(query.Query)
+	if tsquery == "" {
+		return nil, todo.ErrInvalidSearchQuery
+	}
+
+	return s.repo.Search(ctx, userId, tsquery, query.Limit)
+}
+
+// prefixQuery turns free text into a tsquery matching every word as a
+// prefix. Only letters and digits are kept, so the text cannot inject
+// tsquery operators.
+func prefixQuery(text string) string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	terms := make([]string, len(words))
+	for i, word := range words {
+		terms[i] = word + ":*"
+	}
+
+	return strings.Join(terms, " & ")
+}
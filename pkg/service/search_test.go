@@ -0,0 +1,42 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrefixQuery(t *testing.T) {
+	testTable := []struct {
+		name     string
+		text     string
+		expected string
+	}{
+		{
+			name:     "words",
+			text:     "Quarterly Rep",
+			expected: "quarterly:* & rep:*",
+		},
+		{
+			name:     "unicode",
+			text:     "  отчёт за май ",
+			expected: "отчёт:* & за:* & май:*",
+		},
+		{
+			name:     "tsquery operators",
+			text:     "milk | !bread & (eggs:*)",
+			expected: "milk:* & bread:* & eggs:*",
+		},
+		{
+			name:     "no words",
+			text:     "!!! ---",
+			expected: "",
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			assert.Equal(t, testCase.expected, prefixQuery(testCase.text))
+		})
+	}
+}
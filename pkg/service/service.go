@@ -1,45 +1,169 @@
 package service
 
-import  (
+import (
+	"context"
+	"io"
+	"time"
+
 	"github.com/lypolix/todo-app"
 	"github.com/lypolix/todo-app/pkg/repository"
+	"github.com/lypolix/todo-app/pkg/storage"
 )
 
 
 //go:generate mockgen -source=service.go -destination=mocks/mock.go
 
 type Authorization interface{
-	CreateUser(user todo.User) (int, error)
-	GenerateToken(username, password string) (string, error)
-	ParseToken(token string) (int, error)
+	CreateUser(ctx context.Context, user todo.User) (int, error)
+	GenerateToken(ctx context.Context, username, password string) (todo.Tokens, error)
+	Authenticate(ctx context.Context, username, password string) (int, error)
+	RefreshToken(ctx context.Context, refreshToken string) (todo.Tokens, error)
+	Logout(ctx context.Context, accessToken, refreshToken string) error
+	LogoutAll(ctx context.Context, userId int) error
+	ParseToken(ctx context.Context, token string) (int, error)
+	JWKS() JWKS
 }
 
 type TodoList interface{
-	Create(userId int, list todo.TodoList) (int, error)
-	GetAll(userId int) ([]todo.TodoList, error)
-	GetById(userId, listId int)(todo.TodoList, error)
-	Delete(userId, listId int) error
-	Update(userId, listId int, input todo.UpdateListInput) error
+	Create(ctx context.Context, userId int, list todo.TodoList) (int, error)
+	GetAll(ctx context.Context, userId int) ([]todo.TodoList, error)
+	GetById(ctx context.Context, userId, listId int)(todo.TodoList, error)
+	Delete(ctx context.Context, userId, listId int, version *int) error
+	Update(ctx context.Context, userId, listId int, input todo.UpdateListInput) error
 }
 
 type TodoItem interface{
-	Create(userId, listId int, item todo.TodoItem) (int, error)
-	GetAll(userId, listId int)([]todo.TodoItem, error)
-	GetById(userId, itemId int)(todo.TodoItem, error)
-	Delete(userId, itemId int) error
-	Update(userId, itemId int, input todo.UpdateItemInput) error
+	Create(ctx context.Context, userId, listId int, input todo.CreateItemInput) (int, error)
+	GetAll(ctx context.Context, userId, listId int, filter todo.ItemFilter) ([]todo.TodoItem, string, error)
+	Find(ctx context.Context, userId int, filter todo.ItemFilter) ([]todo.TodoItem, string, error)
+	Move(ctx context.Context, userId, itemId int, input todo.MoveItemInput) error
+	GetAgenda(ctx context.Context, userId int, now time.Time) (todo.Agenda, error)
+	GetById(ctx context.Context, userId, itemId int)(todo.TodoItem, error)
+	Delete(ctx context.Context, userId, itemId int, version *int) error
+	Update(ctx context.Context, userId, itemId int, input todo.UpdateItemInput) error
+	GetDueBetween(ctx context.Context, from, to time.Time) ([]todo.UserItem, error)
+}
+
+type Subtask interface{
+	Create(ctx context.Context, userId, itemId int, subtask todo.Subtask) (int, error)
+	GetAll(ctx context.Context, userId, itemId int) ([]todo.Subtask, error)
+	Update(ctx context.Context, userId, itemId, subtaskId int, input todo.UpdateSubtaskInput) error
+	Delete(ctx context.Context, userId, itemId, subtaskId int) error
+}
+
+type Comment interface{
+	Create(ctx context.Context, userId, itemId int, comment todo.Comment) (int, error)
+	GetAll(ctx context.Context, userId, itemId int) ([]todo.Comment, error)
+	Update(ctx context.Context, userId, itemId, commentId int, input todo.UpdateCommentInput) error
+	Delete(ctx context.Context, userId, itemId, commentId int) error
+}
+
+type Attachment interface{
+	Create(ctx context.Context, userId, itemId int, attachment todo.Attachment, content io.Reader) (int, error)
+	GetAll(ctx context.Context, userId, itemId int) ([]todo.Attachment, error)
+	Open(ctx context.Context, userId, itemId, attachmentId int) (todo.Attachment, io.ReadCloser, error)
+	Delete(ctx context.Context, userId, itemId, attachmentId int) error
+	PurgeDetached(ctx context.Context) error
+}
+
+type Label interface{
+	Create(ctx context.Context, userId int, label todo.Label) (int, error)
+	GetAll(ctx context.Context, userId int) ([]todo.Label, error)
+	Update(ctx context.Context, userId, labelId int, input todo.UpdateLabelInput) error
+	Delete(ctx context.Context, userId, labelId int) error
+	Attach(ctx context.Context, userId, itemId, labelId int) error
+	Detach(ctx context.Context, userId, itemId, labelId int) error
+}
+
+type Trash interface{
+	GetAll(ctx context.Context, userId int) (todo.Trash, error)
+	Restore(ctx context.Context, userId int, kind string, id int) error
+	Purge(ctx context.Context) error
+}
+
+type Activity interface{
+	GetByList(ctx context.Context, userId, listId int, page todo.Page) ([]todo.Activity, string, error)
+	GetByItem(ctx context.Context, userId, itemId int, page todo.Page) ([]todo.Activity, string, error)
+}
+
+type Exchange interface{
+	Export(ctx context.Context, userId, listId int, format string) ([]byte, error)
+	Import(ctx context.Context, userId int, input todo.ImportInput, data io.Reader) (todo.ImportResult, error)
+}
+
+type Calendar interface{
+	CreateToken(ctx context.Context, userId int) (string, error)
+	DeleteToken(ctx context.Context, userId int) error
+	Feed(ctx context.Context, token, component string) ([]byte, error)
+	Import(ctx context.Context, userId, listId int, data io.Reader) ([]int, error)
+}
+
+type CalDAV interface{
+	GetCalendars(ctx context.Context, userId int) ([]todo.Calendar, error)
+	GetCalendar(ctx context.Context, userId, listId int) (todo.Calendar, error)
+	GetObjects(ctx context.Context, userId, listId int) ([]todo.CalendarObject, error)
+	GetObject(ctx context.Context, userId, listId int, name string) (todo.CalendarObject, error)
+	PutObject(ctx context.Context, userId, listId int, name string, data io.Reader, cond todo.Precondition) (todo.CalendarObject, bool, error)
+	DeleteObject(ctx context.Context, userId, listId int, name string, cond todo.Precondition) error
+}
+
+type Search interface{
+	Search(ctx context.Context, userId int, query todo.SearchQuery) ([]todo.SearchResult, error)
+}
+
+type ListMember interface{
+	Add(ctx context.Context, userId, listId int, input todo.AddMemberInput) error
+	GetAll(ctx context.Context, userId, listId int) ([]todo.ListMember, error)
+	UpdateRole(ctx context.Context, userId, listId, memberId int, input todo.UpdateMemberInput) error
+	Delete(ctx context.Context, userId, listId, memberId int) error
+	TransferOwnership(ctx context.Context, userId, listId, newOwnerId int) error
 }
 
 type Service struct {
 	Authorization
 	TodoList
 	TodoItem
+	ListMember
+	Subtask
+	Comment
+	Attachment
+	Label
+	Trash
+	Activity
+	Search
+	Exchange
+	Calendar
+	CalDAV
+
+	// Events carries changes to be pushed to connected clients
+	Events *Events
 }
 
-func NewService(repos *repository.Repository) *Service {
+type Config struct {
+	Auth AuthConfig
+	Trash TrashConfig
+	Attachments AttachmentConfig
+}
+
+func NewService(repos *repository.Repository, files storage.Storage, cfg Config) *Service {
+	items := NewTodoItemService(repos.TodoItem, repos.TodoList)
+	events := NewEvents()
+
 	return &Service{
-		Authorization: NewAuthService(repos.Authorization),
+		Authorization: NewAuthService(repos.Authorization, NewMigratingHasher(NewArgon2idHasher(), LegacySHA1Hasher{}), cfg.Auth),
 		TodoList: NewTodoListService(repos.TodoList),
-		TodoItem: NewTodoItemService(repos.TodoItem, repos.TodoList),
+		TodoItem: items,
+		ListMember: NewListMemberService(repos.ListMember),
+		Subtask: NewSubtaskService(repos.Subtask, items),
+		Comment: NewCommentService(repos.Comment, events),
+		Attachment: NewAttachmentService(repos.Attachment, files, cfg.Attachments),
+		Label: NewLabelService(repos.Label),
+		Trash: NewTrashService(repos.Trash, cfg.Trash),
+		Activity: NewActivityService(repos.Activity),
+		Search: NewSearchService(repos.Search),
+		Exchange: NewExchangeService(repos.TodoList, repos.TodoItem),
+		Calendar: NewCalendarService(repos.Calendar, repos.TodoItem),
+		CalDAV: NewCalDAVService(repos.CalDAV, items),
+		Events: events,
 	}
 }
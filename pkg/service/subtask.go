@@ -0,0 +1,68 @@
+package service
+
+import (
+	"context"
+
+	"github.com/lypolix/todo-app"
+	"github.com/lypolix/todo-app/pkg/repository"
+)
+
+type SubtaskService struct {
+	repo  repository.Subtask
+	items TodoItem
+}
+
+func NewSubtaskService(repo repository.Subtask, items TodoItem) *SubtaskService {
+	return &SubtaskService{repo: repo, items: items}
+}
+
+func (s *SubtaskService) Create(ctx context.Context, userId, itemId int, subtask todo.Subtask) (int, error) {
+	id, err := s.repo.Create(ctx, userId, itemId, subtask)
+	if err != nil {
+		return 0, err
+	}
+
+	return id, s.autoComplete(ctx, userId, itemId)
+}
+
+func (s *SubtaskService) GetAll(ctx context.Context, userId, itemId int) ([]todo.Subtask, error) {
+	return s.repo.GetAll(ctx, userId, itemId)
+}
+
+func (s *SubtaskService) Update(ctx context.Context, userId, itemId, subtaskId int, input todo.UpdateSubtaskInput) error {
+	if err := input.Validate(); err != nil {
+		return err
+	}
+
+	if err := s.repo.Update(ctx, userId, itemId, subtaskId, input); err != nil {
+		return err
+	}
+
+	return s.autoComplete(ctx, userId, itemId)
+}
+
+func (s *SubtaskService) Delete(ctx context.Context, userId, itemId, subtaskId int) error {
+	if err := s.repo.Delete(ctx, userId, itemId, subtaskId); err != nil {
+		return err
+	}
+
+	return s.autoComplete(ctx, userId, itemId)
+}
+
+// autoComplete marks the item done when it asks for it and all of its
+// subtasks are done, e.g. after the last open one was checked or removed or
+// the first one was added checked. It goes through the item service, so a
+// recurring item schedules its next occurrence as usual.
+func (s *SubtaskService) autoComplete(ctx context.Context, userId, itemId int) error {
+	item, err := s.items.GetById(ctx, userId, itemId)
+	if err != nil {
+		return err
+	}
+
+	if !item.AutoComplete || item.Done || item.Progress == nil || *item.Progress < 100 {
+		return nil
+	}
+
+	done := true
+	return s.items.Update(ctx, userId, itemId, todo.UpdateItemInput{Done: &done})
+}
@@ -0,0 +1,91 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/lypolix/todo-app"
+	"github.com/lypolix/todo-app/pkg/service"
+	mock_service "github.com/lypolix/todo-app/pkg/service/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+// subtaskRepo accepts every change, the tests are about what happens after it.
+type subtaskRepo struct{}
+
+func (subtaskRepo) Create(context.Context, int, int, todo.Subtask) (int, error) { return 1, nil }
+func (subtaskRepo) GetAll(context.Context, int, int) ([]todo.Subtask, error)    { return nil, nil }
+func (subtaskRepo) Update(context.Context, int, int, int, todo.UpdateSubtaskInput) error {
+	return nil
+}
+func (subtaskRepo) Delete(context.Context, int, int, int) error { return nil }
+
+func TestSubtaskService_autoComplete(t *testing.T) {
+	progress := func(p int) *int { return &p }
+	done := true
+
+	testTable := []struct {
+		name         string
+		item         todo.TodoItem
+		expectUpdate bool
+	}{
+		{
+			name:         "all subtasks done",
+			item:         todo.TodoItem{Id: 1, AutoComplete: true, Progress: progress(100)},
+			expectUpdate: true,
+		},
+		{
+			name: "open subtasks left",
+			item: todo.TodoItem{Id: 1, AutoComplete: true, Progress: progress(50)},
+		},
+		{
+			name: "auto complete off",
+			item: todo.TodoItem{Id: 1, Progress: progress(100)},
+		},
+		{
+			name: "already done",
+			item: todo.TodoItem{Id: 1, AutoComplete: true, Done: true, Progress: progress(100)},
+		},
+		{
+			name: "no subtasks",
+			item: todo.TodoItem{Id: 1, AutoComplete: true},
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			items := mock_service.NewMockTodoItem(c)
+			items.EXPECT().GetById(gomock.Any(), 2, 1).Return(testCase.item, nil)
+			if testCase.expectUpdate {
+				items.EXPECT().Update(gomock.Any(), 2, 1, todo.UpdateItemInput{Done: &done}).Return(nil)
+			}
+
+			subtasks := service.NewSubtaskService(subtaskRepo{}, items)
+			err := subtasks.Update(context.Background(), 2, 1, 3, todo.UpdateSubtaskInput{Done: &done})
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestSubtaskService_Create_autoComplete(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	// the first subtask is added checked, so the item is complete
+	progress := 100
+	done := true
+	items := mock_service.NewMockTodoItem(c)
+	items.EXPECT().GetById(gomock.Any(), 2, 1).Return(todo.TodoItem{Id: 1, AutoComplete: true, Progress: &progress}, nil)
+	items.EXPECT().Update(gomock.Any(), 2, 1, todo.UpdateItemInput{Done: &done}).Return(nil)
+
+	subtasks := service.NewSubtaskService(subtaskRepo{}, items)
+	id, err := subtasks.Create(context.Background(), 2, 1, todo.Subtask{Title: "Milk", Done: true})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, id)
+}
@@ -1,6 +1,9 @@
 package service
 
 import (
+	"context"
+	"time"
+
 	"github.com/lypolix/todo-app"
 	"github.com/lypolix/todo-app/pkg/repository"
 )
@@ -14,28 +17,131 @@ func NewTodoItemService(repo repository.TodoItem, listRepo repository.TodoList)
 	return &TodoItemService{repo: repo, listRepo: listRepo}
 }
 
-func (s *TodoItemService) Create(userId, listId int, item todo.TodoItem) (int, error) {
-	_, err := s.listRepo.GetById(userId, listId)
+func (s *TodoItemService) Create(ctx context.Context, userId, listId int, input todo.CreateItemInput) (int, error) {
+	_, err := s.listRepo.GetById(ctx, userId, listId)
 	if err != nil {
 
 		return 0, err
 	}
 
-	return s.repo.Create(userId, listId, item)
+	item := todo.TodoItem{
+		Title: input.Title,
+		Description: input.Description,
+		Deadline: input.Deadline,
+		RRule: input.RRule,
+		AutoComplete: input.AutoComplete,
+		Priority: input.Priority,
+	}
+	if item.RRule != "" {
+		rule, err := normalizeRRule(item.RRule, item.Deadline)
+		if err != nil {
+			return 0, err
+		}
+		item.RRule = rule
+	}
+
+	return s.repo.Create(ctx, userId, listId, item)
+}
+
+func (s *TodoItemService) GetAll(ctx context.Context, userId, listId int, filter todo.ItemFilter) ([]todo.TodoItem, string, error){
+	if err := filter.Validate(); err != nil {
+		return nil, "", err
+	}
+	filter.Tags = normalizeTags(filter.Tags)
+	return s.repo.GetAll(ctx, userId, listId, filter)
+}
+
+func (s *TodoItemService) Find(ctx context.Context, userId int, filter todo.ItemFilter) ([]todo.TodoItem, string, error) {
+	if err := filter.Validate(); err != nil {
+		return nil, "", err
+	}
+	filter.Tags = normalizeTags(filter.Tags)
+	return s.repo.Find(ctx, userId, filter)
+}
+
+func (s *TodoItemService) GetById(ctx context.Context, userId, itemId int) (todo.TodoItem, error) {
+	return s.repo.GetById(ctx, userId, itemId)
 }
 
-func (s *TodoItemService) GetAll(userId, listId int) ([]todo.TodoItem, error){
-	return s.repo.GetAll(userId, listId)
+func (s *TodoItemService) Move(ctx context.Context, userId, itemId int, input todo.MoveItemInput) error {
+	if err := input.Validate(); err != nil {
+		return err
+	}
+
+	return s.repo.Move(ctx, userId, itemId, input)
 }
 
-func (s *TodoItemService) GetById(userId, itemId int) (todo.TodoItem, error) {
-	return s.repo.GetById(userId, itemId)
+func (s *TodoItemService) Delete(ctx context.Context, userId, itemId int, version *int) error {
+	return s.repo.Delete(ctx, userId, itemId, version)
 }
 
-func (s *TodoItemService) Delete(userId, itemId int) error {
-	return s.repo.Delete(userId, itemId)
+func (s *TodoItemService) Update(ctx context.Context, userId, itemId int, input todo.UpdateItemInput) error {
+	if err := input.Validate(); err != nil {
+		return err
+	}
+
+	item, err := s.repo.GetById(ctx, userId, itemId)
+	if err != nil {
+		return err
+	}
+
+	if input.RRule != nil {
+		if item.SeriesId != nil && input.Scope != todo.ScopeFuture {
+			return todo.ErrSeriesRuleScope
+		}
+
+		if *input.RRule != "" {
+			deadline := item.Deadline
+			if input.Deadline != nil {
+				deadline = input.Deadline
+			}
+
+			rule, err := normalizeRRule(*input.RRule, deadline)
+			if err != nil {
+				return err
+			}
+			input.RRule = &rule
+		}
+	}
+
+	if err := s.repo.Update(ctx, userId, itemId, input); err != nil {
+		return err
+	}
+
+	if input.Done != nil && *input.Done {
+		return s.scheduleNext(ctx, userId, itemId)
+	}
+
+	return nil
+}
+
+// scheduleNext creates the occurrence following a completed occurrence of a
+// series. The repository skips occurrences that already exist, so completing
+// the same occurrence twice does not schedule the next one twice.
+func (s *TodoItemService) scheduleNext(ctx context.Context, userId, itemId int) error {
+	item, err := s.repo.GetById(ctx, userId, itemId)
+	if err != nil {
+		return err
+	}
+
+	if item.SeriesId == nil || item.RecurrenceId == nil {
+		return nil
+	}
+
+	series, err := s.repo.GetSeries(ctx, userId, *item.SeriesId)
+	if err != nil {
+		return err
+	}
+
+	next, ok, err := nextOccurrence(series, *item.RecurrenceId)
+	if err != nil || !ok {
+		return err
+	}
+
+	_, err = s.repo.CreateOccurrence(ctx, userId, itemId, next)
+	return err
 }
 
-func (s *TodoItemService) Update(userId, itemId int, input todo.UpdateItemInput) error {
-	return s.repo.Update(userId, itemId, input)
+func (s *TodoItemService) GetDueBetween(ctx context.Context, from, to time.Time) ([]todo.UserItem, error) {
+	return s.repo.GetDueBetween(ctx, from, to)
 }
\ No newline at end of file
@@ -0,0 +1,134 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/lypolix/todo-app"
+	"github.com/lypolix/todo-app/pkg/repository"
+	"github.com/lypolix/todo-app/pkg/service"
+	"github.com/stretchr/testify/assert"
+)
+
+// itemRepo records the updates and filters that reach the repository, the
+// methods the tests do not need are left to the embedded nil interface.
+type itemRepo struct {
+	repository.TodoItem
+	updates []todo.UpdateItemInput
+	filters []todo.ItemFilter
+}
+
+func (r *itemRepo) GetById(_ context.Context, _, itemId int) (todo.TodoItem, error) {
+	return todo.TodoItem{Id: itemId, Title: "Milk"}, nil
+}
+func (r *itemRepo) Update(_ context.Context, _, _ int, input todo.UpdateItemInput) error {
+	r.updates = append(r.updates, input)
+	return nil
+}
+func (r *itemRepo) GetAll(_ context.Context, _, _ int, filter todo.ItemFilter) ([]todo.TodoItem, string, error) {
+	r.filters = append(r.filters, filter)
+	return nil, "", nil
+}
+
+func TestTodoItemService_Update_deadline(t *testing.T) {
+	deadline := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	testTable := []struct {
+		name        string
+		input       todo.UpdateItemInput
+		expectedErr error
+	}{
+		{
+			name:  "Deadline Only",
+			input: todo.UpdateItemInput{Deadline: &deadline},
+		},
+		{
+			name:        "Nothing",
+			input:       todo.UpdateItemInput{},
+			expectedErr: todo.ErrEmptyUpdate,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			repo := &itemRepo{}
+			items := service.NewTodoItemService(repo, nil)
+
+			err := items.Update(context.Background(), 1, 5, testCase.input)
+
+			assert.ErrorIs(t, err, testCase.expectedErr)
+			if testCase.expectedErr == nil {
+				assert.Equal(t, []todo.UpdateItemInput{testCase.input}, repo.updates)
+			} else {
+				assert.Empty(t, repo.updates)
+			}
+		})
+	}
+}
+
+func TestTodoItemService_GetAll_due(t *testing.T) {
+	march := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	april := time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)
+
+	testTable := []struct {
+		name      string
+		dueBefore *time.Time
+		dueAfter  *time.Time
+		invalid   bool
+	}{
+		{
+			name:      "Overdue",
+			dueBefore: &march,
+		},
+		{
+			name:     "Due Later",
+			dueAfter: &march,
+		},
+		{
+			name:      "Due In March",
+			dueAfter:  &march,
+			dueBefore: &april,
+		},
+		{
+			name:      "Empty Range",
+			dueAfter:  &march,
+			dueBefore: &march,
+			invalid:   true,
+		},
+		{
+			name:      "Reversed Range",
+			dueAfter:  &april,
+			dueBefore: &march,
+			invalid:   true,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			repo := &itemRepo{}
+			items := service.NewTodoItemService(repo, nil)
+
+			filter := todo.ItemFilter{
+				DueBefore: testCase.dueBefore,
+				DueAfter:  testCase.dueAfter,
+				TagMatch:  todo.TagMatchAny,
+				Sort:      todo.SortByDeadline,
+				Order:     todo.OrderAsc,
+				Limit:     todo.DefaultItemsLimit,
+			}
+			_, _, err := items.GetAll(context.Background(), 1, 3, filter)
+
+			if testCase.invalid {
+				assert.ErrorIs(t, err, todo.ErrInvalidInput)
+				assert.Empty(t, repo.filters)
+				return
+			}
+			assert.NoError(t, err)
+			if assert.Len(t, repo.filters, 1) {
+				assert.Equal(t, testCase.dueBefore, repo.filters[0].DueBefore)
+				assert.Equal(t, testCase.dueAfter, repo.filters[0].DueAfter)
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"github.com/lypolix/todo-app"
 	"github.com/lypolix/todo-app/pkg/repository"
 )
@@ -13,25 +14,25 @@ func NewTodoListService(repo repository.TodoList) *TodoListService {
 	return &TodoListService{repo: repo}
 }
 
-func (s *TodoListService) Create(userId int, list todo.TodoList) (int, error){
-	return s.repo.Create(userId, list)
+func (s *TodoListService) Create(ctx context.Context, userId int, list todo.TodoList) (int, error){
+	return s.repo.Create(ctx, userId, list)
 }
 
-func (s *TodoListService) GetAll(userId int) ([]todo.TodoList, error){
-	return s.repo.GetAll(userId)
+func (s *TodoListService) GetAll(ctx context.Context, userId int) ([]todo.TodoList, error){
+	return s.repo.GetAll(ctx, userId)
 }
 
-func (s *TodoListService) GetById(userId, listId int) (todo.TodoList, error){
-	return s.repo.GetById(userId, listId)
+func (s *TodoListService) GetById(ctx context.Context, userId, listId int) (todo.TodoList, error){
+	return s.repo.GetById(ctx, userId, listId)
 }
 
-func (s *TodoListService) Delete(userId, listId int) error {
-	return s.repo.Delete(userId, listId)
+func (s *TodoListService) Delete(ctx context.Context, userId, listId int, version *int) error {
+	return s.repo.Delete(ctx, userId, listId, version)
 }
 
-func (s *TodoListService) Update(userId, listId int, input todo.UpdateListInput) error {
+func (s *TodoListService) Update(ctx context.Context, userId, listId int, input todo.UpdateListInput) error {
 	if err := input.Validate(); err != nil {
 		return err 	
 	}
-	return s.repo.Update(userId, listId, input)
+	return s.repo.Update(ctx, userId, listId, input)
 }
\ No newline at end of file
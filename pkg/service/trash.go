@@ -0,0 +1,74 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/lypolix/todo-app"
+	"github.com/lypolix/todo-app/pkg/repository"
+	"github.com/sirupsen/logrus"
+)
+
+type TrashConfig struct {
+	// Retention is how long deleted lists and items stay in the trash, zero keeps them forever
+	Retention time.Duration
+}
+
+type TrashService struct {
+	repo repository.Trash
+	cfg  TrashConfig
+}
+
+func NewTrashService(repo repository.Trash, cfg TrashConfig) *TrashService {
+	return &TrashService{repo: repo, cfg: cfg}
+}
+
+func (s *TrashService) GetAll(ctx context.Context, userId int) (todo.Trash, error) {
+	return s.repo.GetAll(ctx, userId)
+}
+
+// Restore takes a list or an item, depending on kind, out of the trash.
+func (s *TrashService) Restore(ctx context.Context, userId int, kind string, id int) error {
+	switch kind {
+	case todo.TrashLists:
+		return s.repo.RestoreList(ctx, userId, id)
+	case todo.TrashItems:
+		return s.repo.RestoreItem(ctx, userId, id)
+	default:
+		return todo.ErrInvalidTrashType
+	}
+}
+
+// Purge permanently deletes what has been in the trash longer than the retention.
+func (s *TrashService) Purge(ctx context.Context) error {
+	if s.cfg.Retention <= 0 {
+		return nil
+	}
+
+	return s.repo.Purge(ctx, time.Now().Add(-s.cfg.Retention))
+}
+
+// RunPurger runs the purges one after another right away and then every
+// interval until ctx is done. A zero interval disables purging.
+func RunPurger(ctx context.Context, interval time.Duration, purges ...func(context.Context) error) {
+	if interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		for _, purge := range purges {
+			if err := purge(ctx); err != nil && ctx.Err() == nil {
+				logrus.Errorf("failed to purge: %s", err.Error())
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/lypolix/todo-app"
+	"github.com/lypolix/todo-app/pkg/service"
+	"github.com/stretchr/testify/assert"
+)
+
+// trashRepo records what the service asked it to do.
+type trashRepo struct {
+	restored string
+	purged   *time.Time
+}
+
+func (r *trashRepo) GetAll(context.Context, int) (todo.Trash, error) { return todo.Trash{}, nil }
+func (r *trashRepo) RestoreList(context.Context, int, int) error {
+	r.restored = todo.TrashLists
+	return nil
+}
+func (r *trashRepo) RestoreItem(context.Context, int, int) error {
+	r.restored = todo.TrashItems
+	return nil
+}
+func (r *trashRepo) Purge(_ context.Context, before time.Time) error {
+	r.purged = &before
+	return nil
+}
+
+func TestTrashService_Restore(t *testing.T) {
+	testTable := []struct {
+		name        string
+		kind        string
+		expectedErr error
+	}{
+		{name: "list", kind: todo.TrashLists},
+		{name: "item", kind: todo.TrashItems},
+		{name: "unknown type", kind: "labels", expectedErr: todo.ErrInvalidTrashType},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			repo := &trashRepo{}
+			trash := service.NewTrashService(repo, service.TrashConfig{})
+
+			err := trash.Restore(context.Background(), 1, testCase.kind, 2)
+
+			if testCase.expectedErr != nil {
+				assert.ErrorIs(t, err, testCase.expectedErr)
+				assert.Empty(t, repo.restored)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.kind, repo.restored)
+		})
+	}
+}
+
+func TestTrashService_Purge(t *testing.T) {
+	t.Run("retention", func(t *testing.T) {
+		repo := &trashRepo{}
+		trash := service.NewTrashService(repo, service.TrashConfig{Retention: 24 * time.Hour})
+
+		assert.NoError(t, trash.Purge(context.Background()))
+
+		if assert.NotNil(t, repo.purged) {
+			assert.WithinDuration(t, time.Now().Add(-24*time.Hour), *repo.purged, time.Minute)
+		}
+	})
+
+	t.Run("kept forever", func(t *testing.T) {
+		repo := &trashRepo{}
+		trash := service.NewTrashService(repo, service.TrashConfig{})
+
+		assert.NoError(t, trash.Purge(context.Background()))
+		assert.Nil(t, repo.purged)
+	})
+}
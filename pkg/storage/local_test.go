@@ -0,0 +1,66 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocalStorage(t *testing.T) {
+	ctx := context.Background()
+	s, err := NewLocalStorage(t.TempDir())
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	n, err := s.Save(ctx, "report", strings.NewReader("hello"))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), n)
+
+	file, err := s.Open(ctx, "report")
+	if assert.NoError(t, err) {
+		data, err := io.ReadAll(file)
+		file.Close()
+		assert.NoError(t, err)
+		assert.Equal(t, "hello", string(data))
+	}
+
+	assert.NoError(t, s.Delete(ctx, "report"))
+	assert.NoError(t, s.Delete(ctx, "report"))
+
+	_, err = s.Open(ctx, "report")
+	assert.ErrorIs(t, err, ErrNotExist)
+}
+
+func TestLocalStorage_SaveFailed(t *testing.T) {
+	ctx := context.Background()
+	s, err := NewLocalStorage(t.TempDir())
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	failure := errors.New("connection reset")
+	_, err = s.Save(ctx, "report", io.MultiReader(strings.NewReader("hel"), iotest.ErrReader(failure)))
+	assert.ErrorIs(t, err, failure)
+
+	_, err = s.Open(ctx, "report")
+	assert.ErrorIs(t, err, ErrNotExist)
+}
+
+func TestLocalStorage_InvalidKey(t *testing.T) {
+	ctx := context.Background()
+	s, err := NewLocalStorage(t.TempDir())
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	for _, key := range []string{"", "../secret", "a/b", ".hidden"} {
+		_, err := s.Save(ctx, key, strings.NewReader("x"))
+		assert.Error(t, err, key)
+	}
+}
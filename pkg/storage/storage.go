@@ -0,0 +1,21 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+// ErrNotExist is returned by Open for a key nothing was saved under.
+var ErrNotExist = errors.New("file does not exist")
+
+// Storage keeps the contents of uploaded files. Keys are chosen by the
+// caller and are plain names without path separators.
+type Storage interface {
+	// Save writes everything read from r under key and returns the number of
+	// bytes written. Nothing is left under key when reading r fails.
+	Save(ctx context.Context, key string, r io.Reader) (int64, error)
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete removes the file, deleting a missing file is not an error.
+	Delete(ctx context.Context, key string) error
+}
@@ -1,57 +1,86 @@
 package wsserver
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/gorilla/websocket"
+	"github.com/lypolix/todo-app"
+	_ "github.com/lypolix/todo-app/docs"
+	"github.com/lypolix/todo-app/pkg/service"
+	"github.com/sirupsen/logrus"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
-	"github.com/sirupsen/logrus"
-	_ "github.com/lypolix/todo-app/docs"
 )
 
 type WSServer interface {
 	Start() error
+	Shutdown(ctx context.Context) error
 }
 
 type Client struct {
-	conn *websocket.Conn
-	send chan []byte
-}
-
-type Todo struct {
-	ID       string    `json:"id"`
-	Task     string    `json:"task"`
-	Deadline time.Time `json:"deadline"`
-	Done     bool      `json:"done"`
+	userId int
+	conn   *websocket.Conn
+	send   chan []byte
 }
 
 type Notification struct {
 	Type     string    `json:"type"`
+	ItemId   int       `json:"item_id"`
 	Task     string    `json:"task"`
 	Deadline time.Time `json:"deadline"`
 	Message  string    `json:"message"`
 }
 
+const (
+	deadlineCheckInterval = 1 * time.Minute
+	deadlineSoonWindow    = 30 * time.Minute
+	closeWriteWait        = 5 * time.Second
+
+	// tokenSubprotocol is the Sec-WebSocket-Protocol value browsers send in
+	// front of the JWT, e.g. new WebSocket(url, ["bearer", token]).
+	tokenSubprotocol = "bearer"
+)
+
 type wsSrv struct {
-	router  *gin.Engine
-	wsUpg   *websocket.Upgrader
-	clients map[*Client]bool
-	todos   map[string]Todo
-	mu      sync.Mutex
+	httpServer *http.Server
+	router     *gin.Engine
+	wsUpg      *websocket.Upgrader
+	services   *service.Service
+	clients    map[int]map[*Client]bool
+	mu         sync.Mutex
+
+	// conns tracks the read pumps of connected clients, so Shutdown can wait
+	// for them to answer the close frame.
+	conns   sync.WaitGroup
+	closing bool
+
+	// ctx is cancelled by Shutdown, which also cancels the queries of the
+	// background jobs and of messages from clients.
+	ctx          context.Context
+	stop         context.CancelFunc
+	queryTimeout time.Duration
+
+	// announced remembers the deadline for which a deadline_soon event was
+	// already sent, so every item is announced once per deadline.
+	// It is only touched by the checkDeadlines goroutine.
+	announced map[int]time.Time
 }
 
-func NewWsServer(addr string) WSServer {
+func NewWsServer(addr string, services *service.Service, queryTimeout time.Duration) WSServer {
 	r := gin.Default()
 	r.SetTrustedProxies([]string{"127.0.0.1"})
 
 	upgrader := &websocket.Upgrader{
 		ReadBufferSize:  1024,
 		WriteBufferSize: 1024,
+		Subprotocols:    []string{tokenSubprotocol},
 		CheckOrigin: func(r *http.Request) bool {
 			return true
 		},
@@ -63,33 +92,106 @@ func NewWsServer(addr string) WSServer {
 		c.File("./web/templates/html/index.html")
 	})
 
-	return &wsSrv{
-		router:  r,
-		wsUpg:   upgrader,
-		clients: make(map[*Client]bool),
-		todos:   make(map[string]Todo),
+	ctx, stop := context.WithCancel(context.Background())
+
+	ws := &wsSrv{
+		httpServer: &http.Server{
+			Addr:           addr,
+			Handler:        r,
+			MaxHeaderBytes: 1 << 20,
+			ReadTimeout:    10 * time.Second,
+		},
+		router:       r,
+		wsUpg:        upgrader,
+		services:     services,
+		clients:      make(map[int]map[*Client]bool),
+		ctx:          ctx,
+		stop:         stop,
+		queryTimeout: queryTimeout,
+		announced:    make(map[int]time.Time),
 	}
+
+	if services.Events != nil {
+		services.Events.Subscribe(ws.sendEvent)
+	}
+
+	return ws
 }
 
 func (ws *wsSrv) Start() error {
 	ws.router.GET("/ws", ws.wsHandler)
 	ws.router.GET("/test", ws.testHandler)
-	ws.router.POST("/api/todos", ws.createTodoHandler)
 
 	go ws.checkDeadlines()
 
-	logrus.Infof("Starting server on :8000")
-	return ws.router.Run(":8000")
+	logrus.Infof("Starting server on %s", ws.httpServer.Addr)
+	return ws.httpServer.ListenAndServe()
+}
+
+// Shutdown stops accepting connections, sends a close frame to every
+// connected client and waits until they hang up or ctx expires.
+func (ws *wsSrv) Shutdown(ctx context.Context) error {
+	ws.stop()
+	err := ws.httpServer.Shutdown(ctx)
+
+	ws.mu.Lock()
+	ws.closing = true
+	closeMessage := websocket.FormatCloseMessage(websocket.CloseGoingAway, "server is shutting down")
+	for _, userClients := range ws.clients {
+		for client := range userClients {
+			if err := client.conn.WriteControl(websocket.CloseMessage, closeMessage, time.Now().Add(closeWriteWait)); err != nil {
+				client.conn.Close()
+			}
+		}
+	}
+	ws.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		ws.conns.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return err
+	case <-ctx.Done():
+		ws.mu.Lock()
+		for _, userClients := range ws.clients {
+			for client := range userClients {
+				client.conn.Close()
+			}
+		}
+		ws.mu.Unlock()
+		return ctx.Err()
+	}
 }
 
 // wsHandler godoc
 // @Summary WebSocket endpoint
-// @Description Establish WebSocket connection
+// @Description Establish WebSocket connection. The JWT is passed either in the
+// @Description token query parameter or as Sec-WebSocket-Protocol: bearer, <token>
 // @Tags websocket
 // @Schemes ws
+// @Param token query string false "access token"
 // @Success 101 {string} string "Switching Protocols"
+// @Failure 401 {string} string "Unauthorized"
 // @Router /ws [get]
 func (ws *wsSrv) wsHandler(c *gin.Context) {
+	token := requestToken(c.Request)
+	if token == "" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"message": "empty token"})
+		return
+	}
+
+	ctx, cancel := ws.queryContext(c.Request.Context())
+	userId, err := ws.services.Authorization.ParseToken(ctx, token)
+	cancel()
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"message": err.Error()})
+		return
+	}
+
 	conn, err := ws.wsUpg.Upgrade(c.Writer, c.Request, nil)
 	if err != nil {
 		logrus.Errorf("WebSocket upgrade error: %v", err)
@@ -97,12 +199,22 @@ func (ws *wsSrv) wsHandler(c *gin.Context) {
 	}
 
 	client := &Client{
-		conn: conn,
-		send: make(chan []byte, 256),
+		userId: userId,
+		conn:   conn,
+		send:   make(chan []byte, 256),
 	}
 
 	ws.mu.Lock()
-	ws.clients[client] = true
+	if ws.closing {
+		ws.mu.Unlock()
+		conn.Close()
+		return
+	}
+	if ws.clients[userId] == nil {
+		ws.clients[userId] = make(map[*Client]bool)
+	}
+	ws.clients[userId][client] = true
+	ws.conns.Add(1)
 	ws.mu.Unlock()
 
 	go ws.writePump(client)
@@ -111,14 +223,39 @@ func (ws *wsSrv) wsHandler(c *gin.Context) {
 	ws.sendTodos(client)
 }
 
+// requestToken extracts the access token from the token query parameter or,
+// for browsers that cannot set headers, from the Sec-WebSocket-Protocol list.
+func requestToken(r *http.Request) string {
+	if token := r.URL.Query().Get("token"); token != "" {
+		return token
+	}
+
+	protocols := websocket.Subprotocols(r)
+	for i, protocol := range protocols {
+		if strings.EqualFold(protocol, tokenSubprotocol) && i+1 < len(protocols) {
+			return protocols[i+1]
+		}
+	}
+
+	return ""
+}
+
+func (ws *wsSrv) removeClient(client *Client) {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+
+	delete(ws.clients[client.userId], client)
+	if len(ws.clients[client.userId]) == 0 {
+		delete(ws.clients, client.userId)
+	}
+}
+
 func (ws *wsSrv) writePump(client *Client) {
 	ticker := time.NewTicker(30 * time.Second)
 	defer func() {
 		ticker.Stop()
 		client.conn.Close()
-		ws.mu.Lock()
-		delete(ws.clients, client)
-		ws.mu.Unlock()
+		ws.removeClient(client)
 	}()
 
 	for {
@@ -144,9 +281,8 @@ func (ws *wsSrv) writePump(client *Client) {
 func (ws *wsSrv) readPump(client *Client) {
 	defer func() {
 		client.conn.Close()
-		ws.mu.Lock()
-		delete(ws.clients, client)
-		ws.mu.Unlock()
+		ws.removeClient(client)
+		ws.conns.Done()
 	}()
 
 	client.conn.SetReadLimit(512)
@@ -172,7 +308,7 @@ func (ws *wsSrv) readPump(client *Client) {
 func (ws *wsSrv) handleMessage(client *Client, message []byte) {
 	var msg struct {
 		Action string `json:"action"`
-		TodoID string `json:"todoId"`
+		ItemId int    `json:"item_id"`
 	}
 
 	if err := json.Unmarshal(message, &msg); err != nil {
@@ -182,63 +318,53 @@ func (ws *wsSrv) handleMessage(client *Client, message []byte) {
 
 	switch msg.Action {
 	case "complete":
-		ws.completeTodo(msg.TodoID)
+		ctx, cancel := ws.queryContext(ws.ctx)
+		defer cancel()
+
+		done := true
+		if err := ws.services.TodoItem.Update(ctx, client.userId, msg.ItemId, todo.UpdateItemInput{Done: &done}); err != nil {
+			logrus.Errorf("Error completing item %d: %v", msg.ItemId, err)
+		}
 	}
 }
 
 func (ws *wsSrv) sendTodos(client *Client) {
-	ws.mu.Lock()
-	todos := make([]Todo, 0, len(ws.todos))
-	for _, todo := range ws.todos {
-		todos = append(todos, todo)
-	}
-	ws.mu.Unlock()
+	ctx, cancel := ws.queryContext(ws.ctx)
+	defer cancel()
 
-	message, err := json.Marshal(map[string]interface{}{
-		"type":  "todos",
-		"todos": todos,
-	})
+	now := time.Now()
+	due, err := ws.services.TodoItem.GetDueBetween(ctx, now, now.Add(deadlineSoonWindow))
 	if err != nil {
-		logrus.Errorf("Error marshaling todos: %v", err)
+		logrus.Errorf("Error loading todos: %v", err)
 		return
 	}
 
-	client.send <- message
-}
-
-func (ws *wsSrv) broadcastTodos() {
-	ws.mu.Lock()
-	defer ws.mu.Unlock()
-
-	todos := make([]Todo, 0, len(ws.todos))
-	for _, todo := range ws.todos {
-		todos = append(todos, todo)
+	items := make([]todo.TodoItem, 0)
+	for _, item := range due {
+		if item.UserId == client.userId {
+			items = append(items, item.TodoItem)
+		}
 	}
 
 	message, err := json.Marshal(map[string]interface{}{
 		"type":  "todos",
-		"todos": todos,
+		"todos": items,
 	})
 	if err != nil {
 		logrus.Errorf("Error marshaling todos: %v", err)
 		return
 	}
 
-	for client := range ws.clients {
-		select {
-		case client.send <- message:
-		default:
-			close(client.send)
-			delete(ws.clients, client)
-		}
-	}
+	ws.sendTo(client, message)
 }
 
-func (ws *wsSrv) sendNotification(notificationType, task string, deadline time.Time, message string) {
+// sendNotification delivers the notification to every connection of the given users.
+func (ws *wsSrv) sendNotification(userIds []int, notificationType string, item todo.TodoItem, message string) {
 	notification := Notification{
 		Type:     notificationType,
-		Task:     task,
-		Deadline: deadline,
+		ItemId:   item.Id,
+		Task:     item.Title,
+		Deadline: *item.Deadline,
 		Message:  message,
 	}
 
@@ -248,133 +374,130 @@ func (ws *wsSrv) sendNotification(notificationType, task string, deadline time.T
 		return
 	}
 
-	ws.mu.Lock()
-	defer ws.mu.Unlock()
+	ws.send(userIds, msg)
+}
 
-	for client := range ws.clients {
-		select {
-		case client.send <- msg:
-		default:
-			close(client.send)
-			delete(ws.clients, client)
-		}
+// sendEvent delivers an event of the services, e.g. a new comment, to its recipients.
+func (ws *wsSrv) sendEvent(event service.Event) {
+	msg, err := json.Marshal(event)
+	if err != nil {
+		logrus.Errorf("Error marshaling %s event: %v", event.Type, err)
+		return
 	}
+
+	ws.send(event.Recipients, msg)
 }
 
-func (ws *wsSrv) checkDeadlines() {
-	ticker := time.NewTicker(1 * time.Minute)
-	defer ticker.Stop()
+// send queues the message for every connection of the given users. Clients
+// that can't keep up are dropped.
+func (ws *wsSrv) send(userIds []int, msg []byte) {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
 
-	for {
-		select {
-		case <-ticker.C:
-			ws.mu.Lock()
-			for id, todo := range ws.todos {
-				if todo.Done {
-					continue
-				}
-
-				remaining := time.Until(todo.Deadline)
-				if remaining <= 0 {
-					ws.sendNotification("deadline_passed", todo.Task, todo.Deadline, "Deadline has passed!")
-					todo.Done = true
-					ws.todos[id] = todo
-				} else if remaining <= 30*time.Minute {
-					ws.sendNotification("deadline_soon", todo.Task, todo.Deadline,
-						"Deadline is approaching! "+formatDuration(remaining))
-				}
-			}
-			ws.mu.Unlock()
+	for _, userId := range userIds {
+		for client := range ws.clients[userId] {
+			ws.queue(client, msg)
 		}
 	}
 }
 
-func formatDuration(d time.Duration) string {
-	d = d.Round(time.Minute)
-	h := d / time.Hour
-	d -= h * time.Hour
-	m := d / time.Minute
-	return h.String() + "h" + m.String() + "m"
-}
-
-func (ws *wsSrv) createTodoHandler(c *gin.Context) {
-	var todo struct {
-		Task     string    `json:"task"`
-		Deadline time.Time `json:"deadline"`
-	}
-
-	if err := c.ShouldBindJSON(&todo); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		return
-	}
+// sendTo queues the message for one connection, unless it has been dropped
+// or has hung up in the meantime.
+func (ws *wsSrv) sendTo(client *Client, msg []byte) {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
 
-	newTodo := Todo{
-		Task:     todo.Task,
-		Deadline: todo.Deadline,
-		Done:     false,
+	if ws.clients[client.userId][client] {
+		ws.queue(client, msg)
 	}
-
-	ws.addTodo(newTodo)
-	c.JSON(http.StatusCreated, newTodo)
 }
 
-func (ws *wsSrv) addTodo(todo Todo) {
-	ws.mu.Lock()
-	todo.ID = generateID()
-	ws.todos[todo.ID] = todo
-	ws.mu.Unlock()
-
-	ws.broadcastTodos()
-	go ws.checkDeadline(todo.ID)
+// queue hands the message to the write pump or, when its buffer is full,
+// drops the client. The caller holds ws.mu, so the send channel is closed
+// only once and never written to afterwards.
+func (ws *wsSrv) queue(client *Client, msg []byte) {
+	select {
+	case client.send <- msg:
+	default:
+		close(client.send)
+		delete(ws.clients[client.userId], client)
+	}
 }
 
-func (ws *wsSrv) completeTodo(todoID string) {
-	ws.mu.Lock()
-	defer ws.mu.Unlock()
+func (ws *wsSrv) checkDeadlines() {
+	ticker := time.NewTicker(deadlineCheckInterval)
+	defer ticker.Stop()
 
-	if todo, exists := ws.todos[todoID]; exists {
-		todo.Done = true
-		ws.todos[todoID] = todo
-		ws.broadcastTodos()
+	lastCheck := time.Now()
+	for {
+		select {
+		case now := <-ticker.C:
+			ws.notifyDeadlines(lastCheck, now)
+			lastCheck = now
+		case <-ws.ctx.Done():
+			return
+		}
 	}
 }
 
-func (ws *wsSrv) checkDeadline(todoID string) {
-	ws.mu.Lock()
-	todo, exists := ws.todos[todoID]
-	ws.mu.Unlock()
+// notifyDeadlines sends deadline_passed for items whose deadline expired since
+// the previous check and deadline_soon for items due within deadlineSoonWindow.
+func (ws *wsSrv) notifyDeadlines(since, now time.Time) {
+	ctx, cancel := ws.queryContext(ws.ctx)
+	defer cancel()
 
-	if !exists || todo.Done {
+	due, err := ws.services.TodoItem.GetDueBetween(ctx, since, now.Add(deadlineSoonWindow))
+	if err != nil {
+		logrus.Errorf("Error loading items with deadlines: %v", err)
 		return
 	}
 
-	for {
-		time.Sleep(1 * time.Minute)
+	items := make([]todo.TodoItem, 0)
+	recipients := make(map[int][]int)
+	for _, item := range due {
+		if _, ok := recipients[item.Id]; !ok {
+			items = append(items, item.TodoItem)
+		}
+		recipients[item.Id] = append(recipients[item.Id], item.UserId)
+	}
 
-		ws.mu.Lock()
-		todo, exists = ws.todos[todoID]
-		if !exists || todo.Done {
-			ws.mu.Unlock()
-			return
+	for id, deadline := range ws.announced {
+		if !deadline.After(now) {
+			delete(ws.announced, id)
 		}
+	}
 
-		remaining := time.Until(todo.Deadline)
+	for _, item := range items {
+		deadline := *item.Deadline
+		remaining := deadline.Sub(now)
 		if remaining <= 0 {
-			ws.sendNotification("deadline_passed", todo.Task, todo.Deadline, "Deadline has passed!")
-			todo.Done = true
-			ws.todos[todoID] = todo
-			ws.mu.Unlock()
-			return
-		} else if remaining <= 30*time.Minute {
-			ws.sendNotification("deadline_soon", todo.Task, todo.Deadline,
-				"Deadline is approaching! "+formatDuration(remaining))
+			ws.sendNotification(recipients[item.Id], "deadline_passed", item, "Deadline has passed!")
+			continue
 		}
-		ws.mu.Unlock()
+
+		if announced, ok := ws.announced[item.Id]; ok && announced.Equal(deadline) {
+			continue
+		}
+		ws.announced[item.Id] = deadline
+		ws.sendNotification(recipients[item.Id], "deadline_soon", item, "Deadline is approaching! "+formatDuration(remaining))
 	}
 }
 
-func generateID() string {
-	return time.Now().Format("20060102150405")
+// queryContext limits the queries made on behalf of one event to queryTimeout.
+func (ws *wsSrv) queryContext(parent context.Context) (context.Context, context.CancelFunc) {
+	if ws.queryTimeout <= 0 {
+		return context.WithCancel(parent)
+	}
+
+	return context.WithTimeout(parent, ws.queryTimeout)
+}
+
+func formatDuration(d time.Duration) string {
+	d = d.Round(time.Minute)
+	h := d / time.Hour
+	d -= h * time.Hour
+	m := d / time.Minute
+	return strconv.Itoa(int(h)) + "h" + strconv.Itoa(int(m)) + "m"
 }
 
 // testHandler godoc
@@ -387,4 +510,4 @@ func generateID() string {
 // @Router /test [get]
 func (ws *wsSrv) testHandler(c *gin.Context) {
 	c.String(http.StatusOK, "Test is successful")
-}
\ No newline at end of file
+}
@@ -0,0 +1,148 @@
+package wsserver
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/websocket"
+	"github.com/lypolix/todo-app"
+	"github.com/lypolix/todo-app/pkg/service"
+	mock_service "github.com/lypolix/todo-app/pkg/service/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWsServer_handshake(t *testing.T) {
+	type mockBehavior func(auth *mock_service.MockAuthorization, items *mock_service.MockTodoItem)
+
+	deadline := time.Now().Add(10 * time.Minute)
+
+	testTable := []struct {
+		name                string
+		query               string
+		subprotocols        []string
+		mockBehavior        mockBehavior
+		expectedStatusCode  int
+		expectedRequestBody string
+		expectedTodos       []int
+	}{
+		{
+			name:                "Missing Token",
+			mockBehavior:        func(auth *mock_service.MockAuthorization, items *mock_service.MockTodoItem) {},
+			expectedStatusCode:  401,
+			expectedRequestBody: `{"message":"empty token"}`,
+		},
+		{
+			name:  "Invalid Token",
+			query: "?token=forged",
+			mockBehavior: func(auth *mock_service.MockAuthorization, items *mock_service.MockTodoItem) {
+				auth.EXPECT().ParseToken(gomock.Any(), "forged").Return(0, todo.ErrInvalidToken)
+			},
+			expectedStatusCode:  401,
+			expectedRequestBody: `{"message":"invalid access token"}`,
+		},
+		{
+			name:  "Token In Query",
+			query: "?token=valid",
+			mockBehavior: func(auth *mock_service.MockAuthorization, items *mock_service.MockTodoItem) {
+				auth.EXPECT().ParseToken(gomock.Any(), "valid").Return(2, nil)
+				items.EXPECT().GetDueBetween(gomock.Any(), gomock.Any(), gomock.Any()).Return([]todo.UserItem{
+					{UserId: 2, TodoItem: todo.TodoItem{Id: 5, Title: "Milk", Deadline: &deadline}},
+					{UserId: 3, TodoItem: todo.TodoItem{Id: 6, Title: "Bread", Deadline: &deadline}},
+				}, nil)
+			},
+			expectedStatusCode: 101,
+			expectedTodos:      []int{5},
+		},
+		{
+			name:         "Token In Subprotocol",
+			subprotocols: []string{tokenSubprotocol, "valid"},
+			mockBehavior: func(auth *mock_service.MockAuthorization, items *mock_service.MockTodoItem) {
+				auth.EXPECT().ParseToken(gomock.Any(), "valid").Return(2, nil)
+				items.EXPECT().GetDueBetween(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil)
+			},
+			expectedStatusCode: 101,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			auth := mock_service.NewMockAuthorization(c)
+			items := mock_service.NewMockTodoItem(c)
+			testCase.mockBehavior(auth, items)
+
+			ws := NewWsServer(":0", &service.Service{Authorization: auth, TodoItem: items}, time.Second).(*wsSrv)
+
+			r := gin.New()
+			r.GET("/ws", ws.wsHandler)
+			srv := httptest.NewServer(r)
+			defer srv.Close()
+
+			dialer := websocket.Dialer{Subprotocols: testCase.subprotocols}
+			conn, resp, err := dialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http")+"/ws"+testCase.query, nil)
+
+			if assert.NotNil(t, resp) {
+				assert.Equal(t, testCase.expectedStatusCode, resp.StatusCode)
+			}
+			if testCase.expectedStatusCode != http.StatusSwitchingProtocols {
+				assert.ErrorIs(t, err, websocket.ErrBadHandshake)
+				body, _ := io.ReadAll(resp.Body)
+				assert.Equal(t, testCase.expectedRequestBody, string(body))
+				return
+			}
+			if !assert.NoError(t, err) {
+				return
+			}
+			defer conn.Close()
+
+			if testCase.subprotocols != nil {
+				assert.Equal(t, tokenSubprotocol, conn.Subprotocol())
+			}
+
+			// the first message lists the items due soon of this user only
+			var msg struct {
+				Type  string          `json:"type"`
+				Todos []todo.TodoItem `json:"todos"`
+			}
+			conn.SetReadDeadline(time.Now().Add(time.Second))
+			_, data, err := conn.ReadMessage()
+			assert.NoError(t, err)
+			assert.NoError(t, json.Unmarshal(data, &msg))
+			assert.Equal(t, "todos", msg.Type)
+			ids := make([]int, 0)
+			for _, item := range msg.Todos {
+				ids = append(ids, item.Id)
+			}
+			if testCase.expectedTodos == nil {
+				testCase.expectedTodos = []int{}
+			}
+			assert.Equal(t, testCase.expectedTodos, ids)
+		})
+	}
+}
+
+func TestWsServer_sendToDroppedClient(t *testing.T) {
+	ws := &wsSrv{clients: make(map[int]map[*Client]bool)}
+	client := &Client{userId: 2, send: make(chan []byte, 1)}
+	ws.clients[2] = map[*Client]bool{client: true}
+
+	// the second message overflows the buffer, so the client is dropped and
+	// its channel closed
+	ws.send([]int{2}, []byte("first"))
+	ws.send([]int{2}, []byte("second"))
+	assert.False(t, ws.clients[2][client])
+
+	// sending the initial todos afterwards must not write to the closed channel
+	assert.NotPanics(t, func() {
+		ws.sendTo(client, []byte("todos"))
+	})
+}
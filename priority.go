@@ -0,0 +1,57 @@
+package todo
+
+import (
+	"encoding/json"
+	"strconv"
+)
+
+// Priority of an item. It is stored as a number so items sort by it, and
+// clients see it by name.
+type Priority int
+
+const (
+	PriorityNone Priority = iota
+	PriorityLow
+	PriorityMedium
+	PriorityHigh
+	PriorityUrgent
+)
+
+var priorityNames = []string{"none", "low", "medium", "high", "urgent"}
+
+func ParsePriority(name string) (Priority, error) {
+	for i, priorityName := range priorityNames {
+		if name == priorityName {
+			return Priority(i), nil
+		}
+	}
+
+	return PriorityNone, ErrInvalidPriority
+}
+
+func (p Priority) String() string {
+	if p < PriorityNone || p > PriorityUrgent {
+		return strconv.Itoa(int(p))
+	}
+
+	return priorityNames[p]
+}
+
+func (p Priority) MarshalJSON() ([]byte, error) {
+	return json.Marshal(p.String())
+}
+
+func (p *Priority) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err != nil {
+		return ErrInvalidPriority
+	}
+
+	priority, err := ParsePriority(name)
+	if err != nil {
+		return err
+	}
+
+	*p = priority
+	return nil
+}
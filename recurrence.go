@@ -0,0 +1,22 @@
+package todo
+
+import "time"
+
+// Scopes of an edit of a recurring item.
+const (
+	ScopeThis   = "this"
+	ScopeFuture = "future"
+)
+
+// ItemSeries is the template the occurrences of a recurring item are
+// generated from. RRule is an RFC 5545 recurrence rule anchored at DtStart;
+// an empty rule means the series has ended.
+type ItemSeries struct {
+	Id          int       `json:"id" db:"id"`
+	RRule       string    `json:"rrule" db:"rrule"`
+	Title       string    `json:"title" db:"title"`
+	Description string    `json:"description" db:"description"`
+	DtStart     time.Time `json:"dtstart" db:"dtstart"`
+	// ListId is the list all items of the series are in
+	ListId int `json:"list_id" db:"list_id"`
+}
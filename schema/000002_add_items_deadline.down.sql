@@ -0,0 +1,3 @@
+DROP INDEX todo_items_deadline_idx;
+
+ALTER TABLE todo_items DROP COLUMN deadline;
@@ -0,0 +1,3 @@
+ALTER TABLE todo_items ADD COLUMN deadline timestamptz;
+
+CREATE INDEX todo_items_deadline_idx ON todo_items (deadline) WHERE done = false AND deadline IS NOT NULL;
@@ -0,0 +1,5 @@
+ALTER TABLE users DROP COLUMN tokens_revoked_at;
+
+DROP TABLE revoked_tokens;
+
+DROP TABLE refresh_tokens;
@@ -0,0 +1,18 @@
+CREATE TABLE refresh_tokens
+(
+    id serial not null unique,
+    user_id int references users (id) on delete cascade not null,
+    token_hash varchar(64) not null unique,
+    expires_at timestamptz not null,
+    created_at timestamptz not null default now(),
+    revoked_at timestamptz,
+    replaced_by int references refresh_tokens (id) on delete set null
+);
+
+CREATE TABLE revoked_tokens
+(
+    jti varchar(64) not null primary key,
+    expires_at timestamptz not null
+);
+
+ALTER TABLE users ADD COLUMN tokens_revoked_at timestamptz;
@@ -0,0 +1,3 @@
+DROP INDEX users_lists_user_list_idx;
+
+ALTER TABLE users_lists DROP COLUMN role;
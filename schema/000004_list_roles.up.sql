@@ -0,0 +1,5 @@
+ALTER TABLE users_lists ADD COLUMN role varchar(16) not null default 'owner';
+
+ALTER TABLE users_lists ADD CONSTRAINT users_lists_role_check CHECK (role IN ('owner', 'editor', 'viewer'));
+
+CREATE UNIQUE INDEX users_lists_user_list_idx ON users_lists (user_id, list_id);
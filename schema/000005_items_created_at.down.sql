@@ -0,0 +1,3 @@
+DROP INDEX lists_items_list_id_idx;
+
+ALTER TABLE todo_items DROP COLUMN created_at;
@@ -0,0 +1,3 @@
+ALTER TABLE todo_items ADD COLUMN created_at timestamptz not null default now();
+
+CREATE INDEX lists_items_list_id_idx ON lists_items (list_id);
@@ -0,0 +1,6 @@
+DROP INDEX todo_items_series_recurrence_idx;
+
+ALTER TABLE todo_items DROP COLUMN recurrence_id;
+ALTER TABLE todo_items DROP COLUMN series_id;
+
+DROP TABLE item_series;
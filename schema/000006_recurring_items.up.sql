@@ -0,0 +1,13 @@
+CREATE TABLE item_series
+(
+    id serial not null unique,
+    rrule varchar(512) not null,
+    title varchar(255) not null,
+    description varchar(255),
+    dtstart timestamptz not null
+);
+
+ALTER TABLE todo_items ADD COLUMN series_id int references item_series (id) on delete set null;
+ALTER TABLE todo_items ADD COLUMN recurrence_id timestamptz;
+
+CREATE UNIQUE INDEX todo_items_series_recurrence_idx ON todo_items (series_id, recurrence_id);
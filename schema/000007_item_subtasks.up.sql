@@ -0,0 +1,12 @@
+CREATE TABLE item_subtasks
+(
+    id serial not null unique,
+    item_id int references todo_items (id) on delete cascade not null,
+    title varchar(255) not null,
+    done boolean not null default false,
+    position int not null
+);
+
+CREATE INDEX item_subtasks_item_id_idx ON item_subtasks (item_id, position);
+
+ALTER TABLE todo_items ADD COLUMN auto_complete boolean not null default false;
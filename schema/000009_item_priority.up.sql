@@ -0,0 +1,3 @@
+ALTER TABLE todo_items ADD COLUMN priority smallint not null default 0;
+
+CREATE INDEX todo_items_open_deadline_idx ON todo_items (deadline) WHERE not done;
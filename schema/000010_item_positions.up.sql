@@ -0,0 +1,9 @@
+ALTER TABLE lists_items ADD COLUMN position text COLLATE "C";
+
+UPDATE lists_items li SET position = p.position FROM (
+    SELECT id, lpad(to_hex(row_number() OVER (PARTITION BY list_id ORDER BY item_id)), 8, '0') AS position FROM lists_items
+) p WHERE p.id = li.id;
+
+ALTER TABLE lists_items ALTER COLUMN position SET NOT NULL;
+
+CREATE INDEX lists_items_list_position_idx ON lists_items (list_id, position);
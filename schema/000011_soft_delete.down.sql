@@ -0,0 +1,7 @@
+DROP INDEX todo_items_deleted_at_idx;
+
+DROP INDEX todo_lists_deleted_at_idx;
+
+ALTER TABLE todo_items DROP COLUMN deleted_at;
+
+ALTER TABLE todo_lists DROP COLUMN deleted_at;
@@ -0,0 +1,14 @@
+CREATE TABLE activity
+(
+    id serial not null unique,
+    actor_id int references users (id) on delete set null,
+    list_id int references todo_lists (id) on delete cascade not null,
+    item_id int references todo_items (id) on delete cascade,
+    action varchar(16) not null,
+    changes jsonb not null default '{}',
+    created_at timestamptz not null default now()
+);
+
+CREATE INDEX activity_list_id_idx ON activity (list_id, id);
+
+CREATE INDEX activity_item_id_idx ON activity (item_id, id) WHERE item_id IS NOT NULL;
@@ -0,0 +1,12 @@
+CREATE TABLE item_comments
+(
+    id serial not null unique,
+    item_id int references todo_items (id) on delete cascade not null,
+    parent_id int references item_comments (id) on delete cascade,
+    author_id int references users (id) on delete cascade not null,
+    body text not null,
+    created_at timestamptz not null default now(),
+    edited_at timestamptz
+);
+
+CREATE INDEX item_comments_item_id_idx ON item_comments (item_id, id);
@@ -0,0 +1,16 @@
+-- files of purged items are not deleted together with them: the row loses its
+-- item and the purger removes the file before deleting the row
+CREATE TABLE item_attachments
+(
+    id serial not null unique,
+    item_id int references todo_items (id) on delete set null,
+    user_id int references users (id) on delete cascade not null,
+    file_name varchar(255) not null,
+    content_type varchar(255) not null,
+    size bigint not null,
+    storage_key varchar(64) not null unique,
+    created_at timestamptz not null default now()
+);
+
+CREATE INDEX item_attachments_item_id_idx ON item_attachments (item_id);
+CREATE INDEX item_attachments_user_id_idx ON item_attachments (user_id);
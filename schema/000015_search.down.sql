@@ -0,0 +1,11 @@
+DROP INDEX item_comments_search_idx;
+
+DROP INDEX todo_items_search_idx;
+
+DROP INDEX todo_lists_search_idx;
+
+ALTER TABLE item_comments DROP COLUMN search;
+
+ALTER TABLE todo_items DROP COLUMN search;
+
+ALTER TABLE todo_lists DROP COLUMN search;
@@ -0,0 +1,17 @@
+ALTER TABLE todo_lists ADD COLUMN search tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('simple', title), 'A') || setweight(to_tsvector('simple', coalesce(description, '')), 'B')
+) STORED;
+
+ALTER TABLE todo_items ADD COLUMN search tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('simple', title), 'A') || setweight(to_tsvector('simple', coalesce(description, '')), 'B')
+) STORED;
+
+ALTER TABLE item_comments ADD COLUMN search tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('simple', body), 'C')
+) STORED;
+
+CREATE INDEX todo_lists_search_idx ON todo_lists USING gin (search);
+
+CREATE INDEX todo_items_search_idx ON todo_items USING gin (search);
+
+CREATE INDEX item_comments_search_idx ON item_comments USING gin (search);
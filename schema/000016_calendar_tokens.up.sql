@@ -0,0 +1,6 @@
+CREATE TABLE calendar_tokens
+(
+    user_id int references users (id) on delete cascade not null primary key,
+    token_hash varchar(64) not null unique,
+    created_at timestamptz not null default now()
+);
@@ -0,0 +1,3 @@
+DROP TABLE caldav_objects;
+
+ALTER TABLE todo_items DROP COLUMN version;
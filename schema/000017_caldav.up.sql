@@ -0,0 +1,10 @@
+ALTER TABLE todo_items ADD COLUMN version int not null default 1;
+
+-- resources created by CalDAV clients keep the name and UID the client chose,
+-- other items are served as <id>.ics
+CREATE TABLE caldav_objects
+(
+    item_id int references todo_items (id) on delete cascade not null primary key,
+    name varchar(255) not null unique,
+    uid varchar(255) not null
+);
@@ -0,0 +1 @@
+ALTER TABLE todo_lists ADD COLUMN version int not null default 1;
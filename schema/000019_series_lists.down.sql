@@ -0,0 +1 @@
+ALTER TABLE item_series DROP COLUMN list_id;
@@ -0,0 +1,12 @@
+ALTER TABLE item_series ADD COLUMN list_id int references todo_lists (id) on delete cascade;
+
+-- a series belongs to the list of the item that started it, items of other lists leave the series
+UPDATE item_series s SET list_id = li.list_id FROM lists_items li
+WHERE li.item_id = (SELECT min(ti.id) FROM todo_items ti WHERE ti.series_id = s.id);
+
+UPDATE todo_items ti SET series_id = NULL, recurrence_id = NULL FROM lists_items li, item_series s
+WHERE li.item_id = ti.id AND s.id = ti.series_id AND li.list_id <> s.list_id;
+
+DELETE FROM item_series WHERE list_id IS NULL;
+
+ALTER TABLE item_series ALTER COLUMN list_id SET NOT NULL;
@@ -0,0 +1,4 @@
+DROP INDEX caldav_objects_list_name_idx;
+
+ALTER TABLE caldav_objects ADD CONSTRAINT caldav_objects_name_key UNIQUE (name);
+ALTER TABLE caldav_objects DROP COLUMN list_id;
@@ -0,0 +1,9 @@
+-- resource names are unique within a calendar, not across all of them
+ALTER TABLE caldav_objects ADD COLUMN list_id int references todo_lists (id) on delete cascade;
+
+UPDATE caldav_objects co SET list_id = li.list_id FROM lists_items li WHERE li.item_id = co.item_id;
+
+ALTER TABLE caldav_objects ALTER COLUMN list_id SET NOT NULL;
+ALTER TABLE caldav_objects DROP CONSTRAINT caldav_objects_name_key;
+
+CREATE UNIQUE INDEX caldav_objects_list_name_idx ON caldav_objects (list_id, name);
@@ -0,0 +1,4 @@
+-- replies outlive the comment they answer, they are kept as top-level comments
+ALTER TABLE item_comments DROP CONSTRAINT item_comments_parent_id_fkey;
+ALTER TABLE item_comments ADD CONSTRAINT item_comments_parent_id_fkey
+    FOREIGN KEY (parent_id) REFERENCES item_comments (id) ON DELETE SET NULL;
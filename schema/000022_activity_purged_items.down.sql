@@ -0,0 +1,3 @@
+ALTER TABLE activity DROP CONSTRAINT activity_item_id_fkey;
+ALTER TABLE activity ADD CONSTRAINT activity_item_id_fkey
+    FOREIGN KEY (item_id) REFERENCES todo_items (id) ON DELETE CASCADE;
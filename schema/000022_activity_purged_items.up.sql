@@ -0,0 +1,4 @@
+-- the history of a list keeps the entries of items purged from the trash
+ALTER TABLE activity DROP CONSTRAINT activity_item_id_fkey;
+ALTER TABLE activity ADD CONSTRAINT activity_item_id_fkey
+    FOREIGN KEY (item_id) REFERENCES todo_items (id) ON DELETE SET NULL;
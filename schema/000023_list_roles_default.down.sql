@@ -0,0 +1 @@
+ALTER TABLE users_lists ALTER COLUMN role SET DEFAULT 'owner';
@@ -0,0 +1,2 @@
+-- the default only backfilled existing rows; every membership names its role
+ALTER TABLE users_lists ALTER COLUMN role DROP DEFAULT;
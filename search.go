@@ -0,0 +1,48 @@
+package todo
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// Types of search results.
+const (
+	SearchList    = "list"
+	SearchItem    = "item"
+	SearchComment = "comment"
+
+	DefaultSearchLimit = 20
+	MaxSearchLimit     = 100
+	MaxSearchLength    = 256
+)
+
+// SearchQuery asks for the Limit best matches of the words in Query.
+type SearchQuery struct {
+	Query string
+	Limit int
+}
+
+func (q SearchQuery) Validate() error {
+	if strings.TrimSpace(q.Query) == "" || utf8.RuneCountInString(q.Query) > MaxSearchLength {
+		return ErrInvalidSearchQuery
+	}
+
+	if q.Limit < 1 || q.Limit > MaxSearchLimit {
+		return NewError(ErrInvalidInput, "invalid_filter", "limit is out of range")
+	}
+
+	return nil
+}
+
+// SearchResult is a list, an item or a comment matching a search. Title is
+// the title of the list or item, for comments the one of their item, and
+// Snippet is the matching text with the matches highlighted.
+type SearchResult struct {
+	Type    string  `json:"type" db:"type"`
+	Id      int     `json:"id" db:"id"`
+	ListId  int     `json:"list_id" db:"list_id"`
+	ItemId  *int    `json:"item_id,omitempty" db:"item_id"`
+	Title   string  `json:"title" db:"title"`
+	Snippet string  `json:"snippet" db:"snippet"`
+	Rank    float64 `json:"rank" db:"rank"`
+}
@@ -2,26 +2,83 @@ package todo
 
 import (
 	"context"
+	"net"
 	"net/http"
+	"sync"
 	"time"
 )
 
 type Server struct {
+	mu sync.Mutex
 	httpServer *http.Server
+	cancel context.CancelFunc
+	closed bool
 }
 
 func (s *Server) Run(port string, handler http.Handler) error{
+	ctx, cancel := context.WithCancel(context.Background())
+
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		cancel()
+		return http.ErrServerClosed
+	}
+	s.cancel = cancel
 	s.httpServer = &http.Server{
 		Addr: ":" + port,
 		Handler: handler,
 		MaxHeaderBytes: 1 << 20,
 		ReadTimeout: 10 * time.Second,
 		WriteTimeout: 10 * time.Second,
+		BaseContext: func(net.Listener) context.Context {
+			return ctx
+		},
 	}
-	return s.httpServer.ListenAndServe()
+	srv := s.httpServer
+	s.mu.Unlock()
+
+	return srv.ListenAndServe()
 }
 
 
+// Shutdown may be called from another goroutine while Run is starting, so
+// the server is only read under the lock. A Run that has not started yet
+// returns http.ErrServerClosed.
 func (s *Server) Shutdown(ctx context.Context) error {
-	return s.httpServer.Shutdown(ctx)
+	s.mu.Lock()
+	s.closed = true
+	srv, cancel := s.httpServer, s.cancel
+	s.mu.Unlock()
+
+	if srv == nil {
+		return nil
+	}
+	// requests still running when ctx expires get their queries cancelled
+	defer cancel()
+	return srv.Shutdown(ctx)
+}
+
+// Shutdowner is a server that stops gracefully within the deadline of ctx.
+type Shutdowner interface {
+	Shutdown(ctx context.Context) error
+}
+
+// ShutdownAll shuts the servers down concurrently, so a server waiting for
+// long-lived connections, e.g. websocket clients, does not use up the deadline
+// the others need to drain their requests. Errors are in the order of servers.
+func ShutdownAll(ctx context.Context, servers ...Shutdowner) []error {
+	errs := make([]error, len(servers))
+
+	var wg sync.WaitGroup
+	for i, srv := range servers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = srv.Shutdown(ctx)
+		}()
+	}
+	wg.Wait()
+
+	return errs
 }
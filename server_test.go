@@ -0,0 +1,104 @@
+package todo_test
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/lypolix/todo-app"
+	"github.com/stretchr/testify/assert"
+)
+
+// connectedServer stands for the websocket server with clients that do not
+// leave, so it only stops when the deadline expires.
+type connectedServer struct{}
+
+func (connectedServer) Shutdown(ctx context.Context) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func freePort(t *testing.T) string {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	_, port, _ := net.SplitHostPort(l.Addr().String())
+	return port
+}
+
+func TestShutdownAll(t *testing.T) {
+	port := freePort(t)
+	url := "http://127.0.0.1:" + port + "/"
+
+	started := make(chan struct{}, 1)
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		started <- struct{}{}
+		select {
+		case <-time.After(200 * time.Millisecond):
+			io.WriteString(w, "done")
+		case <-r.Context().Done():
+		}
+	})
+
+	srv := new(todo.Server)
+	go srv.Run(port, handler)
+
+	// wait until the server listens
+	for i := 0; ; i++ {
+		conn, err := net.Dial("tcp", "127.0.0.1:"+port)
+		if err == nil {
+			conn.Close()
+			break
+		}
+		if i == 100 {
+			t.Fatal(err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	type response struct {
+		body string
+		err  error
+	}
+	responses := make(chan response, 1)
+	go func() {
+		resp, err := http.Get(url)
+		if err != nil {
+			responses <- response{err: err}
+			return
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		responses <- response{body: string(body), err: err}
+	}()
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	errs := make(chan []error, 1)
+	go func() {
+		// the websocket server goes first, as it did before servers were
+		// shut down together
+		errs <- todo.ShutdownAll(ctx, connectedServer{}, srv)
+	}()
+
+	// the request in flight is drained while websocket clients are connected
+	got := <-responses
+	assert.NoError(t, got.err)
+	assert.Equal(t, "done", got.body)
+
+	// and the server takes no new requests in the meantime
+	client := &http.Client{Transport: &http.Transport{DisableKeepAlives: true}}
+	_, err := client.Get(url)
+	assert.Error(t, err)
+
+	result := <-errs
+	assert.ErrorIs(t, result[0], context.DeadlineExceeded)
+	assert.NoError(t, result[1])
+}
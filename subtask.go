@@ -0,0 +1,29 @@
+package todo
+
+// Subtask is a checklist entry of an item. Subtasks of an item are ordered by
+// Position, starting at 0.
+type Subtask struct {
+	Id       int    `json:"id" db:"id"`
+	ItemId   int    `json:"item_id" db:"item_id"`
+	Title    string `json:"title" db:"title" binding:"required"`
+	Done     bool   `json:"done" db:"done"`
+	Position int    `json:"position" db:"position"`
+}
+
+type UpdateSubtaskInput struct {
+	Title    *string `json:"title"`
+	Done     *bool   `json:"done"`
+	Position *int    `json:"position"`
+}
+
+func (i UpdateSubtaskInput) Validate() error {
+	if i.Title == nil && i.Done == nil && i.Position == nil {
+		return ErrEmptyUpdate
+	}
+
+	if i.Position != nil && *i.Position < 0 {
+		return ErrInvalidPosition
+	}
+
+	return nil
+}
@@ -1,20 +1,23 @@
 package todo
 
-import (
-	"errors"
-	"time"
-)
+import "time"
 
 type TodoList struct {
 	Id int `json:"id" db:"id"`
-	Title string `json:"title" db:"title" binding: "required"`
+	Title string `json:"title" db:"title" binding:"required"`
 	Description string `json:"description" db:"description"`
+	Role string `json:"role,omitempty" db:"role"`
+	// DeletedAt is set for lists in the trash
+	DeletedAt *time.Time `json:"deleted_at,omitempty" db:"deleted_at"`
+	// Version is incremented by every change of the list
+	Version int `json:"version" db:"version"`
 }
 
 type UserList struct {
 	Id int
 	UserId int
 	ListId int
+	Role string
 }
 
 type TodoItem struct{
@@ -22,7 +25,42 @@ type TodoItem struct{
 	Title string `json:"title" db:"title" binding:"required"`
 	Description string `json:"description" db:"description"`
 	Done bool `json:"done" db:"done"`
-	Deadline time.Time `json:"deadline"`
+	Deadline *time.Time `json:"deadline,omitempty" db:"deadline"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	RRule string `json:"rrule,omitempty" db:"rrule"`
+	SeriesId *int `json:"series_id,omitempty" db:"series_id"`
+	RecurrenceId *time.Time `json:"recurrence_id,omitempty" db:"recurrence_id"`
+	// AutoComplete marks the item done once all of its subtasks are done
+	AutoComplete bool `json:"auto_complete" db:"auto_complete"`
+	Priority Priority `json:"priority" db:"priority"`
+	// Progress is the percentage of done subtasks, nil for items without subtasks
+	Progress *int `json:"progress,omitempty" db:"progress"`
+	ListId int `json:"list_id,omitempty" db:"list_id"`
+	// Position orders the items of a list, see POST /api/items/:id/move
+	Position string `json:"position,omitempty" db:"position"`
+	// Labels are the labels the requesting user put on the item
+	Labels []Label `json:"labels,omitempty" db:"-"`
+	// DeletedAt is set for items in the trash
+	DeletedAt *time.Time `json:"deleted_at,omitempty" db:"deleted_at"`
+	// Version is incremented by every change of the item
+	Version int `json:"version" db:"version"`
+}
+
+// UserItem is an item together with one of the users who can access it.
+type UserItem struct {
+	UserId int `json:"-" db:"user_id"`
+	TodoItem
+}
+
+// CreateItemInput holds the fields a client sets on a new item. The series
+// of a recurring item is created from RRule, never taken from the request.
+type CreateItemInput struct {
+	Title string `json:"title" binding:"required"`
+	Description string `json:"description"`
+	Deadline *time.Time `json:"deadline"`
+	RRule string `json:"rrule"`
+	AutoComplete bool `json:"auto_complete"`
+	Priority Priority `json:"priority"`
 }
 
 type ListsItem struct{
@@ -34,12 +72,13 @@ type ListsItem struct{
 type UpdateListInput struct {
 	Title *string `json:"title"`
 	Description *string `json:"description"`
-	
+	// Version, when set, is the version the list must still have for the update to apply
+	Version *int `json:"-"`
 }
 
 func (i UpdateListInput) Validate() error {
 	if i.Title == nil && i.Description == nil {
-		return errors.New("update structure has no values")
+		return ErrEmptyUpdate
 	}
 
 	return nil
@@ -50,11 +89,41 @@ type UpdateItemInput struct {
 	Description *string `json:"description"`
 	Done *bool `json:"done"`
 	Deadline *time.Time `json:"deadline"`
+	RRule *string `json:"rrule"`
+	AutoComplete *bool `json:"auto_complete"`
+	Priority *Priority `json:"priority"`
+	// Scope tells whether an edit of a recurring item applies to this occurrence only or to the future ones too
+	Scope string `json:"scope"`
+	// Version, when set, is the version the item must still have for the update to apply
+	Version *int `json:"-"`
 }
 
 func (i UpdateItemInput) Validate() error {
-	if i.Title == nil && i.Description == nil && i.Done == nil{
-		return errors.New("update structure has no values")
+	if i.Title == nil && i.Description == nil && i.Done == nil && i.Deadline == nil && i.RRule == nil && i.AutoComplete == nil && i.Priority == nil {
+		return ErrEmptyUpdate
+	}
+
+	switch i.Scope {
+	case "", ScopeThis, ScopeFuture:
+	default:
+		return ErrInvalidScope
+	}
+
+	return nil
+}
+
+// MoveItemInput puts an item before or after another item of the target
+// list, which is the item's current list unless ListId is set. Without
+// anchors the item goes to the end of the list.
+type MoveItemInput struct {
+	ListId *int `json:"list_id"`
+	Before *int `json:"before"`
+	After *int `json:"after"`
+}
+
+func (i MoveItemInput) Validate() error {
+	if i.Before != nil && i.After != nil && *i.Before == *i.After {
+		return ErrInvalidAnchor
 	}
 
 	return nil
@@ -0,0 +1,15 @@
+package todo
+
+// Kinds of trashed entities, as used in /api/trash/:type/:id/restore.
+const (
+	TrashLists = "lists"
+	TrashItems = "items"
+)
+
+// Trash is what the user deleted from the lists they own and can still
+// restore. Items of a trashed list are restored together with the list and
+// are not listed on their own.
+type Trash struct {
+	Lists []TodoList `json:"lists"`
+	Items []TodoItem `json:"items"`
+}
@@ -1,8 +1,25 @@
 package todo
 
+import "time"
+
 type User struct {
-	Id       int    `json:"-" db:"id"`
-	Name     string `json:"name" binding:"required"`
-	Username string `json:"username" binding:"required"`
-	Password string `json:"password" binding:"required"`
-}
\ No newline at end of file
+	Id           int    `json:"-" db:"id"`
+	Name         string `json:"name" binding:"required"`
+	Username     string `json:"username" binding:"required"`
+	Password     string `json:"password" binding:"required"`
+	PasswordHash string `json:"-" db:"password_hash"`
+}
+
+type Tokens struct {
+	AccessToken  string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+type RefreshToken struct {
+	Id         int        `db:"id"`
+	UserId     int        `db:"user_id"`
+	TokenHash  string     `db:"token_hash"`
+	ExpiresAt  time.Time  `db:"expires_at"`
+	RevokedAt  *time.Time `db:"revoked_at"`
+	ReplacedBy *int       `db:"replaced_by"`
+}